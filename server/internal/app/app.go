@@ -6,19 +6,19 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"os"
 	"time"
 
 	"go.robinthrift.com/conveyor/internal/auth"
 	"go.robinthrift.com/conveyor/internal/control"
+	"go.robinthrift.com/conveyor/internal/ingress/adminv1"
 	appingress "go.robinthrift.com/conveyor/internal/ingress/app"
 	"go.robinthrift.com/conveyor/internal/ingress/authv1"
+	"go.robinthrift.com/conveyor/internal/ingress/jobsv1"
 	"go.robinthrift.com/conveyor/internal/ingress/memosv1"
 	"go.robinthrift.com/conveyor/internal/ingress/syncv1"
 	"go.robinthrift.com/conveyor/internal/jobs"
 	"go.robinthrift.com/conveyor/internal/server"
 	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
-	"go.robinthrift.com/conveyor/internal/storage/filesystem"
 )
 
 type App struct {
@@ -26,8 +26,9 @@ type App struct {
 	srv    *http.Server
 	db     *sqlite.SQLite
 
-	initSetup *initSetup
-	jobs      *jobs.System
+	initSetup     *initSetup
+	jobs          *jobs.System
+	apiTokenUsage *control.APITokenUsageTracker
 }
 
 func New(config Config) *App { //nolint:funlen
@@ -49,12 +50,18 @@ func New(config Config) *App { //nolint:funlen
 	accountRepo := sqlite.NewAccountRepo(db)
 	syncRepo := sqlite.NewSyncRepo(db)
 	authTokenRepo := sqlite.NewAuthTokenRepo(db)
+	totpRepo := sqlite.NewTOTPRepo(db)
 	apiTokenRepo := sqlite.NewAPITokenRepo(db)
 	jobRepo := sqlite.NewJobRepo(db)
+	usageRepo := sqlite.NewUsageRepo(db)
+	uploadRepo := sqlite.NewUploadRepo(db)
 
-	blobs := &filesystem.LocalFSBlobStorage{
-		BaseDir: config.Blobs.Dir,
-		TmpDir:  os.TempDir(),
+	blobs := newBlobStorage(config.Blobs)
+
+	syncConfig := control.SyncConfig{
+		ChangelogCompactionGracePeriod: config.Sync.ChangelogCompactionGracePeriod,
+		FullSyncRetainCount:            config.Sync.FullSyncRetainCount,
+		FullSyncRetainDuration:         config.Sync.FullSyncRetainDuration,
 	}
 
 	authConfig := control.AuthConfig{
@@ -65,22 +72,68 @@ func New(config Config) *App { //nolint:funlen
 		RefreshTokenValidDuration: config.RefreshTokenValidDuration,
 	}
 
-	accountCtrl := control.NewAccountController(db, accountRepo)
-	attachmentCtrl := control.NewAttachmentController(blobs)
-	syncCtrl := control.NewSyncController(db, syncRepo, accountCtrl, attachmentCtrl, blobs)
-	authCtrl := control.NewAuthController(authConfig, db, accountCtrl, authTokenRepo)
-	apiTokenCtrl := control.NewAPITokenController(authConfig, db, apiTokenRepo, authTokenRepo)
+	quotaConfig := control.QuotaConfig{
+		MaxSizeBytes: config.Quota.MaxSizeBytes,
+		MaxEntries:   config.Quota.MaxEntries,
+	}
+
+	accountCtrl := control.NewAccountController(db, accountRepo, blobs)
+	usageCtrl := control.NewUsageController(quotaConfig, usageRepo)
+	attachmentCtrl := control.NewAttachmentController(db, blobs, sqlite.NewAttachmentRepo(db), usageCtrl)
+
+	apiTokenUsage := control.NewAPITokenUsageTracker(control.APITokenUsageConfig{
+		FlushInterval:     config.APITokens.UsageFlushInterval,
+		RevokeUnusedAfter: config.APITokens.RevokeUnusedAfter,
+	}, db, apiTokenRepo)
+	authCtrl := control.NewAuthController(authConfig, db, accountCtrl, authTokenRepo, totpRepo, apiTokenUsage)
+
+	jobSystem := jobs.NewSystem(jobs.SystemConfig{
+		Workers:           config.Jobs.Workers,
+		Timeout:           config.Jobs.Timeout,
+		InstanceID:        config.Jobs.InstanceID,
+		LeaseDuration:     config.Jobs.LeaseDuration,
+		CancelGracePeriod: config.Jobs.CancelGracePeriod,
+	}, db, jobRepo, accountCtrl, time.Now, jobFuncs(
+		control.NewCompactChangelogJob(syncConfig, db, syncRepo, usageRepo),
+		control.NewCleanupFullSyncEntriesJob(syncConfig, db, syncRepo, blobs, usageRepo),
+		control.NewVerifyFullSyncEntriesJob(syncRepo, blobs),
+		control.NewCleanupExpiredUploadsJob(db, uploadRepo, blobs, usageRepo),
+		control.NewCleanupInvalidAuthTokensJob(authCtrl),
+		control.NewRecalculateUsageJob(db, usageRepo, blobs),
+	))
+	jobSystem.RegisterRecurring(recurringJobs(config.Jobs)...)
+
+	for kind, policy := range retryPolicies() {
+		jobSystem.RegisterRetryPolicy(kind, policy)
+	}
+
+	for kind, timeout := range jobTimeouts() {
+		jobSystem.RegisterTimeout(kind, timeout)
+	}
 
-	jobSystem := jobs.NewSystem(db, jobRepo, accountCtrl, time.Now, jobFuncs)
+	syncCtrl := control.NewSyncController(syncConfig, db, syncRepo, accountCtrl, attachmentCtrl, usageCtrl, blobs, jobSystem)
+	uploadCtrl := control.NewUploadController(control.UploadConfig{Expiry: config.Uploads.Expiry}, db, uploadRepo, blobs, syncCtrl, usageCtrl, jobSystem)
+	apiTokenCtrl := control.NewAPITokenController(authConfig, db, apiTokenRepo, authTokenRepo)
 
 	mux := http.NewServeMux()
 	srv := server.New(server.Config{Addr: config.Addr}, mux)
 
 	authv1.New(config.BasePath, mux, authCtrl, accountCtrl, apiTokenCtrl)
+	adminv1.New(config.BasePath, mux, authCtrl, accountCtrl, usageCtrl)
+	jobsv1.New(config.BasePath, mux, authCtrl, jobSystem)
 	syncv1.New(syncv1.RouterConfig{
-		BasePath: config.BasePath,
-	}, mux, syncCtrl, authCtrl, http.Dir(config.Blobs.Dir))
-	memosv1.New(config.BasePath, mux, syncCtrl, authCtrl)
+		BasePath:                config.BasePath,
+		MaxAttachmentSizeBytes:  config.Uploads.MaxAttachmentSizeBytes,
+		MaxFullSyncSizeBytes:    config.Uploads.MaxFullSyncSizeBytes,
+		MaxUploadChunkSizeBytes: config.Uploads.MaxChunkSizeBytes,
+		MaxDecompressionRatio:   config.Uploads.MaxDecompressionRatio,
+	}, mux, syncCtrl, usageCtrl, uploadCtrl, authCtrl, blobs)
+	memosv1.New(memosv1.RouterConfig{
+		BasePath:               config.BasePath,
+		MaxAttachmentSizeBytes: config.Uploads.MaxAttachmentSizeBytes,
+		MaxDecompressionRatio:  config.Uploads.MaxDecompressionRatio,
+		AttachmentContentTypes: config.Uploads.AttachmentContentTypes,
+	}, mux, syncCtrl, authCtrl)
 	appingress.New(config.BasePath, mux)
 
 	return &App{
@@ -92,7 +145,8 @@ func New(config Config) *App { //nolint:funlen
 			InitPassword: auth.PlaintextPassword(config.Init.Password),
 			Argon2params: argon2Params,
 		}, db, accountCtrl, authCtrl),
-		jobs: jobSystem,
+		jobs:          jobSystem,
+		apiTokenUsage: apiTokenUsage,
 	}
 }
 
@@ -112,6 +166,15 @@ func (a *App) Start(ctx context.Context) error {
 		return err
 	}
 
+	jobsStopped := make(chan struct{})
+
+	go func() {
+		a.jobs.Start(ctx)
+		close(jobsStopped)
+	}()
+
+	go a.apiTokenUsage.Start(ctx)
+
 	slog.InfoContext(ctx, fmt.Sprintf("starting server on %v", a.config.Addr))
 
 	err = a.srv.ListenAndServe()
@@ -119,6 +182,19 @@ func (a *App) Start(ctx context.Context) error {
 		return err
 	}
 
+	// the context is already cancelled when the server was stopped
+	err = a.apiTokenUsage.Flush(context.WithoutCancel(ctx))
+	if err != nil {
+		slog.ErrorContext(ctx, "error flushing api token usage", slog.Any("error", err))
+	}
+
+	// running jobs must record their outcome before the database is closed
+	select {
+	case <-jobsStopped:
+	case <-time.After(a.config.Jobs.ShutdownTimeout):
+		slog.ErrorContext(ctx, "timed out waiting for running jobs to stop")
+	}
+
 	return a.db.Close()
 }
 
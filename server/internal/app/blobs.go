@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage"
+	"go.robinthrift.com/conveyor/internal/storage/filesystem"
+	"go.robinthrift.com/conveyor/internal/storage/objectstorage"
+)
+
+type blobStorage interface {
+	storage.BlobReader
+	WriteBlob(ctx context.Context, accountID domain.AccountID, filepath string, content io.Reader) (int64, error)
+	OpenBlobTarget(ctx context.Context, accountID domain.AccountID, originalFilename string) (storage.BlobTarget, error)
+	RemoveBlob(ctx context.Context, accountID domain.AccountID, filepath string) error
+	RemoveAccountBlobs(ctx context.Context, accountID domain.AccountID) error
+	WalkAccountBlobs(ctx context.Context, accountID domain.AccountID, fn func(filepath string, info *storage.BlobInfo) error) error
+}
+
+func newBlobStorage(config Blobs) blobStorage {
+	if config.Backend == BlobsBackendS3 {
+		s3 := &objectstorage.S3BlobStorage{
+			Endpoint:        config.S3.Endpoint,
+			Region:          config.S3.Region,
+			Bucket:          config.S3.Bucket,
+			Prefix:          config.S3.Prefix,
+			AccessKeyID:     config.S3.AccessKeyID,
+			SecretAccessKey: config.S3.SecretAccessKey,
+			UsePathStyle:    config.S3.UsePathStyle,
+			PartSize:        config.S3.PartSize,
+			PresignExpiry:   config.S3.PresignExpiry,
+			TmpDir:          os.TempDir(),
+		}
+
+		return s3
+	}
+
+	return &filesystem.LocalFSBlobStorage{
+		BaseDir: config.Dir,
+		TmpDir:  os.TempDir(),
+	}
+}
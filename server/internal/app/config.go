@@ -3,12 +3,15 @@ package app
 
 import (
 	"errors"
+	"fmt"
 	"io/fs"
+	"net/url"
 	"os"
 	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/subosito/gotenv"
+	"go.robinthrift.com/conveyor/internal/jobs"
 	"go.robinthrift.com/conveyor/internal/tracing"
 	"go.robinthrift.com/conveyor/internal/version"
 )
@@ -23,9 +26,19 @@ type Config struct {
 
 	Argon2 Argon2 `envPrefix:"ARGON2_"`
 
+	Sync Sync `envPrefix:"SYNC_"`
+
+	Quota Quota `envPrefix:"QUOTA_"`
+
+	Uploads Uploads `envPrefix:"UPLOADS_"`
+
 	AccessTokenValidDuration  time.Duration `env:"ACCESS_TOKEN_VALID_DURATION"`
 	RefreshTokenValidDuration time.Duration `env:"REFRESH_TOKEN_VALID_DURATION"`
 
+	APITokens APITokens `envPrefix:"API_TOKENS_"`
+
+	Jobs Jobs `envPrefix:"JOBS_"`
+
 	Log Log `envPrefix:"LOG_"`
 
 	Tracing tracing.Config `envPrefix:"TRACING_"`
@@ -34,7 +47,26 @@ type Config struct {
 }
 
 type Blobs struct {
-	Dir string `env:"DIR"`
+	Backend string `env:"BACKEND"`
+	Dir     string `env:"DIR"`
+	S3      S3     `envPrefix:"S3_"`
+}
+
+const (
+	BlobsBackendFS = "fs"
+	BlobsBackendS3 = "s3"
+)
+
+type S3 struct {
+	Endpoint        string        `env:"ENDPOINT"`
+	Region          string        `env:"REGION"`
+	Bucket          string        `env:"BUCKET"`
+	Prefix          string        `env:"PREFIX"`
+	AccessKeyID     string        `env:"ACCESS_KEY_ID"`
+	SecretAccessKey string        `env:"SECRET_ACCESS_KEY"`
+	UsePathStyle    bool          `env:"USE_PATH_STYLE"`
+	PartSize        int64         `env:"PART_SIZE"`
+	PresignExpiry   time.Duration `env:"PRESIGN_EXPIRY"`
 }
 
 type Log struct {
@@ -57,6 +89,52 @@ type Argon2 struct {
 	Version int
 }
 
+type Sync struct {
+	ChangelogCompactionGracePeriod time.Duration `env:"CHANGELOG_COMPACTION_GRACE_PERIOD"`
+	FullSyncRetainCount            int           `env:"FULL_SYNC_RETAIN_COUNT"`
+	FullSyncRetainDuration         time.Duration `env:"FULL_SYNC_RETAIN_DURATION"`
+}
+
+// Quota limits the storage per account, 0 means unlimited.
+type Quota struct {
+	MaxSizeBytes int64 `env:"MAX_SIZE_BYTES"`
+	MaxEntries   int64 `env:"MAX_ENTRIES"`
+}
+
+// Uploads configures resumable uploads and limits the size of all uploads, 0 means unlimited.
+type Uploads struct {
+	Expiry                 time.Duration `env:"EXPIRY"`
+	MaxAttachmentSizeBytes int64         `env:"MAX_ATTACHMENT_SIZE_BYTES"`
+	MaxFullSyncSizeBytes   int64         `env:"MAX_FULL_SYNC_SIZE_BYTES"`
+	MaxChunkSizeBytes      int64         `env:"MAX_CHUNK_SIZE_BYTES"`
+	MaxDecompressionRatio  int64         `env:"MAX_DECOMPRESSION_RATIO"`
+	AttachmentContentTypes []string      `env:"ATTACHMENT_CONTENT_TYPES"`
+}
+
+type APITokens struct {
+	UsageFlushInterval time.Duration `env:"USAGE_FLUSH_INTERVAL"`
+	// RevokeUnusedAfter revokes API tokens that haven't been used for the duration, 0 disables the revocation.
+	RevokeUnusedAfter time.Duration `env:"REVOKE_UNUSED_AFTER"`
+}
+
+type Jobs struct {
+	Workers int           `env:"WORKERS"`
+	Timeout time.Duration `env:"TIMEOUT"`
+	// InstanceID must be unique for every instance sharing the database, defaults to the hostname with a random suffix.
+	InstanceID    string        `env:"INSTANCE_ID"`
+	LeaseDuration time.Duration `env:"LEASE_DURATION"`
+	// CancelGracePeriod is the time a cancelled job, e.g. after a timeout or when stopping, is given to return before
+	// it's abandoned.
+	CancelGracePeriod time.Duration `env:"CANCEL_GRACE_PERIOD"`
+	// ShutdownTimeout limits the time waiting for running jobs to record their outcome when stopping.
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT"`
+
+	// Schedules of the built-in recurring jobs, either as a cron expression or as `@every <duration>`.
+	// An empty schedule disables the job.
+	AuthTokenCleanupSchedule string `env:"AUTH_TOKEN_CLEANUP_SCHEDULE"`
+	FullSyncVerifySchedule   string `env:"FULL_SYNC_VERIFY_SCHEDULE"`
+}
+
 type Init struct {
 	Username string `env:"USERNAME"`
 	Password string `env:"PASSWORD"`
@@ -67,7 +145,13 @@ var defaultConfig = Config{
 	Addr:     ":8080",
 	BasePath: "/",
 	Blobs: Blobs{
-		Dir: "blobs",
+		Backend: BlobsBackendFS,
+		Dir:     "blobs",
+		S3: S3{
+			Region:        "us-east-1",
+			PartSize:      16 << 20, // 16MiB
+			PresignExpiry: time.Minute * 15,
+		},
 	},
 	Database: Database{
 		Path:      "conveyor.db",
@@ -80,10 +164,36 @@ var defaultConfig = Config{
 		Threads: 2,
 		Time:    1,
 	},
+	Sync: Sync{
+		ChangelogCompactionGracePeriod: time.Hour * 24 * 7,
+		FullSyncRetainCount:            5,
+		FullSyncRetainDuration:         time.Hour * 24 * 7,
+	},
+	Uploads: Uploads{
+		Expiry:                 time.Hour * 24,
+		MaxAttachmentSizeBytes: 100 << 20, // 100MiB
+		MaxFullSyncSizeBytes:   1 << 30,   // 1GiB
+		MaxChunkSizeBytes:      32 << 20,  // 32MiB
+		MaxDecompressionRatio:  100,
+	},
 
 	AccessTokenValidDuration:  time.Hour * 24,
 	RefreshTokenValidDuration: time.Hour * 24 * 30,
 
+	APITokens: APITokens{
+		UsageFlushInterval: time.Second * 30,
+	},
+
+	Jobs: Jobs{
+		Workers:                  2,
+		Timeout:                  time.Minute * 10,
+		LeaseDuration:            time.Minute,
+		CancelGracePeriod:        time.Second * 30,
+		ShutdownTimeout:          time.Minute,
+		AuthTokenCleanupSchedule: "@every 6h",
+		FullSyncVerifySchedule:   "@every 24h",
+	},
+
 	Log: Log{
 		Format: "json",
 		Level:  "info",
@@ -119,9 +229,60 @@ func ParseConfig(prefix string) (Config, error) {
 		return defaultConfig, err
 	}
 
+	err = validateBlobsConfig(config.Blobs)
+	if err != nil {
+		return defaultConfig, err
+	}
+
+	err = validateJobsConfig(config.Jobs)
+	if err != nil {
+		return defaultConfig, err
+	}
+
 	return config, nil
 }
 
+var ErrInvalidBlobsConfig = errors.New("invalid blobs config")
+
+func validateBlobsConfig(config Blobs) error {
+	switch config.Backend {
+	case BlobsBackendFS:
+		return nil
+	case BlobsBackendS3:
+		if config.S3.Bucket == "" {
+			return fmt.Errorf("%w: missing S3 bucket", ErrInvalidBlobsConfig)
+		}
+
+		endpoint, err := url.Parse(config.S3.Endpoint)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			return fmt.Errorf("%w: invalid S3 endpoint: %s", ErrInvalidBlobsConfig, config.S3.Endpoint)
+		}
+
+		if config.S3.PartSize < 5<<20 {
+			return fmt.Errorf("%w: S3 part size must be at least 5MiB", ErrInvalidBlobsConfig)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown backend: %s", ErrInvalidBlobsConfig, config.Backend)
+	}
+}
+
+func validateJobsConfig(config Jobs) error {
+	for _, spec := range []string{config.AuthTokenCleanupSchedule, config.FullSyncVerifySchedule} {
+		if spec == "" {
+			continue
+		}
+
+		_, err := jobs.ParseSchedule(spec)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func getEnvDefault(name string, d string) string {
 	s, ok := os.LookupEnv(name)
 	if !ok {
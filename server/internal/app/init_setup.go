@@ -56,6 +56,7 @@ func (isj *initSetup) run(ctx context.Context) error {
 	err = isj.authCtrl.CreateAccount(ctx, control.CreateAccountCmd{
 		Account: &domain.Account{
 			Username: isj.config.InitUsername,
+			IsAdmin:  true,
 			Password: domain.AccountPassword{
 				RequiresChange: true,
 			},
@@ -1,8 +1,72 @@
 package app
 
 import (
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/control"
 	"go.robinthrift.com/conveyor/internal/jobs"
 )
 
-//nolint:gochecknoglobals
-var jobFuncs = map[string]jobs.JobKindWithJSONData{}
+func jobFuncs(compactChangelogJob *control.CompactChangelogJob, cleanupFullSyncEntriesJob *control.CleanupFullSyncEntriesJob, verifyFullSyncEntriesJob *control.VerifyFullSyncEntriesJob, cleanupExpiredUploadsJob *control.CleanupExpiredUploadsJob, cleanupInvalidAuthTokensJob *control.CleanupInvalidAuthTokensJob, recalculateUsageJob *control.RecalculateUsageJob) map[string]jobs.JobKindWithJSONData {
+	return map[string]jobs.JobKindWithJSONData{
+		control.CompactChangelogJobKind:         jobs.NewJobKindWithJSONData(compactChangelogJob),
+		control.CleanupFullSyncEntriesJobKind:   jobs.NewJobKindWithJSONData(cleanupFullSyncEntriesJob),
+		control.VerifyFullSyncEntriesJobKind:    jobs.NewJobKindWithJSONData(verifyFullSyncEntriesJob),
+		control.CleanupExpiredUploadsJobKind:    jobs.NewJobKindWithJSONData(cleanupExpiredUploadsJob),
+		control.CleanupInvalidAuthTokensJobKind: jobs.NewJobKindWithJSONData(cleanupInvalidAuthTokensJob),
+		control.RecalculateUsageJobKind:         jobs.NewJobKindWithJSONData(recalculateUsageJob),
+	}
+}
+
+func recurringJobs(config Jobs) []jobs.RecurringJob {
+	return []jobs.RecurringJob{
+		{
+			Name:     "auth_token_cleanup",
+			Kind:     control.CleanupInvalidAuthTokensJobKind,
+			Data:     control.CleanupInvalidAuthTokensJobData{},
+			Schedule: mustParseSchedule(config.AuthTokenCleanupSchedule),
+		},
+		{
+			Name:     "full_sync_verify",
+			Kind:     control.VerifyFullSyncEntriesJobKind,
+			Data:     control.VerifyFullSyncEntriesJobData{},
+			Schedule: mustParseSchedule(config.FullSyncVerifySchedule),
+		},
+	}
+}
+
+//nolint:mnd // config values
+func retryPolicies() map[string]jobs.RetryPolicy {
+	return map[string]jobs.RetryPolicy{
+		// the cleanup runs regularly anyway, so a single retry is sufficient
+		control.CleanupInvalidAuthTokensJobKind: {
+			MaxAttempts:    2,
+			InitialBackoff: time.Minute * 5,
+			MaxBackoff:     time.Minute * 5,
+			Jitter:         jobs.DefaultRetryPolicy.Jitter,
+		},
+	}
+}
+
+//nolint:mnd // config values
+func jobTimeouts() map[string]time.Duration {
+	return map[string]time.Duration{
+		// verification reads every full sync snapshot of all accounts
+		control.VerifyFullSyncEntriesJobKind: time.Hour,
+	}
+}
+
+// mustParseSchedule returns nil for an empty spec, which disables the job. The specs are validated when parsing the
+// config.
+func mustParseSchedule(spec string) jobs.Schedule {
+	if spec == "" {
+		return nil
+	}
+
+	schedule, err := jobs.ParseSchedule(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	return schedule
+}
@@ -9,6 +9,7 @@ import (
 type ctxAccountKeyType string
 
 const ctxAccountKey = ctxAccountKeyType("ctxAccountKey")
+const ctxAPITokenScopesKey = ctxAccountKeyType("ctxAPITokenScopesKey")
 
 func CtxWithAccount(ctx context.Context, a *domain.Account) context.Context {
 	return context.WithValue(ctx, ctxAccountKey, a)
@@ -24,3 +25,14 @@ func AccountFromCtx(ctx context.Context) *domain.Account {
 
 	return a
 }
+
+// CtxWithAPITokenScopes restricts the request to the given scopes. Requests authenticated with session tokens have no
+// scopes in their context and are unrestricted.
+func CtxWithAPITokenScopes(ctx context.Context, scopes domain.APITokenScopes) context.Context {
+	return context.WithValue(ctx, ctxAPITokenScopesKey, scopes)
+}
+
+func APITokenScopesFromCtx(ctx context.Context) (domain.APITokenScopes, bool) {
+	scopes, ok := ctx.Value(ctxAPITokenScopesKey).(domain.APITokenScopes)
+	return scopes, ok
+}
@@ -2,6 +2,9 @@ package auth
 
 import (
 	"errors"
+	"fmt"
 )
 
 var ErrUnauthorized = errors.New("unauthorized")
+var ErrForbidden = errors.New("forbidden")
+var ErrMissingScope = fmt.Errorf("%w: api token is missing the required scope", ErrForbidden)
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // HMAC-SHA1 is the algorithm defined by RFC 6238 and supported by all authenticator apps
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TOTP codes follow RFC 6238 using the parameters supported by all common authenticator apps: HMAC-SHA1,
+// 6 digits and a period of 30 seconds.
+const (
+	totpSecretLen = 20
+	totpDigits    = 6
+	totpModulo    = 1_000_000
+	totpPeriod    = 30
+	// totpSkew is the number of steps a code may be off in either direction, to allow for clock drift.
+	totpSkew = 1
+)
+
+// recoveryCodeLen is the number of random bytes of a recovery code, which encode to 16 base32 characters.
+const recoveryCodeLen = 10
+
+//nolint:gochecknoglobals
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func NewTOTPSecret() ([]byte, error) {
+	secret := make([]byte, totpSecretLen)
+
+	_, err := rand.Read(secret)
+	if err != nil {
+		return nil, fmt.Errorf("error generating random value for totp secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// EncodeTOTPSecret encodes the secret as unpadded base32, which is the format authenticator apps expect when entering
+// the secret manually.
+func EncodeTOTPSecret(secret []byte) string {
+	return totpEncoding.EncodeToString(secret)
+}
+
+// TOTPKeyURI returns the otpauth URI of the secret, which authenticator apps can import, usually from a QR code.
+func TOTPKeyURI(issuer string, accountName string, secret []byte) string {
+	query := url.Values{}
+	query.Set("secret", EncodeTOTPSecret(secret))
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(totpDigits))
+	query.Set("period", strconv.Itoa(totpPeriod))
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// TOTPStep returns the time step t falls into.
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / totpPeriod
+}
+
+// TOTPCode returns the code of the time step as described in RFC 4226.
+func TOTPCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step)) //nolint:gosec // steps are never negative
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f                                    //nolint:mnd // dynamic truncation as defined by the RFC
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff //nolint:mnd // dynamic truncation as defined by the RFC
+
+	return fmt.Sprintf("%0*d", totpDigits, value%totpModulo)
+}
+
+// ValidateTOTP checks the code against the time steps around t and returns the matching step. Codes of steps up to
+// and including lastUsedStep are rejected, so every code can only be used once.
+func ValidateTOTP(secret []byte, code string, t time.Time, lastUsedStep int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := TOTPStep(t)
+
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(TOTPCode(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// NewRecoveryCodes generates n single-use recovery codes, formatted in groups of four characters for readability.
+func NewRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+
+	for i := range codes {
+		value := make([]byte, recoveryCodeLen)
+
+		_, err := rand.Read(value)
+		if err != nil {
+			return nil, fmt.Errorf("error generating random value for recovery code: %w", err)
+		}
+
+		encoded := strings.ToLower(totpEncoding.EncodeToString(value))
+
+		groups := make([]string, 0, len(encoded)/4) //nolint:mnd // false positive
+		for g := 0; g < len(encoded); g += 4 {
+			groups = append(groups, encoded[g:g+4])
+		}
+
+		codes[i] = strings.Join(groups, "-")
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode removes the formatting of a recovery code as entered by a user.
+// The second return value is false if the input can't be a recovery code.
+func NormalizeRecoveryCode(code string) (PlaintextPassword, bool) {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	decoded, err := totpEncoding.DecodeString(normalized)
+	if err != nil || len(decoded) != recoveryCodeLen {
+		return nil, false
+	}
+
+	return PlaintextPassword(normalized), true
+}
@@ -2,15 +2,21 @@ package control
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"go.robinthrift.com/conveyor/internal/auth"
 	"go.robinthrift.com/conveyor/internal/domain"
 	"go.robinthrift.com/conveyor/internal/storage/database"
 )
 
+var ErrCurrentAccount = errors.New("the current account can't be disabled or deleted")
+
 type AccountControl struct {
 	transactioner database.Transactioner
 	repo          AccountControlAccountRepo
+	blobs         AccountControlBlobStorage
 }
 
 type AccountControlAccountRepo interface {
@@ -19,13 +25,21 @@ type AccountControlAccountRepo interface {
 	Update(ctx context.Context, account *domain.Account) error
 	Get(ctx context.Context, id domain.AccountID) (*domain.Account, error)
 	GetByUsername(ctx context.Context, username string) (*domain.Account, error)
+	List(ctx context.Context, query domain.ListAccountsQuery) (*domain.AccountList, error)
+	Disable(ctx context.Context, id domain.AccountID, disabledAt time.Time) error
+	Enable(ctx context.Context, id domain.AccountID) error
+	Delete(ctx context.Context, id domain.AccountID) error
 
 	GetAccountKeyByName(ctx context.Context, accountID domain.AccountID, name string) (*domain.AccountKey, error)
 	CreateAccountKey(ctx context.Context, key *domain.AccountKey) error
 }
 
-func NewAccountController(transactioner database.Transactioner, repo AccountControlAccountRepo) *AccountControl {
-	return &AccountControl{transactioner: transactioner, repo: repo}
+type AccountControlBlobStorage interface {
+	RemoveAccountBlobs(ctx context.Context, accountID domain.AccountID) error
+}
+
+func NewAccountController(transactioner database.Transactioner, repo AccountControlAccountRepo, blobs AccountControlBlobStorage) *AccountControl {
+	return &AccountControl{transactioner: transactioner, repo: repo, blobs: blobs}
 }
 
 func (ac *AccountControl) Get(ctx context.Context, id domain.AccountID) (*domain.Account, error) {
@@ -54,6 +68,88 @@ func (ac *AccountControl) Update(ctx context.Context, account *domain.Account) e
 	return ac.repo.Update(ctx, account)
 }
 
+func (ac *AccountControl) List(ctx context.Context, query domain.ListAccountsQuery) (*domain.AccountList, error) {
+	_, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ac.repo.List(ctx, query)
+}
+
+// Disable prevents the account from logging in or using any of its tokens, without deleting any data.
+func (ac *AccountControl) Disable(ctx context.Context, id domain.AccountID) error {
+	admin, err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if admin.ID == id {
+		return ErrCurrentAccount
+	}
+
+	return ac.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		account, err := ac.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if account.IsDisabled() {
+			return nil
+		}
+
+		return ac.repo.Disable(ctx, id, time.Now())
+	})
+}
+
+func (ac *AccountControl) Enable(ctx context.Context, id domain.AccountID) error {
+	_, err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	return ac.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		_, err := ac.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return ac.repo.Enable(ctx, id)
+	})
+}
+
+// Delete deletes the account including its tokens, keys, sync data and blobs. The blobs are removed last,
+// so a failure rolls back the database changes and the deletion can simply be retried.
+func (ac *AccountControl) Delete(ctx context.Context, id domain.AccountID) error {
+	admin, err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if admin.ID == id {
+		return ErrCurrentAccount
+	}
+
+	return ac.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		_, err := ac.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = ac.repo.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = ac.blobs.RemoveAccountBlobs(ctx, id)
+		if err != nil {
+			return fmt.Errorf("error removing blobs of account %d: %w", id, err)
+		}
+
+		return nil
+	})
+}
+
 func (ac *AccountControl) CountAccounts(ctx context.Context) (int64, error) {
 	return ac.repo.CountAccounts(ctx)
 }
@@ -77,3 +173,16 @@ func (ac *AccountControl) CreateAccountKey(ctx context.Context, key *domain.Acco
 
 	return ac.repo.CreateAccountKey(ctx, key)
 }
+
+func requireAdmin(ctx context.Context) (*domain.Account, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, auth.ErrUnauthorized
+	}
+
+	if !account.IsAdmin {
+		return nil, auth.ErrForbidden
+	}
+
+	return account, nil
+}
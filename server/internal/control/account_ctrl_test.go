@@ -0,0 +1,284 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
+	"go.robinthrift.com/conveyor/internal/storage/filesystem"
+	"go.robinthrift.com/conveyor/internal/testhelper"
+)
+
+func TestAccountControl_Delete(t *testing.T) {
+	t.Parallel()
+
+	setup := setupAccountCtrlTest(t)
+	userCtx := auth.CtxWithAccount(t.Context(), setup.user)
+
+	token, err := setup.authCtrl.CreateAuthTokenUsingCredentials(t.Context(), CreateAuthTokenUsingCredentialsCmd{
+		Username:        setup.user.Username,
+		PlaintextPasswd: auth.PlaintextPassword("user"),
+	})
+	require.NoError(t, err)
+
+	apiToken, err := setup.apiTokenCtrl.CreateAPIToken(userCtx, CreateAPITokenCmd{Name: "api", Scopes: domain.APITokenScopes{domain.APITokenScopeSyncRead}, ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	err = setup.accountCtrl.CreateAccountKey(userCtx, &domain.AccountKey{Name: domain.PrimaryAccountKeyName, Type: "agev1", Data: []byte(publicKey)})
+	require.NoError(t, err)
+
+	err = setup.syncRepo.CreateSyncClient(t.Context(), &domain.SyncClient{ID: "client_a", AccountID: setup.user.ID})
+	require.NoError(t, err)
+
+	err = setup.syncRepo.CreateChangelogEntries(t.Context(), []domain.ChangelogEntry{{AccountID: setup.user.ID, SyncClientID: "client_a", Data: []byte("a")}})
+	require.NoError(t, err)
+
+	_, err = setup.blobs.WriteBlob(t.Context(), setup.user.ID, "a/b/test.txt", bytes.NewReader([]byte("content")))
+	require.NoError(t, err)
+
+	t.Run("Forbidden for non-admins", func(t *testing.T) {
+		err := setup.accountCtrl.Delete(userCtx, setup.admin.ID)
+		require.ErrorIs(t, err, auth.ErrForbidden)
+	})
+
+	t.Run("Current account can't be deleted", func(t *testing.T) {
+		err := setup.accountCtrl.Delete(setup.adminCtx, setup.admin.ID)
+		require.ErrorIs(t, err, ErrCurrentAccount)
+	})
+
+	err = setup.accountCtrl.Delete(setup.adminCtx, setup.user.ID)
+	require.NoError(t, err)
+
+	_, err = setup.accountCtrl.Get(t.Context(), setup.user.ID)
+	require.ErrorIs(t, err, domain.ErrAccountNotFound)
+
+	_, err = setup.authCtrl.GetAccountForAuthToken(t.Context(), token.Plaintext)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	apiTokenValue, err := auth.NewPlaintextAuthTokenValueFromString(apiToken)
+	require.NoError(t, err)
+
+	_, err = setup.authCtrl.GetAccountForAuthToken(t.Context(), *apiTokenValue)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = setup.accountRepo.GetAccountKeyByName(t.Context(), setup.user.ID, domain.PrimaryAccountKeyName)
+	require.ErrorIs(t, err, domain.ErrAccountKeyNotFound)
+
+	clients, err := setup.syncRepo.ListSyncClients(t.Context(), setup.user.ID, domain.ListSyncClientsQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, clients.Items)
+
+	entries, err := setup.syncRepo.ListChangelogEntries(t.Context(), domain.ListChangelogEntriesQuery{AccountID: setup.user.ID})
+	require.NoError(t, err)
+	assert.Empty(t, entries.Items)
+
+	assert.NoDirExists(t, filepath.Join(setup.blobDir, setup.user.ID.String()))
+
+	_, err = os.Stat(filepath.Join(setup.blobDir, setup.user.ID.String(), "a/b/test.txt"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	err = setup.accountCtrl.Delete(setup.adminCtx, setup.user.ID)
+	require.ErrorIs(t, err, domain.ErrAccountNotFound)
+}
+
+func TestAccountControl_Disable(t *testing.T) {
+	t.Parallel()
+
+	setup := setupAccountCtrlTest(t)
+
+	token, err := setup.authCtrl.CreateAuthTokenUsingCredentials(t.Context(), CreateAuthTokenUsingCredentialsCmd{
+		Username:        setup.user.Username,
+		PlaintextPasswd: auth.PlaintextPassword("user"),
+	})
+	require.NoError(t, err)
+
+	err = setup.accountCtrl.Disable(auth.CtxWithAccount(t.Context(), setup.user), setup.admin.ID)
+	require.ErrorIs(t, err, auth.ErrForbidden)
+
+	err = setup.accountCtrl.Disable(setup.adminCtx, setup.admin.ID)
+	require.ErrorIs(t, err, ErrCurrentAccount)
+
+	err = setup.accountCtrl.Disable(setup.adminCtx, setup.user.ID)
+	require.NoError(t, err)
+
+	_, err = setup.authCtrl.GetAccountForAuthToken(t.Context(), token.Plaintext)
+	require.ErrorIs(t, err, domain.ErrAccountDisabled)
+
+	_, err = setup.authCtrl.CreateAuthTokenUsingCredentials(t.Context(), CreateAuthTokenUsingCredentialsCmd{
+		Username:        setup.user.Username,
+		PlaintextPasswd: auth.PlaintextPassword("user"),
+	})
+	require.ErrorIs(t, err, domain.ErrAccountDisabled)
+
+	_, err = setup.authCtrl.CreateAuthTokenUsingRefreshToken(t.Context(), CreateAuthTokenUsingRefreshTokenCmd{
+		PlaintextRefreshToken: token.RefreshPlaintext,
+	})
+	require.ErrorIs(t, err, domain.ErrAccountDisabled)
+
+	err = setup.accountCtrl.Enable(setup.adminCtx, setup.user.ID)
+	require.NoError(t, err)
+
+	account, err := setup.authCtrl.GetAccountForAuthToken(t.Context(), token.Plaintext)
+	require.NoError(t, err)
+	assert.False(t, account.IsDisabled())
+}
+
+func TestAccountControl_List(t *testing.T) {
+	t.Parallel()
+
+	setup := setupAccountCtrlTest(t)
+
+	_, err := setup.accountCtrl.List(auth.CtxWithAccount(t.Context(), setup.user), domain.ListAccountsQuery{})
+	require.ErrorIs(t, err, auth.ErrForbidden)
+
+	page, err := setup.accountCtrl.List(setup.adminCtx, domain.ListAccountsQuery{PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, setup.admin.ID, page.Items[0].ID)
+	assert.True(t, page.Items[0].IsAdmin)
+	require.NotNil(t, page.Next)
+
+	page, err = setup.accountCtrl.List(setup.adminCtx, domain.ListAccountsQuery{PageSize: 1, PageAfter: page.Next})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, setup.user.ID, page.Items[0].ID)
+	assert.False(t, page.Items[0].IsAdmin)
+}
+
+func TestAuthController_ForcePasswordReset(t *testing.T) {
+	t.Parallel()
+
+	setup := setupAccountCtrlTest(t)
+	userCtx := auth.CtxWithAccount(t.Context(), setup.user)
+
+	token, err := setup.authCtrl.CreateAuthTokenUsingCredentials(t.Context(), CreateAuthTokenUsingCredentialsCmd{
+		Username:        setup.user.Username,
+		PlaintextPasswd: auth.PlaintextPassword("user"),
+	})
+	require.NoError(t, err)
+
+	apiToken, err := setup.apiTokenCtrl.CreateAPIToken(userCtx, CreateAPITokenCmd{Name: "api", Scopes: domain.APITokenScopes{domain.APITokenScopeSyncRead}, ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	err = setup.authCtrl.ForcePasswordReset(userCtx, ForcePasswordResetCmd{AccountID: setup.user.ID, PlaintextPasswd: auth.PlaintextPassword("temp")})
+	require.ErrorIs(t, err, auth.ErrForbidden)
+
+	err = setup.authCtrl.ForcePasswordReset(setup.adminCtx, ForcePasswordResetCmd{AccountID: setup.user.ID})
+	require.ErrorIs(t, err, ErrPasswordEmpty)
+
+	err = setup.authCtrl.ForcePasswordReset(setup.adminCtx, ForcePasswordResetCmd{AccountID: setup.user.ID, PlaintextPasswd: auth.PlaintextPassword("temp")})
+	require.NoError(t, err)
+
+	_, err = setup.authCtrl.GetAccountForAuthToken(t.Context(), token.Plaintext)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	apiTokenValue, err := auth.NewPlaintextAuthTokenValueFromString(apiToken)
+	require.NoError(t, err)
+
+	_, err = setup.authCtrl.GetAccountForAuthToken(t.Context(), *apiTokenValue)
+	require.NoError(t, err)
+
+	_, err = setup.authCtrl.CreateAuthTokenUsingCredentials(t.Context(), CreateAuthTokenUsingCredentialsCmd{
+		Username:        setup.user.Username,
+		PlaintextPasswd: auth.PlaintextPassword("user"),
+	})
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = setup.authCtrl.CreateAuthTokenUsingCredentials(t.Context(), CreateAuthTokenUsingCredentialsCmd{
+		Username:        setup.user.Username,
+		PlaintextPasswd: auth.PlaintextPassword("temp"),
+	})
+	require.ErrorIs(t, err, ErrRequiresPasswordChange)
+}
+
+type accountCtrlTestSetup struct {
+	authCtrl      *AuthController
+	accountCtrl   *AccountControl
+	apiTokenCtrl  *APITokenController
+	apiTokenUsage *APITokenUsageTracker
+	accountRepo   *sqlite.AccountRepo
+	syncRepo      *sqlite.SyncRepo
+	blobs         *filesystem.LocalFSBlobStorage
+	blobDir       string
+	admin         *domain.Account
+	adminCtx      context.Context
+	user          *domain.Account
+}
+
+func setupAccountCtrlTest(t *testing.T) accountCtrlTestSetup {
+	t.Helper()
+
+	db := testhelper.NewInMemTestSQLite(t)
+
+	blobDir := t.TempDir()
+	blobs := &filesystem.LocalFSBlobStorage{
+		BaseDir: blobDir,
+		TmpDir:  t.TempDir(),
+	}
+
+	config := AuthConfig{
+		Argon2Params:              auth.Argon2Params{KeyLen: 32, Memory: 8192, Threads: 2, Time: 1},
+		AuthTokenLength:           32,
+		AccessTokenValidDuration:  time.Hour,
+		RefreshTokenValidDuration: time.Hour * 2,
+	}
+
+	accountRepo := sqlite.NewAccountRepo(db)
+	authTokenRepo := sqlite.NewAuthTokenRepo(db)
+	totpRepo := sqlite.NewTOTPRepo(db)
+	apiTokenRepo := sqlite.NewAPITokenRepo(db)
+	apiTokenUsage := NewAPITokenUsageTracker(APITokenUsageConfig{}, db, apiTokenRepo)
+	accountCtrl := NewAccountController(db, accountRepo, blobs)
+	authCtrl := NewAuthController(config, db, accountCtrl, authTokenRepo, totpRepo, apiTokenUsage)
+
+	createAccount := func(username string, isAdmin bool) *domain.Account {
+		err := authCtrl.CreateAccount(t.Context(), CreateAccountCmd{
+			Account:         &domain.Account{Username: username, IsAdmin: isAdmin},
+			PlaintextPasswd: auth.PlaintextPassword(username + "_init"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = authCtrl.ChangeAccountPassword(t.Context(), ChangeAccountPasswordCmd{
+			Username:            username,
+			CurrPasswdPlaintext: auth.PlaintextPassword(username + "_init"),
+			NewPasswdPlaintext:  auth.PlaintextPassword(username),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		account, err := accountRepo.GetByUsername(t.Context(), username)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return account
+	}
+
+	admin := createAccount("admin", true)
+	user := createAccount("user", false)
+
+	return accountCtrlTestSetup{
+		authCtrl:      authCtrl,
+		accountCtrl:   accountCtrl,
+		apiTokenCtrl:  NewAPITokenController(config, db, apiTokenRepo, authTokenRepo),
+		apiTokenUsage: apiTokenUsage,
+		accountRepo:   accountRepo,
+		syncRepo:      sqlite.NewSyncRepo(db),
+		blobs:         blobs,
+		blobDir:       blobDir,
+		admin:         admin,
+		adminCtx:      auth.CtxWithAccount(t.Context(), admin),
+		user:          user,
+	}
+}
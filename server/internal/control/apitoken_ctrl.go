@@ -57,6 +57,7 @@ func (atc *APITokenController) ListAPITokens(ctx context.Context, query ListAPIT
 
 type CreateAPITokenCmd struct {
 	Name      string
+	Scopes    domain.APITokenScopes
 	ExpiresAt time.Time
 }
 
@@ -66,6 +67,25 @@ func (atc *APITokenController) CreateAPIToken(ctx context.Context, cmd CreateAPI
 		return "", auth.ErrUnauthorized
 	}
 
+	if len(cmd.Scopes) == 0 {
+		return "", fmt.Errorf("%w: at least one scope is required", domain.ErrInvalidAPITokenScope)
+	}
+
+	for _, scope := range cmd.Scopes {
+		if _, err := domain.ParseAPITokenScope(string(scope)); err != nil {
+			return "", err
+		}
+	}
+
+	// API tokens can't be used to create tokens with more permissions than they have themselves
+	if ownScopes, ok := auth.APITokenScopesFromCtx(ctx); ok {
+		for _, scope := range cmd.Scopes {
+			if !ownScopes.Has(scope) {
+				return "", fmt.Errorf("%w: %s", auth.ErrMissingScope, scope)
+			}
+		}
+	}
+
 	plaintextToken, err := auth.NewPlaintextAuthToken(atc.config.AuthTokenLength, cmd.ExpiresAt, cmd.ExpiresAt)
 	if err != nil {
 		return "", fmt.Errorf("error creating api token value: %w", err)
@@ -87,6 +107,7 @@ func (atc *APITokenController) CreateAPIToken(ctx context.Context, cmd CreateAPI
 			AccountID: account.ID,
 			TokenID:   int64(id),
 			Name:      cmd.Name,
+			Scopes:    cmd.Scopes,
 			ExpiresAt: cmd.ExpiresAt,
 		}
 
@@ -0,0 +1,77 @@
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/domain"
+)
+
+func TestAPITokenController_CreateAPIToken(t *testing.T) {
+	t.Parallel()
+
+	setup := setupAccountCtrlTest(t)
+	ctx := auth.CtxWithAccount(t.Context(), setup.user)
+
+	t.Run("Scopes Required", func(t *testing.T) {
+		_, err := setup.apiTokenCtrl.CreateAPIToken(ctx, CreateAPITokenCmd{Name: "no scopes", ExpiresAt: time.Now().Add(time.Hour)})
+		require.ErrorIs(t, err, domain.ErrInvalidAPITokenScope)
+
+		_, err = setup.apiTokenCtrl.CreateAPIToken(ctx, CreateAPITokenCmd{Name: "invalid", Scopes: domain.APITokenScopes{"memos:delete"}, ExpiresAt: time.Now().Add(time.Hour)})
+		require.ErrorIs(t, err, domain.ErrInvalidAPITokenScope)
+	})
+
+	t.Run("Scopes Are Stored", func(t *testing.T) {
+		plaintext, err := setup.apiTokenCtrl.CreateAPIToken(ctx, CreateAPITokenCmd{
+			Name:      "memos",
+			Scopes:    domain.APITokenScopes{domain.APITokenScopeMemosCreate, domain.APITokenScopeAttachmentsCreate},
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+
+		token, err := setup.apiTokenCtrl.GetAPITokenByName(ctx, "memos")
+		require.NoError(t, err)
+		assert.Equal(t, domain.APITokenScopes{domain.APITokenScopeMemosCreate, domain.APITokenScopeAttachmentsCreate}, token.Scopes)
+
+		value, err := auth.NewPlaintextAuthTokenValueFromString(plaintext)
+		require.NoError(t, err)
+
+		account, apiToken, err := setup.authCtrl.GetAccountAndAPITokenForAuthToken(t.Context(), *value)
+		require.NoError(t, err)
+		assert.Equal(t, setup.user.ID, account.ID)
+		assert.Equal(t, token.Scopes, apiToken.Scopes)
+	})
+
+	t.Run("Session Tokens Are Unrestricted", func(t *testing.T) {
+		token, err := setup.authCtrl.CreateAuthTokenUsingCredentials(t.Context(), CreateAuthTokenUsingCredentialsCmd{
+			Username:        setup.user.Username,
+			PlaintextPasswd: auth.PlaintextPassword("user"),
+		})
+		require.NoError(t, err)
+
+		_, apiToken, err := setup.authCtrl.GetAccountAndAPITokenForAuthToken(t.Context(), token.Plaintext)
+		require.NoError(t, err)
+		assert.Nil(t, apiToken)
+	})
+
+	t.Run("No Privilege Escalation", func(t *testing.T) {
+		scopedCtx := auth.CtxWithAPITokenScopes(ctx, domain.APITokenScopes{domain.APITokenScopeTokensManage, domain.APITokenScopeSyncRead})
+
+		_, err := setup.apiTokenCtrl.CreateAPIToken(scopedCtx, CreateAPITokenCmd{
+			Name:      "escalated",
+			Scopes:    domain.APITokenScopes{domain.APITokenScopeSyncWrite},
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.ErrorIs(t, err, auth.ErrMissingScope)
+
+		_, err = setup.apiTokenCtrl.CreateAPIToken(scopedCtx, CreateAPITokenCmd{
+			Name:      "subset",
+			Scopes:    domain.APITokenScopes{domain.APITokenScopeSyncRead},
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,178 @@
+package control
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database"
+)
+
+type APITokenUsageConfig struct {
+	FlushInterval time.Duration
+	// RevokeUnusedAfter revokes API tokens that haven't been used for the duration, 0 disables the revocation.
+	RevokeUnusedAfter time.Duration
+}
+
+// APITokenUsageTracker buffers the usage of API tokens in memory and writes it in batches, so requests don't have to
+// wait for a write.
+type APITokenUsageTracker struct {
+	config        APITokenUsageConfig
+	transactioner database.Transactioner
+	repo          APITokenUsageTrackerRepo
+	now           func() time.Time
+
+	mu      sync.Mutex
+	pending map[domain.APITokenID]*domain.APITokenUsage
+}
+
+type APITokenUsageTrackerRepo interface {
+	RecordAPITokenUsage(ctx context.Context, usage *domain.APITokenUsage) error
+	DeleteUnusedAPITokens(ctx context.Context, unusedSince time.Time) (int64, error)
+}
+
+func NewAPITokenUsageTracker(config APITokenUsageConfig, transactioner database.Transactioner, repo APITokenUsageTrackerRepo) *APITokenUsageTracker {
+	return &APITokenUsageTracker{
+		config:        config,
+		transactioner: transactioner,
+		repo:          repo,
+		now:           time.Now,
+		pending:       map[domain.APITokenID]*domain.APITokenUsage{},
+	}
+}
+
+const defaultAPITokenUsageFlushInterval = 30 * time.Second
+const revokeUnusedAPITokensInterval = time.Hour
+
+// Start periodically flushes the buffered usage and revokes unused API tokens until the context is cancelled.
+// Buffered usage isn't flushed on cancellation, [APITokenUsageTracker.Flush] must be called explicitly.
+func (t *APITokenUsageTracker) Start(ctx context.Context) {
+	flushInterval := t.config.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = defaultAPITokenUsageFlushInterval
+	}
+
+	flushTicker := time.NewTicker(flushInterval)
+	defer flushTicker.Stop()
+
+	var revoke <-chan time.Time
+
+	if t.config.RevokeUnusedAfter > 0 {
+		revokeTicker := time.NewTicker(revokeUnusedAPITokensInterval)
+		defer revokeTicker.Stop()
+
+		revoke = revokeTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-flushTicker.C:
+			err := t.Flush(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "error flushing api token usage", slog.Any("error", err))
+			}
+		case <-revoke:
+			err := t.RevokeUnused(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "error revoking unused api tokens", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// Record only updates the buffered usage of the token.
+func (t *APITokenUsageTracker) Record(id domain.APITokenID, ip string, userAgent string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.merge(&domain.APITokenUsage{
+		APITokenID:   id,
+		LastUsedAt:   t.now(),
+		IP:           ip,
+		UserAgent:    userAgent,
+		RequestCount: 1,
+	})
+}
+
+// Flush writes the buffered usage in a single transaction. If the write fails the usage is buffered again, so no
+// requests are lost.
+func (t *APITokenUsageTracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = map[domain.APITokenID]*domain.APITokenUsage{}
+	t.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := t.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		for _, usage := range pending {
+			err := t.repo.RecordAPITokenUsage(ctx, usage)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.mu.Lock()
+		for _, usage := range pending {
+			t.merge(usage)
+		}
+		t.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// RevokeUnused deletes all API tokens that haven't been used for the configured duration.
+func (t *APITokenUsageTracker) RevokeUnused(ctx context.Context) error {
+	if t.config.RevokeUnusedAfter <= 0 {
+		return nil
+	}
+
+	// write the pending usage first, so recently used tokens aren't revoked
+	err := t.Flush(ctx)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := database.InTransaction(ctx, t.transactioner, func(ctx context.Context) (int64, error) {
+		return t.repo.DeleteUnusedAPITokens(ctx, t.now().Add(-t.config.RevokeUnusedAfter))
+	})
+	if err != nil {
+		return err
+	}
+
+	if revoked != 0 {
+		slog.InfoContext(ctx, "revoked unused api tokens", slog.Int64("count", revoked))
+	}
+
+	return nil
+}
+
+// merge must only be called while holding the lock.
+func (t *APITokenUsageTracker) merge(usage *domain.APITokenUsage) {
+	existing, ok := t.pending[usage.APITokenID]
+	if !ok {
+		t.pending[usage.APITokenID] = usage
+
+		return
+	}
+
+	existing.RequestCount += usage.RequestCount
+
+	if usage.LastUsedAt.After(existing.LastUsedAt) {
+		existing.LastUsedAt = usage.LastUsedAt
+		existing.IP = usage.IP
+		existing.UserAgent = usage.UserAgent
+	}
+}
@@ -0,0 +1,107 @@
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/domain"
+)
+
+func TestAPITokenUsageTracker_Flush(t *testing.T) {
+	t.Parallel()
+
+	setup := setupAccountCtrlTest(t)
+	ctx := auth.CtxWithAccount(t.Context(), setup.user)
+
+	plaintext, err := setup.apiTokenCtrl.CreateAPIToken(ctx, CreateAPITokenCmd{
+		Name:      "usage",
+		Scopes:    domain.APITokenScopes{domain.APITokenScopeSyncRead},
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	value, err := auth.NewPlaintextAuthTokenValueFromString(plaintext)
+	require.NoError(t, err)
+
+	_, apiToken, err := setup.authCtrl.GetAccountAndAPITokenForAuthToken(t.Context(), *value)
+	require.NoError(t, err)
+
+	assert.True(t, apiToken.LastUsedAt.IsZero())
+	assert.Equal(t, int64(0), apiToken.RequestCount)
+
+	setup.authCtrl.RecordAPITokenUsage(apiToken, "192.0.2.1", "first")
+	setup.authCtrl.RecordAPITokenUsage(apiToken, "192.0.2.2", "second")
+	setup.authCtrl.RecordAPITokenUsage(apiToken, "192.0.2.3", "third")
+
+	token, err := setup.apiTokenCtrl.GetAPITokenByName(ctx, "usage")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), token.RequestCount, "usage must not be written before flushing")
+
+	require.NoError(t, setup.apiTokenUsage.Flush(t.Context()))
+
+	token, err = setup.apiTokenCtrl.GetAPITokenByName(ctx, "usage")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), token.RequestCount)
+	assert.False(t, token.LastUsedAt.IsZero())
+	assert.Equal(t, "192.0.2.3", token.LastUsedIP)
+	assert.Equal(t, "third", token.LastUsedUserAgent)
+
+	setup.authCtrl.RecordAPITokenUsage(apiToken, "192.0.2.4", "fourth")
+	require.NoError(t, setup.apiTokenUsage.Flush(t.Context()))
+
+	token, err = setup.apiTokenCtrl.GetAPITokenByName(ctx, "usage")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), token.RequestCount)
+	assert.Equal(t, "192.0.2.4", token.LastUsedIP)
+}
+
+func TestAPITokenUsageTracker_RevokeUnused(t *testing.T) {
+	t.Parallel()
+
+	setup := setupAccountCtrlTest(t)
+	ctx := auth.CtxWithAccount(t.Context(), setup.user)
+
+	createToken := func(name string) auth.PlaintextAuthTokenValue {
+		plaintext, err := setup.apiTokenCtrl.CreateAPIToken(ctx, CreateAPITokenCmd{
+			Name:      name,
+			Scopes:    domain.APITokenScopes{domain.APITokenScopeSyncRead},
+			ExpiresAt: time.Now().Add(time.Hour * 24 * 365),
+		})
+		require.NoError(t, err)
+
+		value, err := auth.NewPlaintextAuthTokenValueFromString(plaintext)
+		require.NoError(t, err)
+
+		return *value
+	}
+
+	unused := createToken("unused")
+	used := createToken("used")
+
+	setup.apiTokenUsage.config.RevokeUnusedAfter = time.Hour * 24 * 30
+	setup.apiTokenUsage.now = func() time.Time {
+		return time.Now().Add(time.Hour * 24 * 31)
+	}
+
+	_, apiToken, err := setup.authCtrl.GetAccountAndAPITokenForAuthToken(t.Context(), used)
+	require.NoError(t, err)
+	setup.authCtrl.RecordAPITokenUsage(apiToken, "192.0.2.1", "test")
+
+	require.NoError(t, setup.apiTokenUsage.RevokeUnused(t.Context()))
+
+	_, _, err = setup.authCtrl.GetAccountAndAPITokenForAuthToken(t.Context(), unused)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = setup.apiTokenCtrl.GetAPITokenByName(ctx, "unused")
+	require.ErrorIs(t, err, domain.ErrAPITokenNotFound)
+
+	_, _, err = setup.authCtrl.GetAccountAndAPITokenForAuthToken(t.Context(), used)
+	require.NoError(t, err)
+
+	token, err := setup.apiTokenCtrl.GetAPITokenByName(ctx, "used")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), token.RequestCount)
+}
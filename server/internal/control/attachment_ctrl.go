@@ -2,25 +2,47 @@ package control
 
 import (
 	"context"
+	"errors"
 	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
 
 	"go.robinthrift.com/conveyor/internal/auth"
 	"go.robinthrift.com/conveyor/internal/domain"
 	"go.robinthrift.com/conveyor/internal/storage"
+	"go.robinthrift.com/conveyor/internal/storage/database"
 )
 
+// reservedBlobDirs are the top-level directories of an account's blob storage that are managed by the server itself,
+// e.g. full sync snapshots and upload chunks, and must not be accessed as attachments.
+//
+//nolint:gochecknoglobals
+var reservedBlobDirs = []string{"dbs", "uploads"}
+
 type AttachmentController struct {
-	blobs AttachmentControllerBlobStorage
+	transactioner database.Transactioner
+	blobs         AttachmentControllerBlobStorage
+	repo          AttachmentControllerRepo
+	usage         *UsageController
 }
 
 type AttachmentControllerBlobStorage interface {
-	WriteBlob(accountID domain.AccountID, filepath string, content io.Reader) (int64, error)
-	OpenBlobTarget(accountID domain.AccountID, originalFilename string) (storage.BlobTarget, error)
-	RemoveBlob(accountID domain.AccountID, filepath string) error
+	WriteBlob(ctx context.Context, accountID domain.AccountID, filepath string, content io.Reader) (int64, error)
+	OpenBlobTarget(ctx context.Context, accountID domain.AccountID, originalFilename string) (storage.BlobTarget, error)
+	StatBlob(ctx context.Context, accountID domain.AccountID, filepath string) (*storage.BlobInfo, error)
+	RemoveBlob(ctx context.Context, accountID domain.AccountID, filepath string) error
+}
+
+type AttachmentControllerRepo interface {
+	GetAttachmentBySha256(ctx context.Context, accountID domain.AccountID, sha256 []byte) (*domain.Attachment, error)
+	CreateAttachment(ctx context.Context, attachment *domain.Attachment) error
+	DeleteAttachmentsByFilepath(ctx context.Context, accountID domain.AccountID, filepath string) error
 }
 
-func NewAttachmentController(blobStorage AttachmentControllerBlobStorage) *AttachmentController {
-	return &AttachmentController{blobStorage}
+func NewAttachmentController(transactioner database.Transactioner, blobStorage AttachmentControllerBlobStorage, repo AttachmentControllerRepo, usage *UsageController) *AttachmentController {
+	return &AttachmentController{transactioner, blobStorage, repo, usage}
 }
 
 type StoreAttachmentCmd struct {
@@ -34,11 +56,32 @@ func (ac *AttachmentController) StoreAttachment(ctx context.Context, cmd StoreAt
 		return auth.ErrUnauthorized
 	}
 
-	_, err := ac.blobs.WriteBlob(account.ID, cmd.Filepath, cmd.Content)
+	filepath, ok := cleanAttachmentFilepath(strings.TrimPrefix(cmd.Filepath, "/"))
+	if !ok {
+		return domain.ErrInvalidAttachmentFilepath
+	}
+
+	content, err := ac.usage.LimitReader(ctx, cmd.Content)
+	if err != nil {
+		return err
+	}
+
+	prevSizeBytes, err := ac.blobSize(ctx, account.ID, filepath)
 	if err != nil {
 		return err
 	}
 
+	sizeBytes, err := ac.blobs.WriteBlob(ctx, account.ID, filepath, content)
+	if err != nil {
+		return err
+	}
+
+	err = ac.usage.Reserve(ctx, sizeBytes-prevSizeBytes, 0)
+	if err != nil {
+		// the previous content has been overwritten already, so its size is released as well
+		return errors.Join(err, ac.blobs.RemoveBlob(ctx, account.ID, filepath), ac.usage.Release(ctx, prevSizeBytes, 0))
+	}
+
 	return nil
 }
 
@@ -48,5 +91,116 @@ func (ac *AttachmentController) OpenBlobTarget(ctx context.Context, originalFile
 		return nil, auth.ErrUnauthorized
 	}
 
-	return ac.blobs.OpenBlobTarget(account.ID, originalFilename)
+	return ac.blobs.OpenBlobTarget(ctx, account.ID, originalFilename)
+}
+
+// GetAttachmentBySha256 looks up a previously stored attachment by the SHA-256 hash of its plaintext content.
+func (ac *AttachmentController) GetAttachmentBySha256(ctx context.Context, sha256 []byte) (*domain.Attachment, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, auth.ErrUnauthorized
+	}
+
+	return ac.repo.GetAttachmentBySha256(ctx, account.ID, sha256)
+}
+
+func (ac *AttachmentController) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return auth.ErrUnauthorized
+	}
+
+	attachment.AccountID = account.ID
+	attachment.Filepath = path.Clean(strings.TrimPrefix(attachment.Filepath, "/"))
+
+	return ac.repo.CreateAttachment(ctx, attachment)
+}
+
+type DeleteAttachmentCmd struct {
+	Filepath string
+}
+
+// DeleteAttachment deletes the attachment's blob together with its metadata. The blob is removed last in the
+// transaction, so the metadata and usage are only changed if the blob was removed successfully.
+func (ac *AttachmentController) DeleteAttachment(ctx context.Context, cmd DeleteAttachmentCmd) error {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return auth.ErrUnauthorized
+	}
+
+	filepath, ok := cleanAttachmentFilepath(cmd.Filepath)
+	if !ok {
+		return domain.ErrInvalidAttachmentFilepath
+	}
+
+	info, err := ac.blobs.StatBlob(ctx, account.ID, filepath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return domain.ErrAttachmentNotFound
+		}
+
+		return err
+	}
+
+	return ac.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		err := ac.repo.DeleteAttachmentsByFilepath(ctx, account.ID, filepath)
+		if err != nil {
+			return err
+		}
+
+		err = ac.usage.Release(ctx, info.SizeBytes, 0)
+		if err != nil {
+			return err
+		}
+
+		err = ac.blobs.RemoveBlob(ctx, account.ID, filepath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return domain.ErrAttachmentNotFound
+			}
+
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (ac *AttachmentController) blobSize(ctx context.Context, accountID domain.AccountID, filepath string) (int64, error) {
+	info, err := ac.blobs.StatBlob(ctx, accountID, filepath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return info.SizeBytes, nil
+}
+
+// cleanAttachmentFilepath normalises the filepath and rejects all paths that would escape the account's blob directory
+// or point into one of the [reservedBlobDirs].
+func cleanAttachmentFilepath(filepath string) (string, bool) {
+	if filepath == "" || path.IsAbs(filepath) {
+		return "", false
+	}
+
+	for _, segment := range strings.Split(filepath, "/") {
+		if segment == ".." {
+			return "", false
+		}
+	}
+
+	cleaned := path.Clean(filepath)
+	if cleaned == "." {
+		return "", false
+	}
+
+	dir, _, _ := strings.Cut(cleaned, "/")
+	if slices.Contains(reservedBlobDirs, dir) {
+		return "", false
+	}
+
+	return cleaned, true
 }
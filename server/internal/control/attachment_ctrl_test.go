@@ -0,0 +1,62 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
+	"go.robinthrift.com/conveyor/internal/storage/filesystem"
+	"go.robinthrift.com/conveyor/internal/testhelper"
+)
+
+func TestAttachmentController_DeleteAttachment_RemoveBlobFailed(t *testing.T) {
+	t.Parallel()
+
+	db := testhelper.NewInMemTestSQLite(t)
+	ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+	err := sqlite.NewAccountRepo(db).Create(t.Context(), &domain.Account{Username: t.Name(), Password: domain.AccountPassword{Password: []byte("1234"), Salt: []byte("1234")}})
+	require.NoError(t, err)
+
+	blobs := &failingRemoveBlobStorage{LocalFSBlobStorage: &filesystem.LocalFSBlobStorage{BaseDir: t.TempDir(), TmpDir: t.TempDir()}}
+	attachmentRepo := sqlite.NewAttachmentRepo(db)
+	usageCtrl := NewUsageController(QuotaConfig{}, sqlite.NewUsageRepo(db))
+	attachmentCtrl := NewAttachmentController(db, blobs, attachmentRepo, usageCtrl)
+
+	content := []byte("0123456789")
+	hash := sha256.Sum256(content)
+
+	err = attachmentCtrl.StoreAttachment(ctx, StoreAttachmentCmd{Filepath: "a/b/test.txt", Content: bytes.NewReader(content)})
+	require.NoError(t, err)
+
+	err = attachmentCtrl.CreateAttachment(ctx, &domain.Attachment{ID: "test", Sha256: hash[:], Filepath: "a/b/test.txt", SizeBytes: int64(len(content))})
+	require.NoError(t, err)
+
+	err = attachmentCtrl.DeleteAttachment(ctx, DeleteAttachmentCmd{Filepath: "a/b/test.txt"})
+	require.ErrorIs(t, err, errRemoveBlobFailed)
+
+	attachment, err := attachmentCtrl.GetAttachmentBySha256(ctx, hash[:])
+	require.NoError(t, err)
+	assert.Equal(t, "a/b/test.txt", attachment.Filepath)
+
+	report, err := usageCtrl.GetUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), report.Usage.SizeBytes)
+}
+
+var errRemoveBlobFailed = errors.New("remove blob failed")
+
+type failingRemoveBlobStorage struct {
+	*filesystem.LocalFSBlobStorage
+}
+
+func (*failingRemoveBlobStorage) RemoveBlob(context.Context, domain.AccountID, string) error {
+	return errRemoveBlobFailed
+}
@@ -16,23 +16,45 @@ var ErrInvalidCredentials = errors.New("invalid credentials")
 var ErrPasswordEmpty = errors.New("password must not be empty")
 var ErrInitialPasswordEmpty = errors.New("initial password must not be empty")
 var ErrRequiresPasswordChange = errors.New("password change required")
+var ErrOTPRequired = errors.New("one-time password required")
+var ErrInvalidOTP = fmt.Errorf("%w: invalid one-time password", ErrInvalidCredentials)
+var ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+var ErrTOTPNotEnrolled = errors.New("two-factor authentication is not enrolled")
+
+const totpIssuer = "Conveyor"
+const numRecoveryCodes = 10
 
 type AuthController struct {
 	config        AuthConfig
 	transactioner database.Transactioner
 	accountCtrl   *AccountControl
 	authTokenRepo AuthControllerAuthTokenRepo
+	totpRepo      AuthControllerTOTPRepo
+	apiTokenUsage *APITokenUsageTracker
 }
 
 type AuthControllerAuthTokenRepo interface {
 	GetAuthToken(ctx context.Context, value auth.AuthTokenValue) (*auth.AuthToken, error)
 	GetAuthTokenByRefreshValue(ctx context.Context, refreshValue auth.AuthTokenValue) (*auth.AuthToken, error)
+	GetAPITokenForAuthToken(ctx context.Context, id auth.AuthTokenID) (*domain.APIToken, error)
 	CreateAuthToken(ctx context.Context, token *auth.AuthToken) (auth.AuthTokenID, error)
 	InvalidateAuthToken(ctx context.Context, value auth.AuthTokenValue) error
+	InvalidateAccountSessionTokens(ctx context.Context, accountID domain.AccountID) error
 	MarkExpiredAuthTokensAsInvalid(ctx context.Context) error
 	DeleteInvalidTokens(ctx context.Context) error
 }
 
+type AuthControllerTOTPRepo interface {
+	GetAccountTOTP(ctx context.Context, accountID domain.AccountID) (*domain.AccountTOTP, error)
+	UpsertAccountTOTP(ctx context.Context, totp *domain.AccountTOTP) error
+	EnableAccountTOTP(ctx context.Context, accountID domain.AccountID, step int64, enabledAt time.Time) error
+	UseAccountTOTPStep(ctx context.Context, accountID domain.AccountID, step int64) (bool, error)
+	DeleteAccountTOTP(ctx context.Context, accountID domain.AccountID) error
+	ListUnusedRecoveryCodes(ctx context.Context, accountID domain.AccountID) ([]*domain.AccountRecoveryCode, error)
+	ReplaceRecoveryCodes(ctx context.Context, accountID domain.AccountID, codes []*domain.AccountRecoveryCode) error
+	UseRecoveryCode(ctx context.Context, id domain.AccountRecoveryCodeID) (bool, error)
+}
+
 type AuthConfig struct {
 	Argon2Params              auth.Argon2Params
 	AuthTokenLength           uint
@@ -40,31 +62,66 @@ type AuthConfig struct {
 	RefreshTokenValidDuration time.Duration
 }
 
-func NewAuthController(config AuthConfig, transactioner database.Transactioner, accountCtrl *AccountControl, authTokenRepo AuthControllerAuthTokenRepo) *AuthController {
-	return &AuthController{config, transactioner, accountCtrl, authTokenRepo}
+func NewAuthController(config AuthConfig, transactioner database.Transactioner, accountCtrl *AccountControl, authTokenRepo AuthControllerAuthTokenRepo, totpRepo AuthControllerTOTPRepo, apiTokenUsage *APITokenUsageTracker) *AuthController {
+	return &AuthController{config, transactioner, accountCtrl, authTokenRepo, totpRepo, apiTokenUsage}
 }
 
 func (ac *AuthController) GetAccountForAuthToken(ctx context.Context, plaintextToken auth.PlaintextAuthTokenValue) (*domain.Account, error) {
+	account, _, err := ac.GetAccountAndAPITokenForAuthToken(ctx, plaintextToken)
+	return account, err
+}
+
+// GetAccountAndAPITokenForAuthToken returns the API token alongside the account, if the token is an API token.
+// The API token of session tokens is nil, as they are unrestricted.
+func (ac *AuthController) GetAccountAndAPITokenForAuthToken(ctx context.Context, plaintextToken auth.PlaintextAuthTokenValue) (*domain.Account, *domain.APIToken, error) {
 	token, err := ac.authTokenRepo.GetAuthToken(ctx, auth.AuthTokenValue(plaintextToken.Encrypt(ac.config.Argon2Params)))
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
 	}
 
 	account, err := ac.accountCtrl.Get(ctx, token.AccountID)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
 	}
 
-	return account, nil
+	if account.IsDisabled() {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, domain.ErrAccountDisabled)
+	}
+
+	apiToken, err := ac.authTokenRepo.GetAPITokenForAuthToken(ctx, token.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAPITokenNotFound) {
+			return account, nil, nil
+		}
+
+		return nil, nil, err
+	}
+
+	return account, apiToken, nil
+}
+
+// RecordAPITokenUsage is called for every request made with an API token. The usage is buffered and written in batches.
+func (ac *AuthController) RecordAPITokenUsage(apiToken *domain.APIToken, ip string, userAgent string) {
+	ac.apiTokenUsage.Record(apiToken.ID, ip, userAgent)
 }
 
 type CreateAuthTokenUsingCredentialsCmd struct {
 	Username        string
 	PlaintextPasswd auth.PlaintextPassword
+	// OTP is either a TOTP code or a recovery code and is required once the account has enabled TOTP.
+	OTP string
 }
 
 func (ac *AuthController) CreateAuthTokenUsingCredentials(ctx context.Context, cmd CreateAuthTokenUsingCredentialsCmd) (*auth.PlaintextAuthToken, error) {
-	account, err := ac.getAccountForCredentials(ctx, getAccountForCredentialsQuery(cmd))
+	account, err := ac.getAccountForCredentials(ctx, getAccountForCredentialsQuery{
+		Username:        cmd.Username,
+		PlaintextPasswd: cmd.PlaintextPasswd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating auth token: %w", err)
+	}
+
+	err = ac.checkOTP(ctx, account, cmd.OTP)
 	if err != nil {
 		return nil, fmt.Errorf("error creating auth token: %w", err)
 	}
@@ -109,6 +166,10 @@ func (ac *AuthController) CreateAuthTokenUsingRefreshToken(ctx context.Context,
 		return nil, err
 	}
 
+	if account.IsDisabled() {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, domain.ErrAccountDisabled)
+	}
+
 	return database.InTransaction(ctx, ac.transactioner, func(ctx context.Context) (*auth.PlaintextAuthToken, error) {
 		err := ac.authTokenRepo.InvalidateAuthToken(ctx, token.Value)
 		if err != nil {
@@ -153,6 +214,15 @@ func (ac *AuthController) CreateAccount(ctx context.Context, cmd CreateAccountCm
 			return err
 		}
 
+		_, err = ac.accountCtrl.GetByUsername(ctx, cmd.Account.Username)
+		if err == nil {
+			return fmt.Errorf("%w: %s", domain.ErrAccountExists, cmd.Account.Username)
+		}
+
+		if !errors.Is(err, domain.ErrAccountNotFound) {
+			return err
+		}
+
 		hash, salt, err := auth.EncryptPassword(cmd.PlaintextPasswd, ac.config.Argon2Params)
 		if err != nil {
 			return err
@@ -167,10 +237,61 @@ func (ac *AuthController) CreateAccount(ctx context.Context, cmd CreateAccountCm
 				Password:       hash,
 				RequiresChange: true,
 			},
+			IsAdmin: cmd.Account.IsAdmin,
 		})
 	})
 }
 
+type ForcePasswordResetCmd struct {
+	AccountID       domain.AccountID
+	PlaintextPasswd auth.PlaintextPassword
+}
+
+// ForcePasswordReset replaces the account's password with a temporary one, which must be changed on the next login,
+// and logs out all sessions of the account. API tokens stay valid.
+func (ac *AuthController) ForcePasswordReset(ctx context.Context, cmd ForcePasswordResetCmd) error {
+	_, err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.PlaintextPasswd) == 0 {
+		return ErrPasswordEmpty
+	}
+
+	return ac.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		account, err := ac.accountCtrl.Get(ctx, cmd.AccountID)
+		if err != nil {
+			return err
+		}
+
+		params, err := ac.config.Argon2Params.ToJSONString()
+		if err != nil {
+			return err
+		}
+
+		hash, salt, err := auth.EncryptPassword(cmd.PlaintextPasswd, ac.config.Argon2Params)
+		if err != nil {
+			return err
+		}
+
+		account.Password = domain.AccountPassword{
+			Algorithm:      "argon2",
+			Params:         params,
+			Salt:           salt,
+			Password:       hash,
+			RequiresChange: true,
+		}
+
+		err = ac.accountCtrl.Update(ctx, account)
+		if err != nil {
+			return err
+		}
+
+		return ac.authTokenRepo.InvalidateAccountSessionTokens(ctx, account.ID)
+	})
+}
+
 type getAccountForCredentialsQuery struct {
 	Username        string
 	PlaintextPasswd auth.PlaintextPassword
@@ -186,15 +307,13 @@ func (ac *AuthController) getAccountForCredentials(ctx context.Context, query ge
 		return nil, err
 	}
 
-	passwordMatch, err := auth.CheckPassword(query.PlaintextPasswd, account.Password.Password, account.Password.Salt, []byte(account.Password.Params))
+	err = checkAccountPassword(ctx, account, query.PlaintextPasswd)
 	if err != nil {
-		slog.ErrorContext(ctx, "error comparing account password", slog.Any("error", err), slog.String("username", query.Username))
-
-		return nil, ErrInvalidCredentials
+		return nil, err
 	}
 
-	if !passwordMatch {
-		return nil, ErrInvalidCredentials
+	if account.IsDisabled() {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, domain.ErrAccountDisabled)
 	}
 
 	return account, nil
@@ -204,6 +323,9 @@ type ChangeAccountPasswordCmd struct {
 	Username            string
 	CurrPasswdPlaintext auth.PlaintextPassword
 	NewPasswdPlaintext  auth.PlaintextPassword
+	// OTP is either a TOTP code or a recovery code and is required once the account has enabled TOTP, unless the
+	// account is already authenticated.
+	OTP string
 }
 
 func (ac *AuthController) ChangeAccountPassword(ctx context.Context, cmd ChangeAccountPasswordCmd) error {
@@ -219,21 +341,20 @@ func (ac *AuthController) ChangeAccountPassword(ctx context.Context, cmd ChangeA
 		if err != nil {
 			return auth.ErrUnauthorized
 		}
+
+		err = ac.checkOTP(ctx, account, cmd.OTP)
+		if err != nil {
+			return err
+		}
 	}
 
 	if account == nil {
 		return auth.ErrUnauthorized
 	}
 
-	passwordMatch, err := auth.CheckPassword(cmd.CurrPasswdPlaintext, account.Password.Password, account.Password.Salt, []byte(account.Password.Params))
+	err = checkAccountPassword(ctx, account, cmd.CurrPasswdPlaintext)
 	if err != nil {
-		slog.ErrorContext(ctx, "error comparing account password", slog.Any("error", err), slog.String("username", account.Username))
-
-		return ErrInvalidCredentials
-	}
-
-	if !passwordMatch {
-		return ErrInvalidCredentials
+		return err
 	}
 
 	params, err := ac.config.Argon2Params.ToJSONString()
@@ -264,16 +385,256 @@ func (ac *AuthController) ChangeAccountPassword(ctx context.Context, cmd ChangeA
 	return nil
 }
 
-func (ac *AuthController) CleanupInvalidTokens(ctx context.Context) error {
-	err := ac.authTokenRepo.MarkExpiredAuthTokensAsInvalid(ctx)
+type EnrollTOTPCmd struct {
+	CurrPasswdPlaintext auth.PlaintextPassword
+}
+
+type TOTPEnrollment struct {
+	// Secret is the base32 encoded secret for entering it into an authenticator app manually.
+	Secret string
+	// KeyURI is the otpauth URI of the secret, usually shown as a QR code.
+	KeyURI string
+}
+
+// EnrollTOTP creates a new TOTP secret for the current account, which is only enabled once it's confirmed using
+// [AuthController.ConfirmTOTP]. Enrolling again replaces an unconfirmed secret.
+func (ac *AuthController) EnrollTOTP(ctx context.Context, cmd EnrollTOTPCmd) (*TOTPEnrollment, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, auth.ErrUnauthorized
+	}
+
+	err := checkAccountPassword(ctx, account, cmd.CurrPasswdPlaintext)
 	if err != nil {
-		return fmt.Errorf("error cleaning up invalid tokens: error marking exipired tokens as invalid: %w", err)
+		return nil, err
 	}
 
-	err = ac.authTokenRepo.DeleteInvalidTokens(ctx)
+	return database.InTransaction(ctx, ac.transactioner, func(ctx context.Context) (*TOTPEnrollment, error) {
+		existing, err := ac.totpRepo.GetAccountTOTP(ctx, account.ID)
+		if err != nil && !errors.Is(err, domain.ErrAccountTOTPNotFound) {
+			return nil, err
+		}
+
+		if existing != nil && existing.IsEnabled() {
+			return nil, ErrTOTPAlreadyEnabled
+		}
+
+		secret, err := auth.NewTOTPSecret()
+		if err != nil {
+			return nil, err
+		}
+
+		err = ac.totpRepo.UpsertAccountTOTP(ctx, &domain.AccountTOTP{AccountID: account.ID, Secret: secret})
+		if err != nil {
+			return nil, err
+		}
+
+		return &TOTPEnrollment{
+			Secret: auth.EncodeTOTPSecret(secret),
+			KeyURI: auth.TOTPKeyURI(totpIssuer, account.Username, secret),
+		}, nil
+	})
+}
+
+type ConfirmTOTPCmd struct {
+	OTP string
+}
+
+// ConfirmTOTP enables TOTP for the current account, if the code matches the enrolled secret. The returned recovery
+// codes are only stored hashed, so they can't be retrieved again.
+func (ac *AuthController) ConfirmTOTP(ctx context.Context, cmd ConfirmTOTPCmd) ([]string, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, auth.ErrUnauthorized
+	}
+
+	return database.InTransaction(ctx, ac.transactioner, func(ctx context.Context) ([]string, error) {
+		totp, err := ac.totpRepo.GetAccountTOTP(ctx, account.ID)
+		if err != nil {
+			if errors.Is(err, domain.ErrAccountTOTPNotFound) {
+				return nil, ErrTOTPNotEnrolled
+			}
+
+			return nil, err
+		}
+
+		if totp.IsEnabled() {
+			return nil, ErrTOTPAlreadyEnabled
+		}
+
+		now := time.Now()
+
+		step, ok := auth.ValidateTOTP(totp.Secret, cmd.OTP, now, totp.LastUsedStep)
+		if !ok {
+			return nil, ErrInvalidOTP
+		}
+
+		err = ac.totpRepo.EnableAccountTOTP(ctx, account.ID, step, now)
+		if err != nil {
+			return nil, err
+		}
+
+		plaintextCodes, err := auth.NewRecoveryCodes(numRecoveryCodes)
+		if err != nil {
+			return nil, err
+		}
+
+		params, err := ac.config.Argon2Params.ToJSONString()
+		if err != nil {
+			return nil, err
+		}
+
+		codes := make([]*domain.AccountRecoveryCode, len(plaintextCodes))
+		for i, plaintext := range plaintextCodes {
+			normalized, _ := auth.NormalizeRecoveryCode(plaintext)
+
+			hash, salt, err := auth.EncryptPassword(normalized, ac.config.Argon2Params)
+			if err != nil {
+				return nil, err
+			}
+
+			codes[i] = &domain.AccountRecoveryCode{Params: params, Salt: salt, Code: hash}
+		}
+
+		err = ac.totpRepo.ReplaceRecoveryCodes(ctx, account.ID, codes)
+		if err != nil {
+			return nil, err
+		}
+
+		return plaintextCodes, nil
+	})
+}
+
+type DisableTOTPCmd struct {
+	CurrPasswdPlaintext auth.PlaintextPassword
+}
+
+// DisableTOTP removes the TOTP secret and the recovery codes of the current account.
+func (ac *AuthController) DisableTOTP(ctx context.Context, cmd DisableTOTPCmd) error {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return auth.ErrUnauthorized
+	}
+
+	err := checkAccountPassword(ctx, account, cmd.CurrPasswdPlaintext)
 	if err != nil {
-		return fmt.Errorf("error cleaning up invalid tokens: error deleting invalid tokens: %w", err)
+		return err
+	}
+
+	return ac.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		_, err := ac.totpRepo.GetAccountTOTP(ctx, account.ID)
+		if err != nil {
+			if errors.Is(err, domain.ErrAccountTOTPNotFound) {
+				return ErrTOTPNotEnrolled
+			}
+
+			return err
+		}
+
+		return ac.totpRepo.DeleteAccountTOTP(ctx, account.ID)
+	})
+}
+
+// checkOTP verifies the second factor of accounts with enabled TOTP. Accepted codes are marked as used, so each code
+// can only be used once.
+func (ac *AuthController) checkOTP(ctx context.Context, account *domain.Account, otp string) error {
+	totp, err := ac.totpRepo.GetAccountTOTP(ctx, account.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountTOTPNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if !totp.IsEnabled() {
+		return nil
+	}
+
+	if otp == "" {
+		return ErrOTPRequired
+	}
+
+	return ac.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		if step, ok := auth.ValidateTOTP(totp.Secret, otp, time.Now(), totp.LastUsedStep); ok {
+			used, err := ac.totpRepo.UseAccountTOTPStep(ctx, account.ID, step)
+			if err != nil {
+				return err
+			}
+
+			if !used {
+				return ErrInvalidOTP
+			}
+
+			return nil
+		}
+
+		// only hash the input against the recovery codes if it looks like one, as hashing is expensive
+		recoveryCode, ok := auth.NormalizeRecoveryCode(otp)
+		if !ok {
+			return ErrInvalidOTP
+		}
+
+		codes, err := ac.totpRepo.ListUnusedRecoveryCodes(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, code := range codes {
+			match, err := auth.CheckPassword(recoveryCode, code.Code, code.Salt, []byte(code.Params))
+			if err != nil {
+				slog.ErrorContext(ctx, "error comparing recovery code", slog.Any("error", err), slog.String("username", account.Username))
+
+				continue
+			}
+
+			if !match {
+				continue
+			}
+
+			used, err := ac.totpRepo.UseRecoveryCode(ctx, code.ID)
+			if err != nil {
+				return err
+			}
+
+			if !used {
+				return ErrInvalidOTP
+			}
+
+			return nil
+		}
+
+		return ErrInvalidOTP
+	})
+}
+
+func checkAccountPassword(ctx context.Context, account *domain.Account, plaintextPasswd auth.PlaintextPassword) error {
+	passwordMatch, err := auth.CheckPassword(plaintextPasswd, account.Password.Password, account.Password.Salt, []byte(account.Password.Params))
+	if err != nil {
+		slog.ErrorContext(ctx, "error comparing account password", slog.Any("error", err), slog.String("username", account.Username))
+
+		return ErrInvalidCredentials
+	}
+
+	if !passwordMatch {
+		return ErrInvalidCredentials
 	}
 
 	return nil
 }
+
+func (ac *AuthController) CleanupInvalidTokens(ctx context.Context) error {
+	return ac.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		err := ac.authTokenRepo.MarkExpiredAuthTokensAsInvalid(ctx)
+		if err != nil {
+			return fmt.Errorf("error cleaning up invalid tokens: error marking exipired tokens as invalid: %w", err)
+		}
+
+		err = ac.authTokenRepo.DeleteInvalidTokens(ctx)
+		if err != nil {
+			return fmt.Errorf("error cleaning up invalid tokens: error deleting invalid tokens: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -1,6 +1,7 @@
 package control
 
 import (
+	"encoding/base32"
 	"testing"
 	"time"
 
@@ -79,6 +80,100 @@ func TestAuthController_CreateAuthTokenUsingRefreshToken(t *testing.T) {
 	require.ErrorIs(t, err, ErrInvalidCredentials)
 }
 
+func TestAuthController_TOTP(t *testing.T) {
+	t.Parallel()
+
+	authCtrl := setupAuthController(t)
+	passwd := auth.PlaintextPassword(t.Name())
+
+	account, err := authCtrl.accountCtrl.GetByUsername(t.Context(), t.Name())
+	require.NoError(t, err)
+
+	ctx := auth.CtxWithAccount(t.Context(), account)
+
+	login := func(otp string) error {
+		_, err := authCtrl.CreateAuthTokenUsingCredentials(t.Context(), CreateAuthTokenUsingCredentialsCmd{
+			Username:        t.Name(),
+			PlaintextPasswd: passwd,
+			OTP:             otp,
+		})
+
+		return err
+	}
+
+	_, err = authCtrl.EnrollTOTP(ctx, EnrollTOTPCmd{CurrPasswdPlaintext: auth.PlaintextPassword("incorrect password")})
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	enrollment, err := authCtrl.EnrollTOTP(ctx, EnrollTOTPCmd{CurrPasswdPlaintext: passwd})
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.KeyURI, "otpauth://totp/Conveyor:")
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(enrollment.Secret)
+	require.NoError(t, err)
+
+	// unconfirmed enrollments don't require a one-time password
+	require.NoError(t, login(""))
+
+	_, err = authCtrl.ConfirmTOTP(ctx, ConfirmTOTPCmd{OTP: "000000"})
+	require.ErrorIs(t, err, ErrInvalidOTP)
+
+	step := auth.TOTPStep(time.Now())
+
+	recoveryCodes, err := authCtrl.ConfirmTOTP(ctx, ConfirmTOTPCmd{OTP: auth.TOTPCode(secret, step)})
+	require.NoError(t, err)
+	assert.Len(t, recoveryCodes, numRecoveryCodes)
+
+	_, err = authCtrl.EnrollTOTP(ctx, EnrollTOTPCmd{CurrPasswdPlaintext: passwd})
+	require.ErrorIs(t, err, ErrTOTPAlreadyEnabled)
+
+	t.Run("Missing OTP", func(t *testing.T) {
+		require.ErrorIs(t, login(""), ErrOTPRequired)
+	})
+
+	t.Run("Invalid OTP", func(t *testing.T) {
+		require.ErrorIs(t, login("000000"), ErrInvalidCredentials)
+	})
+
+	t.Run("OTP", func(t *testing.T) {
+		// the code used for the confirmation can't be reused
+		require.ErrorIs(t, login(auth.TOTPCode(secret, step)), ErrInvalidCredentials)
+
+		require.NoError(t, login(auth.TOTPCode(secret, step+1)))
+		require.ErrorIs(t, login(auth.TOTPCode(secret, step+1)), ErrInvalidCredentials)
+	})
+
+	t.Run("Recovery Code", func(t *testing.T) {
+		require.NoError(t, login(recoveryCodes[0]))
+		require.ErrorIs(t, login(recoveryCodes[0]), ErrInvalidCredentials)
+	})
+
+	t.Run("Change Password", func(t *testing.T) {
+		changePassword := func(otp string) error {
+			return authCtrl.ChangeAccountPassword(t.Context(), ChangeAccountPasswordCmd{
+				Username:            account.Username,
+				CurrPasswdPlaintext: passwd,
+				NewPasswdPlaintext:  passwd,
+				OTP:                 otp,
+			})
+		}
+
+		require.ErrorIs(t, changePassword(""), ErrOTPRequired)
+		require.ErrorIs(t, changePassword("000000"), ErrInvalidCredentials)
+		require.NoError(t, changePassword(recoveryCodes[1]))
+	})
+
+	err = authCtrl.DisableTOTP(ctx, DisableTOTPCmd{CurrPasswdPlaintext: auth.PlaintextPassword("incorrect password")})
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	require.ErrorIs(t, login(""), ErrOTPRequired)
+
+	err = authCtrl.DisableTOTP(ctx, DisableTOTPCmd{CurrPasswdPlaintext: passwd})
+	require.NoError(t, err)
+	require.NoError(t, login(""))
+
+	err = authCtrl.DisableTOTP(ctx, DisableTOTPCmd{CurrPasswdPlaintext: passwd})
+	require.ErrorIs(t, err, ErrTOTPNotEnrolled)
+}
+
 func setupAuthController(t *testing.T) *AuthController {
 	t.Helper()
 
@@ -86,6 +181,7 @@ func setupAuthController(t *testing.T) *AuthController {
 
 	accountRepo := sqlite.NewAccountRepo(db)
 	authTokenRepo := sqlite.NewAuthTokenRepo(db)
+	totpRepo := sqlite.NewTOTPRepo(db)
 
 	config := AuthConfig{
 		Argon2Params:              auth.Argon2Params{KeyLen: 32, Memory: 8192, Threads: 2, Time: 1},
@@ -94,7 +190,8 @@ func setupAuthController(t *testing.T) *AuthController {
 		RefreshTokenValidDuration: time.Hour * 2,
 	}
 
-	authCtrl := NewAuthController(config, db, NewAccountController(db, accountRepo), authTokenRepo)
+	apiTokenUsage := NewAPITokenUsageTracker(APITokenUsageConfig{}, db, sqlite.NewAPITokenRepo(db))
+	authCtrl := NewAuthController(config, db, NewAccountController(db, accountRepo, nil), authTokenRepo, totpRepo, apiTokenUsage)
 
 	err := authCtrl.CreateAccount(t.Context(), CreateAccountCmd{
 		Account: &domain.Account{
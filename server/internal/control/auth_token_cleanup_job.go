@@ -0,0 +1,33 @@
+package control
+
+import (
+	"context"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+)
+
+const CleanupInvalidAuthTokensJobKind = "cleanup_invalid_auth_tokens"
+
+type CleanupInvalidAuthTokensJobData struct{}
+
+type CleanupInvalidAuthTokensJob struct {
+	tokens CleanupInvalidAuthTokensJobTokenCleaner
+}
+
+type CleanupInvalidAuthTokensJobTokenCleaner interface {
+	CleanupInvalidTokens(ctx context.Context) error
+}
+
+func NewCleanupInvalidAuthTokensJob(tokens CleanupInvalidAuthTokensJobTokenCleaner) *CleanupInvalidAuthTokensJob {
+	return &CleanupInvalidAuthTokensJob{tokens: tokens}
+}
+
+// Exec invalidates expired auth tokens and deletes all invalid auth tokens of all accounts.
+func (j *CleanupInvalidAuthTokensJob) Exec(ctx context.Context, _ CleanupInvalidAuthTokensJobData) (*domain.JobResult, error) {
+	err := j.tokens.CleanupInvalidTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.JobResult{Message: "deleted invalid auth tokens"}, nil
+}
@@ -0,0 +1,58 @@
+package control
+
+import (
+	"sync"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+)
+
+// changelogBroker notifies subscribers of an account about newly created ChangelogEntries.
+// Notifications carry no data and are coalesced: subscribers are expected to load all entries after their last known cursor.
+type changelogBroker struct {
+	mu          sync.Mutex
+	subscribers map[domain.AccountID]map[chan struct{}]struct{}
+}
+
+func newChangelogBroker() *changelogBroker {
+	return &changelogBroker{subscribers: map[domain.AccountID]map[chan struct{}]struct{}{}}
+}
+
+func (b *changelogBroker) subscribe(accountID domain.AccountID) (<-chan struct{}, func()) {
+	notify := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs, ok := b.subscribers[accountID]
+	if !ok {
+		subs = map[chan struct{}]struct{}{}
+		b.subscribers[accountID] = subs
+	}
+
+	subs[notify] = struct{}{}
+
+	return notify, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		subs := b.subscribers[accountID]
+
+		delete(subs, notify)
+
+		if len(subs) == 0 {
+			delete(b.subscribers, accountID)
+		}
+	}
+}
+
+func (b *changelogBroker) publish(accountID domain.AccountID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for notify := range b.subscribers[accountID] {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	}
+}
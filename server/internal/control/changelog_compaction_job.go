@@ -0,0 +1,82 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database"
+)
+
+const CompactChangelogJobKind = "compact_changelog"
+
+type CompactChangelogJobData struct {
+	AccountID domain.AccountID
+}
+
+type CompactChangelogJob struct {
+	transactioner database.Transactioner
+	syncRepo      CompactChangelogJobSyncRepo
+	usageRepo     CompactChangelogJobUsageRepo
+	gracePeriod   time.Duration
+	now           func() time.Time
+}
+
+type CompactChangelogJobSyncRepo interface {
+	GetLatestFullSyncEntry(ctx context.Context, accountID domain.AccountID) (*domain.FullSyncEntry, error)
+	DeleteChangelogEntriesBefore(ctx context.Context, accountID domain.AccountID, before time.Time) (*domain.DeletedChangelogEntries, error)
+}
+
+type CompactChangelogJobUsageRepo interface {
+	AddAccountUsage(ctx context.Context, usage *domain.AccountUsage) error
+}
+
+func NewCompactChangelogJob(config SyncConfig, transactioner database.Transactioner, syncRepo CompactChangelogJobSyncRepo, usageRepo CompactChangelogJobUsageRepo) *CompactChangelogJob {
+	return &CompactChangelogJob{transactioner: transactioner, syncRepo: syncRepo, usageRepo: usageRepo, gracePeriod: config.ChangelogCompactionGracePeriod, now: time.Now}
+}
+
+// Exec deletes all ChangelogEntries of the account that are included in the latest full sync snapshot and are older than the grace period.
+func (j *CompactChangelogJob) Exec(ctx context.Context, data CompactChangelogJobData) (*domain.JobResult, error) {
+	latest, err := j.syncRepo.GetLatestFullSyncEntry(ctx, data.AccountID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoFullSyncEntriesFound) {
+			return &domain.JobResult{Message: "no full sync entries found, nothing to compact"}, nil
+		}
+
+		return nil, err
+	}
+
+	if !latest.CorruptedAt.IsZero() {
+		return &domain.JobResult{Message: "latest full sync entry is corrupted, skipping compaction"}, nil
+	}
+
+	before := latest.Timestamp
+	if graceCutoff := j.now().Add(-j.gracePeriod); graceCutoff.Before(before) {
+		before = graceCutoff
+	}
+
+	var deleted *domain.DeletedChangelogEntries
+
+	err = j.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		deleted, err = j.syncRepo.DeleteChangelogEntriesBefore(ctx, data.AccountID, before)
+		if err != nil {
+			return err
+		}
+
+		return j.usageRepo.AddAccountUsage(ctx, &domain.AccountUsage{
+			AccountID:  data.AccountID,
+			SizeBytes:  -deleted.SizeBytes,
+			NumEntries: -deleted.NumEntries,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.JobResult{
+		Message:        fmt.Sprintf("deleted changelog entries before %s", before.UTC().Format(time.RFC3339)),
+		BytesReclaimed: deleted.ReclaimedBytes,
+	}, nil
+}
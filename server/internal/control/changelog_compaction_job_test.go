@@ -0,0 +1,130 @@
+package control
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
+	"go.robinthrift.com/conveyor/internal/testhelper"
+)
+
+func TestCompactChangelogJob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("No Full Sync Entry", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCompactChangelogJobTest(t)
+
+		result, err := setup.job.Exec(t.Context(), CompactChangelogJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), result.BytesReclaimed)
+		assert.Len(t, setup.listEntries(t), 3)
+	})
+
+	t.Run("Older Than Snapshot", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCompactChangelogJobTest(t)
+		setup.createFullSyncEntry(t, time.Now().Add(time.Minute))
+
+		result, err := setup.job.Exec(t.Context(), CompactChangelogJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+		// the small entries shared their page with each other, so no page was freed
+		assert.Equal(t, int64(0), result.BytesReclaimed)
+		assert.Empty(t, setup.listEntries(t))
+	})
+
+	t.Run("Reclaims Freed Pages", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCompactChangelogJobTest(t)
+
+		err := setup.syncRepo.CreateChangelogEntries(t.Context(), []domain.ChangelogEntry{
+			{AccountID: setup.accountID, SyncClientID: "client_a", Data: bytes.Repeat([]byte("a"), 16<<10)},
+			{AccountID: setup.accountID, SyncClientID: "client_a", Data: bytes.Repeat([]byte("b"), 16<<10)},
+			{AccountID: setup.accountID, SyncClientID: "client_b", Data: bytes.Repeat([]byte("c"), 16<<10)},
+		})
+		require.NoError(t, err)
+
+		setup.createFullSyncEntry(t, time.Now().Add(time.Minute))
+
+		result, err := setup.job.Exec(t.Context(), CompactChangelogJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, result.BytesReclaimed, int64(3*(8<<10)))
+		assert.Empty(t, setup.listEntries(t))
+	})
+
+	t.Run("Within Grace Period", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCompactChangelogJobTest(t)
+		setup.createFullSyncEntry(t, time.Now().Add(time.Minute))
+		setup.job.gracePeriod = time.Hour
+
+		result, err := setup.job.Exec(t.Context(), CompactChangelogJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), result.BytesReclaimed)
+		assert.Len(t, setup.listEntries(t), 3)
+	})
+}
+
+type compactChangelogJobTestSetup struct {
+	accountID           domain.AccountID
+	syncRepo            *sqlite.SyncRepo
+	job                 *CompactChangelogJob
+	listEntries         func(t *testing.T) []domain.ChangelogEntry
+	createFullSyncEntry func(t *testing.T, timestamp time.Time)
+}
+
+func setupCompactChangelogJobTest(t *testing.T) compactChangelogJobTestSetup {
+	t.Helper()
+	db := testhelper.NewInMemTestSQLite(t)
+
+	accountRepo := sqlite.NewAccountRepo(db)
+	syncRepo := sqlite.NewSyncRepo(db)
+
+	err := accountRepo.Create(t.Context(), &domain.Account{Username: t.Name(), Password: domain.AccountPassword{Password: []byte("1234"), Salt: []byte("1234")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountID := domain.AccountID(1)
+
+	err = syncRepo.CreateChangelogEntries(t.Context(), []domain.ChangelogEntry{
+		{AccountID: accountID, SyncClientID: "client_a", Data: []byte("entry_0")},
+		{AccountID: accountID, SyncClientID: "client_a", Data: []byte("entry_1")},
+		{AccountID: accountID, SyncClientID: "client_b", Data: []byte("entry_2")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := NewCompactChangelogJob(SyncConfig{}, db, syncRepo, sqlite.NewUsageRepo(db))
+	job.now = func() time.Time { return time.Now().Add(time.Minute * 2) }
+
+	return compactChangelogJobTestSetup{
+		accountID: accountID,
+		syncRepo:  syncRepo,
+		job:       job,
+		listEntries: func(t *testing.T) []domain.ChangelogEntry {
+			t.Helper()
+
+			list, err := syncRepo.ListChangelogEntries(t.Context(), domain.ListChangelogEntriesQuery{AccountID: accountID})
+			require.NoError(t, err)
+
+			return list.Items
+		},
+		createFullSyncEntry: func(t *testing.T, timestamp time.Time) {
+			t.Helper()
+
+			err := syncRepo.CreateFullSyncEntry(t.Context(), &domain.FullSyncEntry{
+				AccountID:  accountID,
+				Timestamp:  timestamp,
+				SizeBytes:  10,
+				Sha256Hash: []byte("hash"),
+			})
+			require.NoError(t, err)
+		},
+	}
+}
@@ -0,0 +1,133 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database"
+)
+
+const CleanupFullSyncEntriesJobKind = "cleanup_full_sync_entries"
+
+type CleanupFullSyncEntriesJobData struct {
+	AccountID domain.AccountID
+}
+
+type CleanupFullSyncEntriesJob struct {
+	transactioner  database.Transactioner
+	syncRepo       CleanupFullSyncEntriesJobSyncRepo
+	blobs          CleanupFullSyncEntriesJobBlobStorage
+	usageRepo      CleanupFullSyncEntriesJobUsageRepo
+	retainCount    int
+	retainDuration time.Duration
+	now            func() time.Time
+}
+
+type CleanupFullSyncEntriesJobSyncRepo interface {
+	ListFullSyncEntries(ctx context.Context, accountID domain.AccountID) ([]*domain.FullSyncEntry, error)
+	DeleteFullSyncEntry(ctx context.Context, entry *domain.FullSyncEntry) error
+}
+
+type CleanupFullSyncEntriesJobBlobStorage interface {
+	RemoveBlob(ctx context.Context, accountID domain.AccountID, filepath string) error
+}
+
+type CleanupFullSyncEntriesJobUsageRepo interface {
+	AddAccountUsage(ctx context.Context, usage *domain.AccountUsage) error
+}
+
+func NewCleanupFullSyncEntriesJob(config SyncConfig, transactioner database.Transactioner, syncRepo CleanupFullSyncEntriesJobSyncRepo, blobs CleanupFullSyncEntriesJobBlobStorage, usageRepo CleanupFullSyncEntriesJobUsageRepo) *CleanupFullSyncEntriesJob {
+	return &CleanupFullSyncEntriesJob{
+		transactioner:  transactioner,
+		syncRepo:       syncRepo,
+		blobs:          blobs,
+		usageRepo:      usageRepo,
+		retainCount:    config.FullSyncRetainCount,
+		retainDuration: config.FullSyncRetainDuration,
+		now:            time.Now,
+	}
+}
+
+// Exec deletes all full sync snapshots of the account that are neither among the newest retainCount uncorrupted snapshots
+// nor newer than retainDuration. The latest uncorrupted snapshot is always kept, as it is required for syncing new
+// clients and compacting the changelog. If neither a retain count nor a retain duration is configured, all snapshots are kept.
+func (j *CleanupFullSyncEntriesJob) Exec(ctx context.Context, data CleanupFullSyncEntriesJobData) (*domain.JobResult, error) {
+	if j.retainCount <= 0 && j.retainDuration <= 0 {
+		return &domain.JobResult{Message: "no retention policy configured, nothing to clean up"}, nil
+	}
+
+	entries, err := j.syncRepo.ListFullSyncEntries(ctx, data.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	retainCount := max(j.retainCount, 1)
+
+	var retainAfter time.Time
+	if j.retainDuration > 0 {
+		retainAfter = j.now().Add(-j.retainDuration)
+	}
+
+	var expired []*domain.FullSyncEntry
+
+	// snapshots taken within the same second share a blob, which must be kept as long as one of them is retained
+	retainedBlobs := map[string]bool{}
+	retained := 0
+
+	for _, entry := range entries {
+		switch {
+		// corrupted snapshots don't count, so they can't push out the last good one
+		case entry.CorruptedAt.IsZero() && retained < retainCount:
+			retained++
+		case !retainAfter.IsZero() && entry.Timestamp.After(retainAfter):
+		default:
+			expired = append(expired, entry)
+			continue
+		}
+
+		retainedBlobs[fullSyncEntryFilepath(entry.Timestamp)] = true
+	}
+
+	deleted := 0
+	var reclaimed int64
+
+	removedBlobs := map[string]bool{}
+
+	for _, entry := range expired {
+		filepath := fullSyncEntryFilepath(entry.Timestamp)
+
+		if !retainedBlobs[filepath] && !removedBlobs[filepath] {
+			err = j.blobs.RemoveBlob(ctx, entry.AccountID, filepath)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("error removing full sync blob: %w", err)
+			}
+
+			removedBlobs[filepath] = true
+			reclaimed += entry.SizeBytes
+		}
+
+		// the usage is updated per entry, so that it stays accurate when a later entry fails
+		err = j.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+			err := j.syncRepo.DeleteFullSyncEntry(ctx, entry)
+			if err != nil {
+				return err
+			}
+
+			return j.usageRepo.AddAccountUsage(ctx, &domain.AccountUsage{AccountID: data.AccountID, SizeBytes: -entry.SizeBytes})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		deleted++
+	}
+
+	return &domain.JobResult{
+		Message:        fmt.Sprintf("deleted %d full sync entries", deleted),
+		BytesReclaimed: reclaimed,
+	}, nil
+}
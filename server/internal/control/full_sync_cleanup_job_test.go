@@ -0,0 +1,198 @@
+package control
+
+import (
+	"bytes"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
+	"go.robinthrift.com/conveyor/internal/storage/filesystem"
+	"go.robinthrift.com/conveyor/internal/testhelper"
+)
+
+func TestCleanupFullSyncEntriesJob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("No Retention Policy", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCleanupFullSyncEntriesJobTest(t)
+
+		result, err := setup.job.Exec(t.Context(), CleanupFullSyncEntriesJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), result.BytesReclaimed)
+		assert.Len(t, setup.listEntries(t), 4)
+	})
+
+	t.Run("Retain Count", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCleanupFullSyncEntriesJobTest(t)
+		setup.job.retainCount = 2
+
+		result, err := setup.job.Exec(t.Context(), CleanupFullSyncEntriesJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), result.BytesReclaimed)
+
+		entries := setup.listEntries(t)
+		require.Len(t, entries, 2)
+		assert.Equal(t, setup.timestamps[3].Unix(), entries[0].Timestamp.Unix())
+		assert.Equal(t, setup.timestamps[2].Unix(), entries[1].Timestamp.Unix())
+
+		assert.FileExists(t, setup.blobPath(setup.timestamps[2]))
+		assert.NoFileExists(t, setup.blobPath(setup.timestamps[1]))
+		assert.NoFileExists(t, setup.blobPath(setup.timestamps[0]))
+	})
+
+	t.Run("Retain Duration", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCleanupFullSyncEntriesJobTest(t)
+		setup.job.retainDuration = time.Minute * 150
+
+		result, err := setup.job.Exec(t.Context(), CleanupFullSyncEntriesJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), result.BytesReclaimed)
+		assert.Len(t, setup.listEntries(t), 2)
+	})
+
+	t.Run("Retain Count Or Duration", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCleanupFullSyncEntriesJobTest(t)
+		setup.job.retainCount = 3
+		setup.job.retainDuration = time.Minute * 90
+
+		result, err := setup.job.Exec(t.Context(), CleanupFullSyncEntriesJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), result.BytesReclaimed)
+		assert.Len(t, setup.listEntries(t), 3)
+	})
+
+	t.Run("Always Keeps Latest", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCleanupFullSyncEntriesJobTest(t)
+		setup.job.retainDuration = time.Minute
+
+		result, err := setup.job.Exec(t.Context(), CleanupFullSyncEntriesJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(30), result.BytesReclaimed)
+
+		entries := setup.listEntries(t)
+		require.Len(t, entries, 1)
+		assert.Equal(t, setup.timestamps[3].Unix(), entries[0].Timestamp.Unix())
+		assert.FileExists(t, setup.blobPath(setup.timestamps[3]))
+	})
+
+	t.Run("Ignores Corrupted Snapshots", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCleanupFullSyncEntriesJobTest(t)
+		setup.job.retainCount = 1
+
+		latest := setup.listEntries(t)[0]
+		latest.CorruptedAt = time.Now()
+		err := setup.syncRepo.MarkFullSyncEntryCorrupted(t.Context(), latest)
+		require.NoError(t, err)
+
+		_, err = setup.job.Exec(t.Context(), CleanupFullSyncEntriesJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+
+		// the corrupted latest snapshot doesn't push out the last good one
+		entries := setup.listEntries(t)
+		require.Len(t, entries, 1)
+		assert.Equal(t, setup.timestamps[2].Unix(), entries[0].Timestamp.Unix())
+		assert.FileExists(t, setup.blobPath(setup.timestamps[2]))
+	})
+
+	t.Run("Shared Blob", func(t *testing.T) {
+		t.Parallel()
+		setup := setupCleanupFullSyncEntriesJobTest(t)
+		setup.job.retainCount = 1
+
+		// taken within the same second as the latest snapshot, so both share a blob
+		err := setup.syncRepo.CreateFullSyncEntry(t.Context(), &domain.FullSyncEntry{
+			AccountID:  setup.accountID,
+			Timestamp:  setup.timestamps[3].Truncate(time.Second),
+			SizeBytes:  10,
+			Sha256Hash: []byte("hash"),
+		})
+		require.NoError(t, err)
+
+		result, err := setup.job.Exec(t.Context(), CleanupFullSyncEntriesJobData{AccountID: setup.accountID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(30), result.BytesReclaimed)
+		assert.Len(t, setup.listEntries(t), 1)
+		assert.FileExists(t, setup.blobPath(setup.timestamps[3]))
+	})
+}
+
+type cleanupFullSyncEntriesJobTestSetup struct {
+	accountID   domain.AccountID
+	syncRepo    *sqlite.SyncRepo
+	job         *CleanupFullSyncEntriesJob
+	timestamps  []time.Time
+	listEntries func(t *testing.T) []*domain.FullSyncEntry
+	blobPath    func(timestamp time.Time) string
+}
+
+func setupCleanupFullSyncEntriesJobTest(t *testing.T) cleanupFullSyncEntriesJobTestSetup {
+	t.Helper()
+	db := testhelper.NewInMemTestSQLite(t)
+
+	blobDir := t.TempDir()
+	blobs := &filesystem.LocalFSBlobStorage{
+		BaseDir: blobDir,
+		TmpDir:  t.TempDir(),
+	}
+
+	accountRepo := sqlite.NewAccountRepo(db)
+	syncRepo := sqlite.NewSyncRepo(db)
+
+	err := accountRepo.Create(t.Context(), &domain.Account{Username: t.Name(), Password: domain.AccountPassword{Password: []byte("1234"), Salt: []byte("1234")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountID := domain.AccountID(1)
+
+	now := time.Now()
+	timestamps := []time.Time{now.Add(-4 * time.Hour), now.Add(-3 * time.Hour), now.Add(-2 * time.Hour), now.Add(-1 * time.Hour)}
+
+	for _, timestamp := range timestamps {
+		sizeBytes, err := blobs.WriteBlob(t.Context(), accountID, fullSyncEntryFilepath(timestamp), bytes.NewReader([]byte("0123456789")))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = syncRepo.CreateFullSyncEntry(t.Context(), &domain.FullSyncEntry{
+			AccountID:  accountID,
+			Timestamp:  timestamp,
+			SizeBytes:  sizeBytes,
+			Sha256Hash: []byte("hash"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	job := NewCleanupFullSyncEntriesJob(SyncConfig{}, db, syncRepo, blobs, sqlite.NewUsageRepo(db))
+	job.now = func() time.Time { return now }
+
+	return cleanupFullSyncEntriesJobTestSetup{
+		accountID:  accountID,
+		syncRepo:   syncRepo,
+		job:        job,
+		timestamps: timestamps,
+		listEntries: func(t *testing.T) []*domain.FullSyncEntry {
+			t.Helper()
+
+			entries, err := syncRepo.ListFullSyncEntries(t.Context(), accountID)
+			require.NoError(t, err)
+
+			return entries
+		},
+		blobPath: func(timestamp time.Time) string {
+			return path.Join(blobDir, "1", fullSyncEntryFilepath(timestamp))
+		},
+	}
+}
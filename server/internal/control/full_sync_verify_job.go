@@ -0,0 +1,99 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+)
+
+const VerifyFullSyncEntriesJobKind = "verify_full_sync_entries"
+
+type VerifyFullSyncEntriesJobData struct{}
+
+type VerifyFullSyncEntriesJob struct {
+	syncRepo VerifyFullSyncEntriesJobSyncRepo
+	blobs    VerifyFullSyncEntriesJobBlobStorage
+	now      func() time.Time
+}
+
+type VerifyFullSyncEntriesJobSyncRepo interface {
+	ListUncorruptedFullSyncEntries(ctx context.Context) ([]*domain.FullSyncEntry, error)
+	MarkFullSyncEntryCorrupted(ctx context.Context, entry *domain.FullSyncEntry) error
+}
+
+type VerifyFullSyncEntriesJobBlobStorage interface {
+	OpenBlob(ctx context.Context, accountID domain.AccountID, filepath string) (io.ReadCloser, error)
+}
+
+func NewVerifyFullSyncEntriesJob(syncRepo VerifyFullSyncEntriesJobSyncRepo, blobs VerifyFullSyncEntriesJobBlobStorage) *VerifyFullSyncEntriesJob {
+	return &VerifyFullSyncEntriesJob{syncRepo: syncRepo, blobs: blobs, now: time.Now}
+}
+
+// Exec rehashes all stored full sync snapshots and marks those as corrupted whose size or SHA-256 hash doesn't match the recorded values.
+// Corrupted snapshots are no longer served to clients.
+func (j *VerifyFullSyncEntriesJob) Exec(ctx context.Context, _ VerifyFullSyncEntriesJobData) (*domain.JobResult, error) {
+	entries, err := j.syncRepo.ListUncorruptedFullSyncEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	corrupted := 0
+
+	for _, entry := range entries {
+		ok, err := j.verify(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			continue
+		}
+
+		slog.WarnContext(ctx, "full sync entry is corrupted", slog.Int64("full_sync_entry_id", entry.ID), slog.Any("account_id", entry.AccountID))
+
+		entry.CorruptedAt = j.now()
+
+		err = j.syncRepo.MarkFullSyncEntryCorrupted(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		corrupted++
+	}
+
+	return &domain.JobResult{
+		Message: fmt.Sprintf("verified %d full sync entries, %d corrupted", len(entries), corrupted),
+	}, nil
+}
+
+func (j *VerifyFullSyncEntriesJob) verify(ctx context.Context, entry *domain.FullSyncEntry) (ok bool, err error) {
+	blob, err := j.blobs.OpenBlob(ctx, entry.AccountID, fullSyncEntryFilepath(entry.Timestamp))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("error opening full sync blob: %w", err)
+	}
+
+	defer func() {
+		err = errors.Join(err, blob.Close())
+	}()
+
+	h := sha256.New()
+
+	sizeBytes, err := io.Copy(h, blob)
+	if err != nil {
+		return false, fmt.Errorf("error reading full sync blob: %w", err)
+	}
+
+	return sizeBytes == entry.SizeBytes && bytes.Equal(h.Sum(nil), entry.Sha256Hash), nil
+}
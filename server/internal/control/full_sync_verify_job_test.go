@@ -0,0 +1,135 @@
+package control
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
+	"go.robinthrift.com/conveyor/internal/storage/filesystem"
+	"go.robinthrift.com/conveyor/internal/testhelper"
+)
+
+func TestVerifyFullSyncEntriesJob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Intact", func(t *testing.T) {
+		t.Parallel()
+		setup := setupVerifyFullSyncEntriesJobTest(t)
+
+		result, err := setup.job.Exec(t.Context(), VerifyFullSyncEntriesJobData{})
+		require.NoError(t, err)
+		assert.Equal(t, "verified 2 full sync entries, 0 corrupted", result.Message)
+		assert.Len(t, setup.listUncorrupted(t), 2)
+	})
+
+	t.Run("Flipped Bit", func(t *testing.T) {
+		t.Parallel()
+		setup := setupVerifyFullSyncEntriesJobTest(t)
+
+		err := os.WriteFile(setup.blobPath(setup.timestamps[1]), []byte("full database contenu"), 0o600)
+		require.NoError(t, err)
+
+		result, err := setup.job.Exec(t.Context(), VerifyFullSyncEntriesJobData{})
+		require.NoError(t, err)
+		assert.Equal(t, "verified 2 full sync entries, 1 corrupted", result.Message)
+
+		uncorrupted := setup.listUncorrupted(t)
+		require.Len(t, uncorrupted, 1)
+		assert.Equal(t, setup.timestamps[0].Unix(), uncorrupted[0].Timestamp.Unix())
+
+		latest, err := setup.syncRepo.GetLatestFullSyncEntry(t.Context(), setup.accountID)
+		require.NoError(t, err)
+		assert.False(t, latest.CorruptedAt.IsZero())
+	})
+
+	t.Run("Missing Blob", func(t *testing.T) {
+		t.Parallel()
+		setup := setupVerifyFullSyncEntriesJobTest(t)
+
+		err := os.Remove(setup.blobPath(setup.timestamps[0]))
+		require.NoError(t, err)
+
+		result, err := setup.job.Exec(t.Context(), VerifyFullSyncEntriesJobData{})
+		require.NoError(t, err)
+		assert.Equal(t, "verified 2 full sync entries, 1 corrupted", result.Message)
+		assert.Len(t, setup.listUncorrupted(t), 1)
+	})
+}
+
+type verifyFullSyncEntriesJobTestSetup struct {
+	accountID       domain.AccountID
+	job             *VerifyFullSyncEntriesJob
+	syncRepo        *sqlite.SyncRepo
+	timestamps      []time.Time
+	listUncorrupted func(t *testing.T) []*domain.FullSyncEntry
+	blobPath        func(timestamp time.Time) string
+}
+
+func setupVerifyFullSyncEntriesJobTest(t *testing.T) verifyFullSyncEntriesJobTestSetup {
+	t.Helper()
+	db := testhelper.NewInMemTestSQLite(t)
+
+	blobDir := t.TempDir()
+	blobs := &filesystem.LocalFSBlobStorage{
+		BaseDir: blobDir,
+		TmpDir:  t.TempDir(),
+	}
+
+	accountRepo := sqlite.NewAccountRepo(db)
+	syncRepo := sqlite.NewSyncRepo(db)
+
+	err := accountRepo.Create(t.Context(), &domain.Account{Username: t.Name(), Password: domain.AccountPassword{Password: []byte("1234"), Salt: []byte("1234")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountID := domain.AccountID(1)
+
+	content := []byte("full database content")
+	hash := sha256.Sum256(content)
+
+	now := time.Now()
+	timestamps := []time.Time{now.Add(-2 * time.Hour), now.Add(-1 * time.Hour)}
+
+	for _, timestamp := range timestamps {
+		sizeBytes, err := blobs.WriteBlob(t.Context(), accountID, fullSyncEntryFilepath(timestamp), bytes.NewReader(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = syncRepo.CreateFullSyncEntry(t.Context(), &domain.FullSyncEntry{
+			AccountID:  accountID,
+			Timestamp:  timestamp,
+			SizeBytes:  sizeBytes,
+			Sha256Hash: hash[:],
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return verifyFullSyncEntriesJobTestSetup{
+		accountID:  accountID,
+		job:        NewVerifyFullSyncEntriesJob(syncRepo, blobs),
+		syncRepo:   syncRepo,
+		timestamps: timestamps,
+		listUncorrupted: func(t *testing.T) []*domain.FullSyncEntry {
+			t.Helper()
+
+			entries, err := syncRepo.ListUncorruptedFullSyncEntries(t.Context())
+			require.NoError(t, err)
+
+			return entries
+		},
+		blobPath: func(timestamp time.Time) string {
+			return path.Join(blobDir, "1", fullSyncEntryFilepath(timestamp))
+		},
+	}
+}
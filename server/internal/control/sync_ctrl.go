@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.robinthrift.com/conveyor/internal/auth"
@@ -21,30 +23,60 @@ import (
 	gonanoid "github.com/matoous/go-nanoid/v2"
 )
 
+type SyncConfig struct {
+	ChangelogCompactionGracePeriod time.Duration
+	FullSyncRetainCount            int
+	FullSyncRetainDuration         time.Duration
+}
+
 type SyncController struct {
+	config        SyncConfig
 	transactioner database.Transactioner
 	syncRepo      SyncControllerSyncRepo
 	attachments   *AttachmentController
+	usage         *UsageController
 	accountCtrl   *AccountControl
 	blobs         SyncControllerBlobStorage
+	scheduler     SyncControllerJobScheduler
+	changes       *changelogBroker
+}
+
+type SyncControllerJobScheduler interface {
+	Schedule(ctx context.Context, job *domain.Job) error
 }
 
 type SyncControllerBlobStorage interface {
-	WriteBlob(accountID domain.AccountID, filepath string, content io.Reader) (int64, error)
-	RemoveBlob(accountID domain.AccountID, filepath string) error
+	WriteBlob(ctx context.Context, accountID domain.AccountID, filepath string, content io.Reader) (int64, error)
+	RemoveBlob(ctx context.Context, accountID domain.AccountID, filepath string) error
 }
 
 type SyncControllerSyncRepo interface {
+	GetSyncClient(ctx context.Context, accountID domain.AccountID, id domain.SyncClientID) (*domain.SyncClient, error)
+	ListSyncClients(ctx context.Context, accountID domain.AccountID, query domain.ListSyncClientsQuery) (*domain.SyncClientList, error)
 	CreateSyncClient(ctx context.Context, client *domain.SyncClient) error
 	DeleteSyncClient(ctx context.Context, client *domain.SyncClient) error
-	ListChangelogEntries(ctx context.Context, query domain.ListChangelogEntriesQuery) ([]domain.ChangelogEntry, error)
+	UpdateSyncClientAck(ctx context.Context, client *domain.SyncClient) error
+	ListChangelogEntries(ctx context.Context, query domain.ListChangelogEntriesQuery) (*domain.ChangelogEntryList, error)
+	GetLatestChangelogEntryID(ctx context.Context, accountID domain.AccountID) (domain.ChangelogEntryID, error)
 	CreateChangelogEntries(ctx context.Context, entries []domain.ChangelogEntry) error
 	CreateFullSyncEntry(ctx context.Context, entry *domain.FullSyncEntry) error
 	GetLatestFullSyncEntry(ctx context.Context, accountID domain.AccountID) (*domain.FullSyncEntry, error)
+	GetFullSyncEntryByTimestamp(ctx context.Context, accountID domain.AccountID, timestamp time.Time) (*domain.FullSyncEntry, error)
+}
+
+func NewSyncController(config SyncConfig, transactioner database.Transactioner, syncRepo SyncControllerSyncRepo, accountCtrl *AccountControl, attachments *AttachmentController, usage *UsageController, blobs SyncControllerBlobStorage, scheduler SyncControllerJobScheduler) *SyncController {
+	return &SyncController{config, transactioner, syncRepo, attachments, usage, accountCtrl, blobs, scheduler, newChangelogBroker()}
 }
 
-func NewSyncController(transactioner database.Transactioner, syncRepo SyncControllerSyncRepo, accountCtrl *AccountControl, attachments *AttachmentController, blobs SyncControllerBlobStorage) *SyncController {
-	return &SyncController{transactioner, syncRepo, attachments, accountCtrl, blobs}
+type ListClientsQuery = domain.ListSyncClientsQuery
+
+func (sc *SyncController) ListClients(ctx context.Context, query ListClientsQuery) (*domain.SyncClientList, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, auth.ErrUnauthorized
+	}
+
+	return sc.syncRepo.ListSyncClients(ctx, account.ID, query)
 }
 
 type RegisterClientCmd struct {
@@ -79,6 +111,25 @@ func (sc *SyncController) UnregisterClient(ctx context.Context, cmd UnregisterCl
 	})
 }
 
+type AckClientCmd struct {
+	ClientID              domain.SyncClientID
+	AckedChangelogEntryID domain.ChangelogEntryID
+}
+
+func (sc *SyncController) AckClient(ctx context.Context, cmd AckClientCmd) error {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return auth.ErrUnauthorized
+	}
+
+	return sc.syncRepo.UpdateSyncClientAck(ctx, &domain.SyncClient{
+		ID:                    cmd.ClientID,
+		AccountID:             account.ID,
+		AckedChangelogEntryID: cmd.AckedChangelogEntryID,
+		LastSeenAt:            time.Now(),
+	})
+}
+
 func (sc *SyncController) GetLatestFullSyncEntry(ctx context.Context) (*domain.FullSyncEntry, error) {
 	account := auth.AccountFromCtx(ctx)
 	if account == nil {
@@ -90,7 +141,38 @@ func (sc *SyncController) GetLatestFullSyncEntry(ctx context.Context) (*domain.F
 		return nil, err
 	}
 
-	entry.Filepath = path.Join("dbs", fmt.Sprintf("conveyor_%d.db", entry.Timestamp.Unix()))
+	if !entry.CorruptedAt.IsZero() {
+		return nil, domain.ErrFullSyncEntryCorrupted
+	}
+
+	entry.Filepath = fullSyncEntryFilepath(entry.Timestamp)
+
+	return entry, nil
+}
+
+// GetFullSyncEntryByFilepath returns the full sync entry stored at the blob filepath.
+// Returns [domain.ErrNoFullSyncEntriesFound] if the filepath doesn't belong to a full sync entry.
+func (sc *SyncController) GetFullSyncEntryByFilepath(ctx context.Context, filepath string) (*domain.FullSyncEntry, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, auth.ErrUnauthorized
+	}
+
+	timestamp, ok := parseFullSyncEntryFilepath(filepath)
+	if !ok {
+		return nil, domain.ErrNoFullSyncEntriesFound
+	}
+
+	entry, err := sc.syncRepo.GetFullSyncEntryByTimestamp(ctx, account.ID, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !entry.CorruptedAt.IsZero() {
+		return nil, domain.ErrFullSyncEntryCorrupted
+	}
+
+	entry.Filepath = fullSyncEntryFilepath(entry.Timestamp)
 
 	return entry, nil
 }
@@ -107,43 +189,130 @@ func (sc *SyncController) SaveFullDB(ctx context.Context, cmd SaveFullDBCmd) err
 
 	return sc.transactioner.InTransaction(ctx, func(ctx context.Context) error {
 		timestamp := time.Now()
-		filepath := path.Join("dbs", fmt.Sprintf("conveyor_%d.db", timestamp.Unix()))
+		filepath := fullSyncEntryFilepath(timestamp)
+
+		data, err := sc.usage.LimitReader(ctx, cmd.Data)
+		if err != nil {
+			return err
+		}
 
 		h := sha256.New()
 
-		tee := io.TeeReader(cmd.Data, h)
+		tee := io.TeeReader(data, h)
 
-		sizeBytes, err := sc.blobs.WriteBlob(account.ID, filepath, tee)
+		sizeBytes, err := sc.blobs.WriteBlob(ctx, account.ID, filepath, tee)
 		if err != nil {
 			return err
 		}
 
-		return sc.syncRepo.CreateFullSyncEntry(ctx, &domain.FullSyncEntry{
+		err = sc.usage.Reserve(ctx, sizeBytes, 0)
+		if err != nil {
+			return errors.Join(err, sc.blobs.RemoveBlob(ctx, account.ID, filepath))
+		}
+
+		err = sc.syncRepo.CreateFullSyncEntry(ctx, &domain.FullSyncEntry{
 			AccountID:  account.ID,
 			Timestamp:  timestamp,
 			Filepath:   filepath,
 			SizeBytes:  sizeBytes,
 			Sha256Hash: h.Sum(nil),
 		})
+		if err != nil {
+			return err
+		}
+
+		err = sc.scheduler.Schedule(ctx, &domain.Job{
+			Kind: CleanupFullSyncEntriesJobKind,
+			Data: CleanupFullSyncEntriesJobData{AccountID: account.ID},
+		})
+		if err != nil {
+			return err
+		}
+
+		return sc.scheduler.Schedule(ctx, &domain.Job{
+			Kind:         CompactChangelogJobKind,
+			Data:         CompactChangelogJobData{AccountID: account.ID},
+			ScheduledFor: timestamp.Add(sc.config.ChangelogCompactionGracePeriod),
+		})
 	})
 }
 
+func fullSyncEntryFilepath(timestamp time.Time) string {
+	return path.Join("dbs", fmt.Sprintf("conveyor_%d.db", timestamp.Unix()))
+}
+
+func parseFullSyncEntryFilepath(filepath string) (time.Time, bool) {
+	filename, ok := strings.CutPrefix(path.Clean(filepath), "dbs/conveyor_")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	filename, ok = strings.CutSuffix(filename, ".db")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	unix, err := strconv.ParseInt(filename, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(unix, 0), true
+}
+
 type ListChangelogEntriesQuery struct {
-	Since time.Time
+	Since           time.Time
+	ExcludeClientID domain.SyncClientID
+	PageSize        uint64
+	PageAfter       *domain.ChangelogEntryID
 }
 
-func (sc *SyncController) ListChangelogEntries(ctx context.Context, query ListChangelogEntriesQuery) ([]domain.ChangelogEntry, error) {
+func (sc *SyncController) ListChangelogEntries(ctx context.Context, query ListChangelogEntriesQuery) (*domain.ChangelogEntryList, error) {
 	account := auth.AccountFromCtx(ctx)
 	if account == nil {
 		return nil, auth.ErrUnauthorized
 	}
 
+	if query.ExcludeClientID != "" {
+		_, err := sc.syncRepo.GetSyncClient(ctx, account.ID, query.ExcludeClientID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return sc.syncRepo.ListChangelogEntries(ctx, domain.ListChangelogEntriesQuery{
-		AccountID: account.ID,
-		Since:     query.Since,
+		AccountID:           account.ID,
+		Since:               query.Since,
+		ExcludeSyncClientID: query.ExcludeClientID,
+		PageSize:            query.PageSize,
+		PageAfter:           query.PageAfter,
 	})
 }
 
+// GetLatestChangelogEntryID returns the ID of the authenticated account's most recent ChangelogEntry, which can be used as
+// a cursor to only list entries created afterwards.
+func (sc *SyncController) GetLatestChangelogEntryID(ctx context.Context) (domain.ChangelogEntryID, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return 0, auth.ErrUnauthorized
+	}
+
+	return sc.syncRepo.GetLatestChangelogEntryID(ctx, account.ID)
+}
+
+// SubscribeToChangelogEntries returns a channel that receives a notification whenever new ChangelogEntries were created
+// for the authenticated account. The returned func must be called to unsubscribe.
+func (sc *SyncController) SubscribeToChangelogEntries(ctx context.Context) (<-chan struct{}, func(), error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, nil, auth.ErrUnauthorized
+	}
+
+	notify, unsubscribe := sc.changes.subscribe(account.ID)
+
+	return notify, unsubscribe, nil
+}
+
 type CreateChangelogEntriesCmd struct {
 	Entries []domain.ChangelogEntry
 }
@@ -158,15 +327,40 @@ func (sc *SyncController) CreateChangelogEntries(ctx context.Context, cmd Create
 		cmd.Entries[i].AccountID = account.ID
 	}
 
-	return sc.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+	err := sc.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		err := sc.usage.Reserve(ctx, changelogEntriesSize(cmd.Entries), int64(len(cmd.Entries)))
+		if err != nil {
+			return err
+		}
+
 		return sc.syncRepo.CreateChangelogEntries(ctx, cmd.Entries)
 	})
+	if err != nil {
+		return err
+	}
+
+	sc.changes.publish(account.ID)
+
+	return nil
+}
+
+func changelogEntriesSize(entries []domain.ChangelogEntry) int64 {
+	var sizeBytes int64
+	for _, entry := range entries {
+		sizeBytes += int64(len(entry.Data))
+	}
+
+	return sizeBytes
 }
 
 func (sc *SyncController) StoreAttachment(ctx context.Context, cmd StoreAttachmentCmd) error {
 	return sc.attachments.StoreAttachment(ctx, cmd)
 }
 
+func (sc *SyncController) DeleteAttachment(ctx context.Context, cmd DeleteAttachmentCmd) error {
+	return sc.attachments.DeleteAttachment(ctx, cmd)
+}
+
 type CreateMemoChangelogEntryCmd struct {
 	Memo          *domain.ChangelogEntry
 	PlaintextMemo *PlaintextMemo
@@ -203,9 +397,21 @@ func (sc *SyncController) CreateMemoChangelogEntry(ctx context.Context, cmd Crea
 
 	memo.AccountID = account.ID
 
-	return sc.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+	err := sc.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		err := sc.usage.Reserve(ctx, int64(len(memo.Data)), 1)
+		if err != nil {
+			return err
+		}
+
 		return sc.syncRepo.CreateChangelogEntries(ctx, []domain.ChangelogEntry{memo})
 	})
+	if err != nil {
+		return err
+	}
+
+	sc.changes.publish(account.ID)
+
+	return nil
 }
 
 type CreateAttachmentChangelogEntryCmd struct {
@@ -215,8 +421,10 @@ type CreateAttachmentChangelogEntryCmd struct {
 	IsEncrytped      bool
 	Filepath         string
 	sizeBytes        int64
+	storedSizeBytes  int64
 	sha256           []byte
 	recipient        *age.X25519Recipient
+	isDuplicate      bool
 }
 
 func (sc *SyncController) CreateAttachmentChangelogEntry(ctx context.Context, cmd CreateAttachmentChangelogEntryCmd) (string, error) {
@@ -236,16 +444,24 @@ func (sc *SyncController) CreateAttachmentChangelogEntry(ctx context.Context, cm
 		return "", fmt.Errorf("error creating changelog entry: %w", err)
 	}
 
+	// no changelog entry is created for content that was uploaded before
+	if !cmd.isDuplicate {
+		sc.changes.publish(account.ID)
+	}
+
 	return id, nil
 }
 
-func (sc *SyncController) createAttachmentChangelogEntry(ctx context.Context, cmd *CreateAttachmentChangelogEntryCmd) (string, error) {
+func (sc *SyncController) createAttachmentChangelogEntry(ctx context.Context, cmd *CreateAttachmentChangelogEntryCmd) (id string, err error) {
 	account := auth.AccountFromCtx(ctx)
 	if account == nil {
 		return "", auth.ErrUnauthorized
 	}
 
-	var id string
+	cmd.Data, err = sc.usage.LimitReader(ctx, cmd.Data)
+	if err != nil {
+		return id, err
+	}
 
 	key, err := sc.accountCtrl.GetAccountKeyByName(ctx, domain.PrimaryAccountKeyName)
 	if err != nil {
@@ -276,9 +492,19 @@ func (sc *SyncController) createAttachmentChangelogEntry(ctx context.Context, cm
 		return id, err
 	}
 
-	err = blob.Finalize(cmd.Filepath)
-	if err != nil {
-		return id, fmt.Errorf("error finalizing bytes: %w", err)
+	if !cmd.IsEncrytped {
+		// the same content was uploaded before, the encrypted blob is discarded when closed without being finalized
+		var existing *domain.Attachment
+
+		existing, err = sc.attachments.GetAttachmentBySha256(ctx, cmd.sha256)
+		if err == nil {
+			cmd.isDuplicate = true
+			return existing.ID, nil
+		}
+
+		if !errors.Is(err, domain.ErrAttachmentNotFound) {
+			return id, fmt.Errorf("error looking up existing attachment: %w", err)
+		}
 	}
 
 	targetID, entry, err := sc.newCreateAttachmentChangelogEntry(cmd)
@@ -290,38 +516,57 @@ func (sc *SyncController) createAttachmentChangelogEntry(ctx context.Context, cm
 
 	id = targetID
 
+	err = sc.usage.Reserve(ctx, cmd.storedSizeBytes+int64(len(entry.Data)), 1)
+	if err != nil {
+		return id, err
+	}
+
+	err = blob.Finalize(ctx, cmd.Filepath)
+	if err != nil {
+		return id, fmt.Errorf("error finalizing bytes: %w", err)
+	}
+
 	err = sc.syncRepo.CreateChangelogEntries(ctx, []domain.ChangelogEntry{*entry})
 	if err != nil {
 		return id, fmt.Errorf("error saving changelog entry to DB: %w", err)
 	}
 
+	if !cmd.IsEncrytped {
+		err = sc.attachments.CreateAttachment(ctx, &domain.Attachment{
+			ID:        id,
+			Sha256:    cmd.sha256,
+			Filepath:  cmd.Filepath,
+			SizeBytes: cmd.sizeBytes,
+		})
+		if err != nil {
+			return id, fmt.Errorf("error saving attachment to DB: %w", err)
+		}
+	}
+
 	return id, nil
 }
 
-func (sc *SyncController) writeUnencryptedDataForAttachmentChangelogEntry(cmd *CreateAttachmentChangelogEntryCmd, blob storage.BlobTarget) error {
+// writeUnencryptedDataForAttachmentChangelogEntry hashes the plaintext while encrypting it into the blob, so the
+// plaintext is never written to disk. The filepath and deduplication depend on the hash.
+func (sc *SyncController) writeUnencryptedDataForAttachmentChangelogEntry(cmd *CreateAttachmentChangelogEntryCmd, blob storage.BlobTarget) (err error) {
 	h := sha256.New()
 
-	tee := io.TeeReader(cmd.Data, h)
+	// the encrypted blob is larger than the plaintext, the stored size is what counts towards the quota
+	stored := &countingWriter{w: blob}
 
-	encrypterClosed := false
-
-	encrypter, err := age.Encrypt(blob, cmd.recipient)
+	encrypter, err := age.Encrypt(stored, cmd.recipient)
 	if err != nil {
 		return fmt.Errorf("error starting encrypter: %w", err)
 	}
 
-	defer func() {
-		if !encrypterClosed {
-			closeErr := encrypter.Close()
-			if closeErr != nil {
-				err = errors.Join(err, fmt.Errorf("error closing encrypter: %w", closeErr))
-			}
-		}
-	}()
+	cmd.sizeBytes, err = io.Copy(encrypter, io.TeeReader(cmd.Data, h))
+	if err != nil {
+		return errors.Join(fmt.Errorf("error copying bytes: %w", err), encrypter.Close())
+	}
 
-	cmd.sizeBytes, err = io.Copy(encrypter, tee)
+	err = encrypter.Close()
 	if err != nil {
-		return fmt.Errorf("error copying bytes: %w", err)
+		return fmt.Errorf("error closing encrypter: %w", err)
 	}
 
 	cmd.sha256 = h.Sum(nil)
@@ -330,18 +575,13 @@ func (sc *SyncController) writeUnencryptedDataForAttachmentChangelogEntry(cmd *C
 		cmd.Filepath = cmd.Filepath + "/" + fmt.Sprintf("%02x", b)
 	}
 
-	encrypterClosed = true
-
-	err = encrypter.Close()
-	if err != nil {
-		return fmt.Errorf("error closing encrypter: %w", err)
-	}
+	cmd.storedSizeBytes = stored.n
 
 	return nil
 }
 
 func (sc *SyncController) writeEncryptedDataForAttachmentChangelogEntry(cmd *CreateAttachmentChangelogEntryCmd, blob storage.BlobTarget) (err error) {
-	_, err = io.Copy(blob, cmd.Data)
+	cmd.storedSizeBytes, err = io.Copy(blob, cmd.Data)
 	if err != nil {
 		return fmt.Errorf("error copying bytes: %w", err)
 	}
@@ -508,3 +748,15 @@ type createAttachmentChangelogEntry struct {
 	IsSynced  bool      `json:"isSynced,omitempty"`
 	IsApplied bool      `json:"isApplied,omitempty"`
 }
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+
+	return n, err
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.robinthrift.com/conveyor/internal/auth"
 	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/jobs"
 	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
 	"go.robinthrift.com/conveyor/internal/storage/filesystem"
 	"go.robinthrift.com/conveyor/internal/testhelper"
@@ -91,9 +92,9 @@ func TestSyncController_CreateAttachmentChangelogEntry_Unencrypted(t *testing.T)
 
 	entries, err := setup.syncCtrl.ListChangelogEntries(ctx, ListChangelogEntriesQuery{})
 	require.NoError(t, err)
-	assert.Len(t, entries, 1)
+	assert.Len(t, entries.Items, 1)
 
-	entryJSON := testhelper.AgeDecrypt(t, privateKey, bytes.NewReader(entries[0].Data))
+	entryJSON := testhelper.AgeDecrypt(t, privateKey, bytes.NewReader(entries.Items[0].Data))
 
 	var entry createAttachmentChangelogEntry
 
@@ -105,6 +106,65 @@ func TestSyncController_CreateAttachmentChangelogEntry_Unencrypted(t *testing.T)
 	assert.Equal(t, "test.txt", entry.Value.Created.OriginalFilename)
 	assert.Equal(t, "text/plain", entry.Value.Created.ContentType)
 	assert.Equal(t, "c189dadcf9db36cce18af55697616eee1807e34c4395703d5bcf46219750a2e0", entry.Value.Created.Sha256)
+
+	// the encrypted blob is reserved and released, not the plaintext
+	stat, err := file.Stat()
+	require.NoError(t, err)
+
+	report, err := setup.usageCtrl.GetUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, stat.Size()+int64(len(entries.Items[0].Data)), report.Usage.SizeBytes)
+
+	err = setup.syncCtrl.DeleteAttachment(ctx, DeleteAttachmentCmd{Filepath: entry.Value.Created.Filepath[1:]})
+	require.NoError(t, err)
+
+	report, err = setup.usageCtrl.GetUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(entries.Items[0].Data)), report.Usage.SizeBytes)
+}
+
+func TestSyncController_CreateAttachmentChangelogEntry_Deduplicated(t *testing.T) {
+	t.Parallel()
+	setup := setupSyncCtrlTest(t)
+	ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+	content := "TestSyncController_CreateAttachmentChangelogEntry_Deduplicated"
+
+	firstID, err := setup.syncCtrl.CreateAttachmentChangelogEntry(ctx, CreateAttachmentChangelogEntryCmd{
+		OriginalFilename: "first.txt",
+		ContentType:      "text/plain",
+		Data:             bytes.NewReader([]byte(content)),
+	})
+	require.NoError(t, err)
+
+	notify, unsubscribe := setup.syncCtrl.changes.subscribe(domain.AccountID(1))
+	t.Cleanup(unsubscribe)
+
+	secondID, err := setup.syncCtrl.CreateAttachmentChangelogEntry(ctx, CreateAttachmentChangelogEntryCmd{
+		OriginalFilename: "second.txt",
+		ContentType:      "text/plain",
+		Data:             bytes.NewReader([]byte(content)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, firstID, secondID)
+
+	select {
+	case <-notify:
+		t.Fatal("subscribers notified about deduplicated attachment")
+	default:
+	}
+
+	entries, err := setup.syncCtrl.ListChangelogEntries(ctx, ListChangelogEntriesQuery{})
+	require.NoError(t, err)
+	assert.Len(t, entries.Items, 1)
+
+	otherID, err := setup.syncCtrl.CreateAttachmentChangelogEntry(ctx, CreateAttachmentChangelogEntryCmd{
+		OriginalFilename: "other.txt",
+		ContentType:      "text/plain",
+		Data:             bytes.NewReader([]byte(content + "_other")),
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, firstID, otherID)
 }
 
 func TestSyncController_CreateAttachmentChangelogEntry_Encrypted(t *testing.T) {
@@ -138,9 +198,9 @@ func TestSyncController_CreateAttachmentChangelogEntry_Encrypted(t *testing.T) {
 
 	entries, err := setup.syncCtrl.ListChangelogEntries(ctx, ListChangelogEntriesQuery{})
 	require.NoError(t, err)
-	assert.Len(t, entries, 1)
+	assert.Len(t, entries.Items, 1)
 
-	entryJSON := testhelper.AgeDecrypt(t, privateKey, bytes.NewReader(entries[0].Data))
+	entryJSON := testhelper.AgeDecrypt(t, privateKey, bytes.NewReader(entries.Items[0].Data))
 
 	var entry createAttachmentChangelogEntry
 
@@ -154,9 +214,118 @@ func TestSyncController_CreateAttachmentChangelogEntry_Encrypted(t *testing.T) {
 	assert.Equal(t, "c189dadcf9db36cce18af55697616eee1807e34c4395703d5bcf46219750a2e0", entry.Value.Created.Sha256)
 }
 
+func TestSyncController_ListChangelogEntries_ExcludeClientID(t *testing.T) {
+	t.Parallel()
+	setup := setupSyncCtrlTest(t)
+	ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+	err := setup.syncCtrl.RegisterClient(ctx, RegisterClientCmd{ClientID: "client_a"})
+	require.NoError(t, err)
+
+	err = setup.syncCtrl.CreateChangelogEntries(ctx, CreateChangelogEntriesCmd{
+		Entries: []domain.ChangelogEntry{
+			{SyncClientID: "client_a", Data: []byte("a")},
+			{SyncClientID: "client_b", Data: []byte("b")},
+		},
+	})
+	require.NoError(t, err)
+
+	entries, err := setup.syncCtrl.ListChangelogEntries(ctx, ListChangelogEntriesQuery{ExcludeClientID: "client_a"})
+	require.NoError(t, err)
+	require.Len(t, entries.Items, 1)
+	assert.Equal(t, domain.SyncClientID("client_b"), entries.Items[0].SyncClientID)
+
+	_, err = setup.syncCtrl.ListChangelogEntries(ctx, ListChangelogEntriesQuery{ExcludeClientID: "client_b"})
+	assert.ErrorIs(t, err, domain.ErrSyncClientNotFound)
+}
+
+func TestSyncController_Quota(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Changelog Entries", func(t *testing.T) {
+		t.Parallel()
+		setup := setupSyncCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		maxEntries := int64(2)
+		err := setup.usageCtrl.SetQuotaOverride(auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1), IsAdmin: true}), SetQuotaOverrideCmd{AccountID: domain.AccountID(1), MaxEntries: &maxEntries})
+		require.NoError(t, err)
+
+		err = setup.syncCtrl.CreateChangelogEntries(ctx, CreateChangelogEntriesCmd{
+			Entries: []domain.ChangelogEntry{{SyncClientID: "client_a", Data: []byte("entry_0")}},
+		})
+		require.NoError(t, err)
+
+		err = setup.syncCtrl.CreateChangelogEntries(ctx, CreateChangelogEntriesCmd{
+			Entries: []domain.ChangelogEntry{
+				{SyncClientID: "client_a", Data: []byte("entry_1")},
+				{SyncClientID: "client_a", Data: []byte("entry_2")},
+			},
+		})
+		require.ErrorIs(t, err, domain.ErrQuotaExceeded)
+
+		entries, err := setup.syncCtrl.ListChangelogEntries(ctx, ListChangelogEntriesQuery{})
+		require.NoError(t, err)
+		assert.Len(t, entries.Items, 1)
+
+		report, err := setup.usageCtrl.GetUsage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len("entry_0")), report.Usage.SizeBytes)
+		assert.Equal(t, int64(1), report.Usage.NumEntries)
+		assert.Equal(t, maxEntries, report.Quota.MaxEntries)
+	})
+
+	t.Run("Full DB", func(t *testing.T) {
+		t.Parallel()
+		setup := setupSyncCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		maxSizeBytes := int64(10)
+		err := setup.usageCtrl.SetQuotaOverride(auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1), IsAdmin: true}), SetQuotaOverrideCmd{AccountID: domain.AccountID(1), MaxSizeBytes: &maxSizeBytes})
+		require.NoError(t, err)
+
+		err = setup.syncCtrl.SaveFullDB(ctx, SaveFullDBCmd{Data: bytes.NewReader([]byte("0123456789_"))})
+		require.ErrorIs(t, err, domain.ErrQuotaExceeded)
+
+		_, err = os.Stat(path.Join(setup.blobDir, "1", "dbs"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+
+		report, err := setup.usageCtrl.GetUsage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), report.Usage.SizeBytes)
+	})
+
+	t.Run("Attachments", func(t *testing.T) {
+		t.Parallel()
+		setup := setupSyncCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		maxSizeBytes := int64(10)
+		err := setup.usageCtrl.SetQuotaOverride(auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1), IsAdmin: true}), SetQuotaOverrideCmd{AccountID: domain.AccountID(1), MaxSizeBytes: &maxSizeBytes})
+		require.NoError(t, err)
+
+		err = setup.syncCtrl.StoreAttachment(ctx, StoreAttachmentCmd{Filepath: "a/b/test.txt", Content: bytes.NewReader([]byte("0123456789"))})
+		require.NoError(t, err)
+
+		err = setup.syncCtrl.StoreAttachment(ctx, StoreAttachmentCmd{Filepath: "a/b/other.txt", Content: bytes.NewReader([]byte("0"))})
+		require.ErrorIs(t, err, domain.ErrQuotaExceeded)
+
+		err = setup.syncCtrl.DeleteAttachment(ctx, DeleteAttachmentCmd{Filepath: "a/b/test.txt"})
+		require.NoError(t, err)
+
+		err = setup.syncCtrl.StoreAttachment(ctx, StoreAttachmentCmd{Filepath: "a/b/other.txt", Content: bytes.NewReader([]byte("0"))})
+		require.NoError(t, err)
+
+		report, err := setup.usageCtrl.GetUsage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), report.Usage.SizeBytes)
+	})
+}
+
 type syncCtrlTestSetup struct {
-	syncCtrl *SyncController
-	blobDir  string
+	syncCtrl  *SyncController
+	usageCtrl *UsageController
+	blobDir   string
 }
 
 func setupSyncCtrlTest(t *testing.T) syncCtrlTestSetup {
@@ -187,11 +356,14 @@ func setupSyncCtrlTest(t *testing.T) syncCtrlTestSetup {
 		t.Fatal(err)
 	}
 
-	accountCtrl := NewAccountController(db, accountRepo)
-	attachmentCtrl := NewAttachmentController(blobs)
+	accountCtrl := NewAccountController(db, accountRepo, blobs)
+	usageCtrl := NewUsageController(QuotaConfig{}, sqlite.NewUsageRepo(db))
+	attachmentCtrl := NewAttachmentController(db, blobs, sqlite.NewAttachmentRepo(db), usageCtrl)
+	jobSystem := jobs.NewSystem(jobs.SystemConfig{}, db, sqlite.NewJobRepo(db), accountCtrl, time.Now, nil)
 
 	return syncCtrlTestSetup{
-		syncCtrl: NewSyncController(db, syncRepo, accountCtrl, attachmentCtrl, blobs),
-		blobDir:  blobDir,
+		syncCtrl:  NewSyncController(SyncConfig{}, db, syncRepo, accountCtrl, attachmentCtrl, usageCtrl, blobs, jobSystem),
+		usageCtrl: usageCtrl,
+		blobDir:   blobDir,
 	}
 }
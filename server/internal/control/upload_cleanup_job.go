@@ -0,0 +1,79 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage"
+	"go.robinthrift.com/conveyor/internal/storage/database"
+)
+
+const CleanupExpiredUploadsJobKind = "cleanup_expired_uploads"
+
+type CleanupExpiredUploadsJobData struct {
+	AccountID domain.AccountID
+}
+
+type CleanupExpiredUploadsJob struct {
+	transactioner database.Transactioner
+	repo          CleanupExpiredUploadsJobRepo
+	blobs         CleanupExpiredUploadsJobBlobStorage
+	usageRepo     CleanupExpiredUploadsJobUsageRepo
+	now           func() time.Time
+}
+
+type CleanupExpiredUploadsJobRepo interface {
+	ListExpiredUploads(ctx context.Context, accountID domain.AccountID, now time.Time) ([]*domain.Upload, error)
+	DeleteUpload(ctx context.Context, upload *domain.Upload) error
+}
+
+type CleanupExpiredUploadsJobBlobStorage interface {
+	StatBlob(ctx context.Context, accountID domain.AccountID, filepath string) (*storage.BlobInfo, error)
+	RemoveBlob(ctx context.Context, accountID domain.AccountID, filepath string) error
+}
+
+type CleanupExpiredUploadsJobUsageRepo interface {
+	AddAccountUsage(ctx context.Context, usage *domain.AccountUsage) error
+}
+
+func NewCleanupExpiredUploadsJob(transactioner database.Transactioner, repo CleanupExpiredUploadsJobRepo, blobs CleanupExpiredUploadsJobBlobStorage, usageRepo CleanupExpiredUploadsJobUsageRepo) *CleanupExpiredUploadsJob {
+	return &CleanupExpiredUploadsJob{transactioner: transactioner, repo: repo, blobs: blobs, usageRepo: usageRepo, now: time.Now}
+}
+
+// Exec deletes all expired uploads of the account including their chunks and releases the size reserved for them.
+func (j *CleanupExpiredUploadsJob) Exec(ctx context.Context, data CleanupExpiredUploadsJobData) (*domain.JobResult, error) {
+	uploads, err := j.repo.ListExpiredUploads(ctx, data.AccountID, j.now())
+	if err != nil {
+		return nil, err
+	}
+
+	var reclaimed int64
+
+	for _, upload := range uploads {
+		err = removeUploadChunks(ctx, j.blobs, upload)
+		if err != nil {
+			return nil, err
+		}
+
+		err = j.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+			err := j.repo.DeleteUpload(ctx, upload)
+			if err != nil {
+				return err
+			}
+
+			return j.usageRepo.AddAccountUsage(ctx, &domain.AccountUsage{AccountID: upload.AccountID, SizeBytes: -upload.SizeBytes})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		reclaimed += upload.OffsetBytes
+	}
+
+	return &domain.JobResult{
+		Message:        fmt.Sprintf("deleted %d expired uploads", len(uploads)),
+		BytesReclaimed: reclaimed,
+	}, nil
+}
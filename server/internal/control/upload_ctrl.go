@@ -0,0 +1,367 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage"
+	"go.robinthrift.com/conveyor/internal/storage/database"
+	"go.robinthrift.com/conveyor/internal/x/httpbody"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type UploadConfig struct {
+	Expiry time.Duration
+}
+
+// UploadController implements resumable uploads: the data is appended in chunks, each of which is stored as a separate blob,
+// so an interrupted upload can be resumed from the last stored chunk. Once all chunks are uploaded and the SHA-256 hash matches,
+// the upload is finalized by storing it like a regular full sync snapshot or attachment.
+// The declared size is reserved from the account's quota when the upload is created and released again when the upload
+// is finalized or expires.
+type UploadController struct {
+	config        UploadConfig
+	transactioner database.Transactioner
+	repo          UploadControllerRepo
+	blobs         UploadControllerBlobStorage
+	syncCtrl      *SyncController
+	usage         *UsageController
+	scheduler     UploadControllerJobScheduler
+	now           func() time.Time
+}
+
+type UploadControllerRepo interface {
+	GetUpload(ctx context.Context, accountID domain.AccountID, id domain.UploadID) (*domain.Upload, error)
+	CreateUpload(ctx context.Context, upload *domain.Upload) error
+	UpdateUploadOffset(ctx context.Context, upload *domain.Upload, prevOffsetBytes int64) error
+	DeleteUpload(ctx context.Context, upload *domain.Upload) error
+}
+
+type UploadControllerBlobStorage interface {
+	OpenBlobTarget(ctx context.Context, accountID domain.AccountID, originalFilename string) (storage.BlobTarget, error)
+	OpenBlob(ctx context.Context, accountID domain.AccountID, filepath string) (io.ReadCloser, error)
+	StatBlob(ctx context.Context, accountID domain.AccountID, filepath string) (*storage.BlobInfo, error)
+	RemoveBlob(ctx context.Context, accountID domain.AccountID, filepath string) error
+}
+
+type UploadControllerJobScheduler interface {
+	Schedule(ctx context.Context, job *domain.Job) error
+}
+
+func NewUploadController(config UploadConfig, transactioner database.Transactioner, repo UploadControllerRepo, blobs UploadControllerBlobStorage, syncCtrl *SyncController, usage *UsageController, scheduler UploadControllerJobScheduler) *UploadController {
+	return &UploadController{config, transactioner, repo, blobs, syncCtrl, usage, scheduler, time.Now}
+}
+
+type CreateUploadCmd struct {
+	Kind      domain.UploadKind
+	Filepath  string
+	SizeBytes int64
+	Sha256    []byte
+}
+
+func (uc *UploadController) CreateUpload(ctx context.Context, cmd CreateUploadCmd) (*domain.Upload, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, auth.ErrUnauthorized
+	}
+
+	upload := &domain.Upload{
+		AccountID: account.ID,
+		Kind:      cmd.Kind,
+		Sha256:    cmd.Sha256,
+		SizeBytes: cmd.SizeBytes,
+	}
+
+	switch cmd.Kind {
+	case domain.UploadKindFullSync:
+	case domain.UploadKindAttachment:
+		filepath, ok := cleanAttachmentFilepath(strings.TrimPrefix(cmd.Filepath, "/"))
+		if !ok {
+			return nil, domain.ErrInvalidAttachmentFilepath
+		}
+
+		upload.Filepath = filepath
+	default:
+		return nil, fmt.Errorf("%w: unknown kind %s", domain.ErrInvalidUpload, cmd.Kind)
+	}
+
+	if len(cmd.Sha256) != sha256.Size {
+		return nil, fmt.Errorf("%w: invalid SHA-256 hash", domain.ErrInvalidUpload)
+	}
+
+	if cmd.SizeBytes <= 0 {
+		return nil, fmt.Errorf("%w: size must be positive", domain.ErrInvalidUpload)
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.now()
+	upload.ID = domain.UploadID(id)
+	upload.CreatedAt = now
+	upload.UpdatedAt = now
+	upload.ExpiresAt = now.Add(uc.config.Expiry)
+
+	err = uc.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		err := uc.usage.Reserve(ctx, cmd.SizeBytes, 0)
+		if err != nil {
+			return err
+		}
+
+		err = uc.repo.CreateUpload(ctx, upload)
+		if err != nil {
+			return err
+		}
+
+		return uc.scheduler.Schedule(ctx, &domain.Job{
+			Kind:         CleanupExpiredUploadsJobKind,
+			Data:         CleanupExpiredUploadsJobData{AccountID: account.ID},
+			ScheduledFor: upload.ExpiresAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+func (uc *UploadController) GetUpload(ctx context.Context, id domain.UploadID) (*domain.Upload, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, auth.ErrUnauthorized
+	}
+
+	upload, err := uc.repo.GetUpload(ctx, account.ID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !uc.now().Before(upload.ExpiresAt) {
+		return nil, domain.ErrUploadNotFound
+	}
+
+	return upload, nil
+}
+
+type AppendUploadChunkCmd struct {
+	ID          domain.UploadID
+	OffsetBytes int64
+	Data        io.Reader
+}
+
+// AppendUploadChunk stores the data as the next chunk of the upload. The offset must match the upload's current offset,
+// otherwise [domain.ErrUploadOffsetMismatch] is returned and the client has to resume from the current offset.
+// A chunk that is interrupted is discarded completely.
+func (uc *UploadController) AppendUploadChunk(ctx context.Context, cmd AppendUploadChunkCmd) (_ *domain.Upload, err error) {
+	upload, err := uc.GetUpload(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.OffsetBytes != upload.OffsetBytes {
+		return nil, fmt.Errorf("%w: expected offset %d", domain.ErrUploadOffsetMismatch, upload.OffsetBytes)
+	}
+
+	// the chunk is written to a temporary blob and only moved into place once the offset has been claimed,
+	// so concurrent requests for the same offset can't overwrite each other's chunks
+	chunk, err := uc.blobs.OpenBlobTarget(ctx, upload.AccountID, string(upload.ID))
+	if err != nil {
+		return nil, fmt.Errorf("error opening blob target: %w", err)
+	}
+
+	defer func() {
+		err = errors.Join(err, chunk.Close())
+	}()
+
+	sizeBytes, err := io.Copy(chunk, httpbody.LimitReaderWithError(cmd.Data, upload.SizeBytes-upload.OffsetBytes, domain.ErrUploadTooLarge))
+	if err != nil {
+		return nil, err
+	}
+
+	if sizeBytes == 0 {
+		return upload, nil
+	}
+
+	prevOffsetBytes := upload.OffsetBytes
+	upload.OffsetBytes += sizeBytes
+
+	err = uc.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		err := uc.repo.UpdateUploadOffset(ctx, upload, prevOffsetBytes)
+		if err != nil {
+			return err
+		}
+
+		return chunk.Finalize(ctx, uploadChunkFilepath(upload.ID, prevOffsetBytes))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+// FinalizeUpload verifies the SHA-256 hash of the complete upload and stores it as a full sync snapshot or attachment.
+// If the hash doesn't match, the upload is deleted and [domain.ErrUploadChecksumMismatch] is returned.
+func (uc *UploadController) FinalizeUpload(ctx context.Context, id domain.UploadID) error {
+	upload, err := uc.GetUpload(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if upload.OffsetBytes != upload.SizeBytes {
+		return fmt.Errorf("%w: %d of %d bytes uploaded", domain.ErrUploadIncomplete, upload.OffsetBytes, upload.SizeBytes)
+	}
+
+	chunks := &uploadChunkReader{ctx: ctx, blobs: uc.blobs, upload: upload}
+	defer chunks.Close()
+
+	data := &sha256VerifyingReader{r: chunks, h: sha256.New(), expected: upload.Sha256}
+
+	var checksumErr error
+
+	err = uc.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		// the reservation made when creating the upload is replaced by the size of the stored data
+		err := uc.usage.Release(ctx, upload.SizeBytes, 0)
+		if err != nil {
+			return err
+		}
+
+		switch upload.Kind {
+		case domain.UploadKindFullSync:
+			err = uc.syncCtrl.SaveFullDB(ctx, SaveFullDBCmd{Data: data})
+		case domain.UploadKindAttachment:
+			err = uc.syncCtrl.StoreAttachment(ctx, StoreAttachmentCmd{Filepath: upload.Filepath, Content: data})
+		default:
+			err = fmt.Errorf("unknown upload kind: %s", upload.Kind)
+		}
+
+		if err != nil {
+			if !errors.Is(err, domain.ErrUploadChecksumMismatch) {
+				return err
+			}
+
+			checksumErr = err
+		}
+
+		return uc.repo.DeleteUpload(ctx, upload)
+	})
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(checksumErr, removeUploadChunks(ctx, uc.blobs, upload))
+}
+
+func uploadChunkFilepath(id domain.UploadID, offsetBytes int64) string {
+	return path.Join("uploads", string(id), strconv.FormatInt(offsetBytes, 10))
+}
+
+type uploadChunkBlobStorage interface {
+	StatBlob(ctx context.Context, accountID domain.AccountID, filepath string) (*storage.BlobInfo, error)
+	RemoveBlob(ctx context.Context, accountID domain.AccountID, filepath string) error
+}
+
+// removeUploadChunks removes all chunks stored for the upload. Each chunk is stored at the offset it starts at,
+// so the chunks are found by following their sizes.
+func removeUploadChunks(ctx context.Context, blobs uploadChunkBlobStorage, upload *domain.Upload) error {
+	var offsetBytes int64
+
+	for offsetBytes < upload.OffsetBytes {
+		filepath := uploadChunkFilepath(upload.ID, offsetBytes)
+
+		info, err := blobs.StatBlob(ctx, upload.AccountID, filepath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return fmt.Errorf("error getting upload chunk info: %w", err)
+		}
+
+		err = blobs.RemoveBlob(ctx, upload.AccountID, filepath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error removing upload chunk: %w", err)
+		}
+
+		offsetBytes += info.SizeBytes
+	}
+
+	return nil
+}
+
+// uploadChunkReader reads all chunks of an upload in order, opening each chunk only once the previous one is read completely.
+type uploadChunkReader struct {
+	ctx         context.Context //nolint:containedctx // Read doesn't take a context
+	blobs       UploadControllerBlobStorage
+	upload      *domain.Upload
+	offsetBytes int64
+	current     io.ReadCloser
+}
+
+func (r *uploadChunkReader) Read(p []byte) (int, error) {
+	if r.current == nil {
+		if r.offsetBytes >= r.upload.OffsetBytes {
+			return 0, io.EOF
+		}
+
+		chunk, err := r.blobs.OpenBlob(r.ctx, r.upload.AccountID, uploadChunkFilepath(r.upload.ID, r.offsetBytes))
+		if err != nil {
+			return 0, fmt.Errorf("error opening upload chunk: %w", err)
+		}
+
+		r.current = chunk
+	}
+
+	n, err := r.current.Read(p)
+	r.offsetBytes += int64(n)
+
+	if errors.Is(err, io.EOF) {
+		err = r.current.Close()
+		r.current = nil
+	}
+
+	return n, err
+}
+
+func (r *uploadChunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+
+	return r.current.Close()
+}
+
+// sha256VerifyingReader returns [domain.ErrUploadChecksumMismatch] instead of [io.EOF] if the data doesn't match the expected hash,
+// which makes the blob storage discard the data instead of finalizing it.
+type sha256VerifyingReader struct {
+	r        io.Reader
+	h        hash.Hash
+	expected []byte
+}
+
+func (vr *sha256VerifyingReader) Read(p []byte) (int, error) {
+	n, err := vr.r.Read(p)
+	vr.h.Write(p[:n])
+
+	if errors.Is(err, io.EOF) && !bytes.Equal(vr.h.Sum(nil), vr.expected) {
+		return n, domain.ErrUploadChecksumMismatch
+	}
+
+	return n, err
+}
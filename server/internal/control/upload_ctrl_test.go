@@ -0,0 +1,361 @@
+package control
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/jobs"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
+	"go.robinthrift.com/conveyor/internal/storage/filesystem"
+	"go.robinthrift.com/conveyor/internal/testhelper"
+)
+
+func TestUploadController(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("0123456789abcdefghij")
+	hash := sha256.Sum256(content)
+
+	t.Run("Full Sync", func(t *testing.T) {
+		t.Parallel()
+		setup := setupUploadCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		upload, err := setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{
+			Kind:      domain.UploadKindFullSync,
+			SizeBytes: int64(len(content)),
+			Sha256:    hash[:],
+		})
+		require.NoError(t, err)
+
+		upload, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: upload.ID, OffsetBytes: 0, Data: bytes.NewReader(content[:8])})
+		require.NoError(t, err)
+		assert.Equal(t, int64(8), upload.OffsetBytes)
+
+		err = setup.uploadCtrl.FinalizeUpload(ctx, upload.ID)
+		require.ErrorIs(t, err, domain.ErrUploadIncomplete)
+
+		_, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: upload.ID, OffsetBytes: 0, Data: bytes.NewReader(content[8:])})
+		require.ErrorIs(t, err, domain.ErrUploadOffsetMismatch)
+
+		upload, err = setup.uploadCtrl.GetUpload(ctx, upload.ID)
+		require.NoError(t, err)
+
+		upload, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: upload.ID, OffsetBytes: upload.OffsetBytes, Data: bytes.NewReader(content[upload.OffsetBytes:])})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), upload.OffsetBytes)
+
+		err = setup.uploadCtrl.FinalizeUpload(ctx, upload.ID)
+		require.NoError(t, err)
+
+		entry, err := setup.syncCtrl.GetLatestFullSyncEntry(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, hash[:], entry.Sha256Hash)
+
+		_, err = setup.uploadCtrl.GetUpload(ctx, upload.ID)
+		require.ErrorIs(t, err, domain.ErrUploadNotFound)
+		assert.NoDirExists(t, path.Join(setup.blobDir, "1", "uploads", string(upload.ID)))
+	})
+
+	t.Run("Attachment", func(t *testing.T) {
+		t.Parallel()
+		setup := setupUploadCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		upload, err := setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{
+			Kind:      domain.UploadKindAttachment,
+			Filepath:  "a/b/test.txt",
+			SizeBytes: int64(len(content)),
+			Sha256:    hash[:],
+		})
+		require.NoError(t, err)
+
+		for offset := 0; offset < len(content); offset += 6 {
+			_, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{
+				ID:          upload.ID,
+				OffsetBytes: int64(offset),
+				Data:        bytes.NewReader(content[offset:min(offset+6, len(content))]),
+			})
+			require.NoError(t, err)
+		}
+
+		err = setup.uploadCtrl.FinalizeUpload(ctx, upload.ID)
+		require.NoError(t, err)
+
+		stored, err := os.ReadFile(path.Join(setup.blobDir, "1", "a", "b", "test.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, content, stored)
+	})
+
+	t.Run("Checksum Mismatch", func(t *testing.T) {
+		t.Parallel()
+		setup := setupUploadCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		upload, err := setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{
+			Kind:      domain.UploadKindFullSync,
+			SizeBytes: int64(len(content)),
+			Sha256:    hash[:],
+		})
+		require.NoError(t, err)
+
+		_, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: upload.ID, Data: bytes.NewReader(bytes.ToUpper(content))})
+		require.NoError(t, err)
+
+		err = setup.uploadCtrl.FinalizeUpload(ctx, upload.ID)
+		require.ErrorIs(t, err, domain.ErrUploadChecksumMismatch)
+
+		_, err = setup.syncCtrl.GetLatestFullSyncEntry(ctx)
+		require.ErrorIs(t, err, domain.ErrNoFullSyncEntriesFound)
+
+		_, err = setup.uploadCtrl.GetUpload(ctx, upload.ID)
+		require.ErrorIs(t, err, domain.ErrUploadNotFound)
+
+		report, err := setup.usageCtrl.GetUsage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), report.Usage.SizeBytes)
+	})
+
+	t.Run("Too Large", func(t *testing.T) {
+		t.Parallel()
+		setup := setupUploadCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		upload, err := setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{
+			Kind:      domain.UploadKindFullSync,
+			SizeBytes: 4,
+			Sha256:    hash[:],
+		})
+		require.NoError(t, err)
+
+		_, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: upload.ID, Data: bytes.NewReader(content)})
+		require.ErrorIs(t, err, domain.ErrUploadTooLarge)
+
+		upload, err = setup.uploadCtrl.GetUpload(ctx, upload.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), upload.OffsetBytes)
+	})
+
+	t.Run("Quota Exceeded", func(t *testing.T) {
+		t.Parallel()
+		setup := setupUploadCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		maxSizeBytes := int64(10)
+		err := setup.usageCtrl.SetQuotaOverride(auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1), IsAdmin: true}), SetQuotaOverrideCmd{AccountID: domain.AccountID(1), MaxSizeBytes: &maxSizeBytes})
+		require.NoError(t, err)
+
+		_, err = setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{
+			Kind:      domain.UploadKindFullSync,
+			SizeBytes: int64(len(content)),
+			Sha256:    hash[:],
+		})
+		require.ErrorIs(t, err, domain.ErrQuotaExceeded)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		setup := setupUploadCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		_, err := setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{Kind: domain.UploadKindFullSync, SizeBytes: 10, Sha256: []byte("short")})
+		require.ErrorIs(t, err, domain.ErrInvalidUpload)
+
+		_, err = setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{Kind: domain.UploadKindFullSync, SizeBytes: 0, Sha256: hash[:]})
+		require.ErrorIs(t, err, domain.ErrInvalidUpload)
+
+		for _, filepath := range []string{"../../etc/passwd", "dbs/conveyor_1.db", "uploads/id/0"} {
+			_, err = setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{Kind: domain.UploadKindAttachment, Filepath: filepath, SizeBytes: 10, Sha256: hash[:]})
+			require.ErrorIs(t, err, domain.ErrInvalidAttachmentFilepath, filepath)
+		}
+	})
+
+	t.Run("Quota Reserved", func(t *testing.T) {
+		t.Parallel()
+		setup := setupUploadCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		maxSizeBytes := int64(len(content)) + 5
+		err := setup.usageCtrl.SetQuotaOverride(auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1), IsAdmin: true}), SetQuotaOverrideCmd{AccountID: domain.AccountID(1), MaxSizeBytes: &maxSizeBytes})
+		require.NoError(t, err)
+
+		upload, err := setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{
+			Kind:      domain.UploadKindAttachment,
+			Filepath:  "a/b/test.txt",
+			SizeBytes: int64(len(content)),
+			Sha256:    hash[:],
+		})
+		require.NoError(t, err)
+
+		report, err := setup.usageCtrl.GetUsage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), report.Usage.SizeBytes)
+
+		_, err = setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{
+			Kind:      domain.UploadKindAttachment,
+			Filepath:  "a/b/other.txt",
+			SizeBytes: int64(len(content)),
+			Sha256:    hash[:],
+		})
+		require.ErrorIs(t, err, domain.ErrQuotaExceeded)
+
+		_, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: upload.ID, Data: bytes.NewReader(content)})
+		require.NoError(t, err)
+
+		err = setup.uploadCtrl.FinalizeUpload(ctx, upload.ID)
+		require.NoError(t, err)
+
+		report, err = setup.usageCtrl.GetUsage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), report.Usage.SizeBytes)
+	})
+
+	t.Run("Concurrent Chunks", func(t *testing.T) {
+		t.Parallel()
+		setup := setupUploadCtrlTest(t)
+		ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+		upload, err := setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{
+			Kind:      domain.UploadKindFullSync,
+			SizeBytes: int64(len(content)),
+			Sha256:    hash[:],
+		})
+		require.NoError(t, err)
+
+		// the second request for the same offset completes while the first one is still writing its chunk
+		data := &funcReader{read: func(p []byte) (int, error) {
+			_, err := setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: upload.ID, Data: bytes.NewReader(content[:8])})
+			require.NoError(t, err)
+
+			return copy(p, "XXXXXXXX"), io.EOF
+		}}
+
+		_, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: upload.ID, Data: data})
+		require.ErrorIs(t, err, domain.ErrUploadOffsetMismatch)
+
+		chunk, err := os.ReadFile(path.Join(setup.blobDir, "1", uploadChunkFilepath(upload.ID, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, content[:8], chunk)
+
+		_, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: upload.ID, OffsetBytes: 8, Data: bytes.NewReader(content[8:])})
+		require.NoError(t, err)
+
+		err = setup.uploadCtrl.FinalizeUpload(ctx, upload.ID)
+		require.NoError(t, err)
+	})
+}
+
+func TestCleanupExpiredUploadsJob(t *testing.T) {
+	t.Parallel()
+
+	setup := setupUploadCtrlTest(t)
+	ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+
+	content := []byte("0123456789")
+	hash := sha256.Sum256(content)
+
+	now := time.Now()
+	setup.uploadCtrl.now = func() time.Time { return now.Add(-2 * time.Hour) }
+
+	expired, err := setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{Kind: domain.UploadKindFullSync, SizeBytes: int64(len(content)), Sha256: hash[:]})
+	require.NoError(t, err)
+
+	_, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: expired.ID, Data: bytes.NewReader(content[:5])})
+	require.NoError(t, err)
+
+	setup.uploadCtrl.now = func() time.Time { return now }
+
+	active, err := setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{Kind: domain.UploadKindFullSync, SizeBytes: int64(len(content)), Sha256: hash[:]})
+	require.NoError(t, err)
+
+	_, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: active.ID, Data: bytes.NewReader(content[:5])})
+	require.NoError(t, err)
+
+	job := NewCleanupExpiredUploadsJob(setup.db, setup.uploadRepo, setup.blobs, sqlite.NewUsageRepo(setup.db))
+	job.now = func() time.Time { return now }
+
+	result, err := job.Exec(t.Context(), CleanupExpiredUploadsJobData{AccountID: domain.AccountID(1)})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), result.BytesReclaimed)
+
+	report, err := setup.usageCtrl.GetUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, active.SizeBytes, report.Usage.SizeBytes)
+
+	_, err = setup.uploadRepo.GetUpload(t.Context(), domain.AccountID(1), expired.ID)
+	require.ErrorIs(t, err, domain.ErrUploadNotFound)
+	assert.NoFileExists(t, path.Join(setup.blobDir, "1", uploadChunkFilepath(expired.ID, 0)))
+
+	_, err = setup.uploadRepo.GetUpload(t.Context(), domain.AccountID(1), active.ID)
+	require.NoError(t, err)
+
+	chunk, err := setup.blobs.OpenBlob(t.Context(), domain.AccountID(1), uploadChunkFilepath(active.ID, 0))
+	require.NoError(t, err)
+	defer chunk.Close()
+
+	data, err := io.ReadAll(chunk)
+	require.NoError(t, err)
+	assert.Equal(t, content[:5], data)
+}
+
+type funcReader struct {
+	read func(p []byte) (int, error)
+}
+
+func (r *funcReader) Read(p []byte) (int, error) {
+	return r.read(p)
+}
+
+type uploadCtrlTestSetup struct {
+	uploadCtrl *UploadController
+	syncCtrl   *SyncController
+	usageCtrl  *UsageController
+	db         *sqlite.SQLite
+	uploadRepo *sqlite.UploadRepo
+	blobs      *filesystem.LocalFSBlobStorage
+	blobDir    string
+}
+
+func setupUploadCtrlTest(t *testing.T) uploadCtrlTestSetup {
+	t.Helper()
+	db := testhelper.NewInMemTestSQLite(t)
+
+	blobDir := t.TempDir()
+	blobs := &filesystem.LocalFSBlobStorage{
+		BaseDir: blobDir,
+		TmpDir:  t.TempDir(),
+	}
+
+	accountRepo := sqlite.NewAccountRepo(db)
+	uploadRepo := sqlite.NewUploadRepo(db)
+
+	err := accountRepo.Create(t.Context(), &domain.Account{Username: t.Name(), Password: domain.AccountPassword{Password: []byte("1234"), Salt: []byte("1234")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountCtrl := NewAccountController(db, accountRepo, blobs)
+	usageCtrl := NewUsageController(QuotaConfig{}, sqlite.NewUsageRepo(db))
+	attachmentCtrl := NewAttachmentController(db, blobs, sqlite.NewAttachmentRepo(db), usageCtrl)
+	jobSystem := jobs.NewSystem(jobs.SystemConfig{}, db, sqlite.NewJobRepo(db), accountCtrl, time.Now, nil)
+	syncCtrl := NewSyncController(SyncConfig{}, db, sqlite.NewSyncRepo(db), accountCtrl, attachmentCtrl, usageCtrl, blobs, jobSystem)
+
+	return uploadCtrlTestSetup{
+		uploadCtrl: NewUploadController(UploadConfig{Expiry: time.Hour}, db, uploadRepo, blobs, syncCtrl, usageCtrl, jobSystem),
+		syncCtrl:   syncCtrl,
+		usageCtrl:  usageCtrl,
+		db:         db,
+		uploadRepo: uploadRepo,
+		blobs:      blobs,
+		blobDir:    blobDir,
+	}
+}
@@ -0,0 +1,216 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/domain"
+)
+
+type QuotaConfig struct {
+	MaxSizeBytes int64
+	MaxEntries   int64
+}
+
+type UsageController struct {
+	config QuotaConfig
+	repo   UsageControllerRepo
+}
+
+type UsageControllerRepo interface {
+	GetAccountUsage(ctx context.Context, accountID domain.AccountID) (*domain.AccountUsage, error)
+	AddAccountUsage(ctx context.Context, usage *domain.AccountUsage) error
+	GetAccountQuotaOverride(ctx context.Context, accountID domain.AccountID) (*domain.AccountQuotaOverride, error)
+	UpsertAccountQuotaOverride(ctx context.Context, override *domain.AccountQuotaOverride) error
+	DeleteAccountQuotaOverride(ctx context.Context, accountID domain.AccountID) error
+}
+
+func NewUsageController(config QuotaConfig, repo UsageControllerRepo) *UsageController {
+	return &UsageController{config, repo}
+}
+
+type UsageReport struct {
+	Usage *domain.AccountUsage
+	Quota *domain.AccountQuota
+}
+
+func (uc *UsageController) GetUsage(ctx context.Context) (*UsageReport, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, auth.ErrUnauthorized
+	}
+
+	usage, err := uc.repo.GetAccountUsage(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	quota, err := uc.getQuota(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UsageReport{Usage: usage, Quota: quota}, nil
+}
+
+// Reserve adds the size and number of entries to the account's usage, unless doing so would exceed the account's quota,
+// in which case [domain.ErrQuotaExceeded] is returned. It should be called in the same transaction that stores the data.
+func (uc *UsageController) Reserve(ctx context.Context, sizeBytes int64, numEntries int64) error {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return auth.ErrUnauthorized
+	}
+
+	err := uc.Check(ctx, sizeBytes, numEntries)
+	if err != nil {
+		return err
+	}
+
+	return uc.repo.AddAccountUsage(ctx, &domain.AccountUsage{
+		AccountID:  account.ID,
+		SizeBytes:  sizeBytes,
+		NumEntries: numEntries,
+	})
+}
+
+// Check returns [domain.ErrQuotaExceeded] if storing the size and number of entries would exceed the account's quota, without changing the usage.
+func (uc *UsageController) Check(ctx context.Context, sizeBytes int64, numEntries int64) error {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return auth.ErrUnauthorized
+	}
+
+	usage, err := uc.repo.GetAccountUsage(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+
+	quota, err := uc.getQuota(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+
+	if quota.MaxSizeBytes > 0 && usage.SizeBytes+sizeBytes > quota.MaxSizeBytes {
+		return fmt.Errorf("%w: storing %d bytes would exceed the limit of %d bytes", domain.ErrQuotaExceeded, sizeBytes, quota.MaxSizeBytes)
+	}
+
+	if quota.MaxEntries > 0 && usage.NumEntries+numEntries > quota.MaxEntries {
+		return fmt.Errorf("%w: storing %d entries would exceed the limit of %d entries", domain.ErrQuotaExceeded, numEntries, quota.MaxEntries)
+	}
+
+	return nil
+}
+
+// Release removes the size and number of entries from the account's usage, after the data was deleted.
+func (uc *UsageController) Release(ctx context.Context, sizeBytes int64, numEntries int64) error {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return auth.ErrUnauthorized
+	}
+
+	return uc.repo.AddAccountUsage(ctx, &domain.AccountUsage{
+		AccountID:  account.ID,
+		SizeBytes:  -sizeBytes,
+		NumEntries: -numEntries,
+	})
+}
+
+// LimitReader returns a reader that fails with [domain.ErrQuotaExceeded] as soon as more bytes are read than the account has left,
+// so uploads of unknown size are aborted early instead of being stored first.
+func (uc *UsageController) LimitReader(ctx context.Context, r io.Reader) (io.Reader, error) {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return nil, auth.ErrUnauthorized
+	}
+
+	quota, err := uc.getQuota(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quota.MaxSizeBytes <= 0 {
+		return r, nil
+	}
+
+	usage, err := uc.repo.GetAccountUsage(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quotaLimitedReader{r: r, remaining: quota.MaxSizeBytes - usage.SizeBytes}, nil
+}
+
+type SetQuotaOverrideCmd struct {
+	AccountID    domain.AccountID
+	MaxSizeBytes *int64
+	MaxEntries   *int64
+}
+
+// SetQuotaOverride replaces the default quota of the account. If neither limit is set, the override is removed.
+// Only admins can override quotas.
+func (uc *UsageController) SetQuotaOverride(ctx context.Context, cmd SetQuotaOverrideCmd) error {
+	_, err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if cmd.MaxSizeBytes == nil && cmd.MaxEntries == nil {
+		return uc.repo.DeleteAccountQuotaOverride(ctx, cmd.AccountID)
+	}
+
+	return uc.repo.UpsertAccountQuotaOverride(ctx, &domain.AccountQuotaOverride{
+		AccountID:    cmd.AccountID,
+		MaxSizeBytes: cmd.MaxSizeBytes,
+		MaxEntries:   cmd.MaxEntries,
+	})
+}
+
+func (uc *UsageController) getQuota(ctx context.Context, accountID domain.AccountID) (*domain.AccountQuota, error) {
+	quota := &domain.AccountQuota{
+		MaxSizeBytes: uc.config.MaxSizeBytes,
+		MaxEntries:   uc.config.MaxEntries,
+	}
+
+	override, err := uc.repo.GetAccountQuotaOverride(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountQuotaOverrideNotFound) {
+			return quota, nil
+		}
+
+		return nil, err
+	}
+
+	if override.MaxSizeBytes != nil {
+		quota.MaxSizeBytes = *override.MaxSizeBytes
+	}
+
+	if override.MaxEntries != nil {
+		quota.MaxEntries = *override.MaxEntries
+	}
+
+	return quota, nil
+}
+
+type quotaLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (qr *quotaLimitedReader) Read(p []byte) (int, error) {
+	// read one byte more than allowed to detect uploads exceeding the quota exactly at EOF
+	if int64(len(p)) > qr.remaining+1 {
+		p = p[:max(qr.remaining+1, 0)]
+	}
+
+	n, err := qr.r.Read(p)
+	qr.remaining -= int64(n)
+
+	if qr.remaining < 0 {
+		return n, domain.ErrQuotaExceeded
+	}
+
+	return n, err
+}
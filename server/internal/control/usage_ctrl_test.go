@@ -0,0 +1,45 @@
+package control
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
+	"go.robinthrift.com/conveyor/internal/testhelper"
+)
+
+func TestUsageController_SetQuotaOverride(t *testing.T) {
+	t.Parallel()
+
+	db := testhelper.NewInMemTestSQLite(t)
+
+	err := sqlite.NewAccountRepo(db).Create(t.Context(), &domain.Account{Username: t.Name(), Password: domain.AccountPassword{Password: []byte("1234"), Salt: []byte("1234")}})
+	require.NoError(t, err)
+
+	usageCtrl := NewUsageController(QuotaConfig{MaxSizeBytes: 100, MaxEntries: 10}, sqlite.NewUsageRepo(db))
+
+	ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+	adminCtx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(2), IsAdmin: true})
+
+	maxSizeBytes := int64(1000)
+
+	err = usageCtrl.SetQuotaOverride(ctx, SetQuotaOverrideCmd{AccountID: domain.AccountID(1), MaxSizeBytes: &maxSizeBytes})
+	require.ErrorIs(t, err, auth.ErrForbidden)
+
+	err = usageCtrl.SetQuotaOverride(adminCtx, SetQuotaOverrideCmd{AccountID: domain.AccountID(1), MaxSizeBytes: &maxSizeBytes})
+	require.NoError(t, err)
+
+	report, err := usageCtrl.GetUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.AccountQuota{MaxSizeBytes: 1000, MaxEntries: 10}, report.Quota)
+
+	err = usageCtrl.SetQuotaOverride(adminCtx, SetQuotaOverrideCmd{AccountID: domain.AccountID(1)})
+	require.NoError(t, err)
+
+	report, err = usageCtrl.GetUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.AccountQuota{MaxSizeBytes: 100, MaxEntries: 10}, report.Quota)
+}
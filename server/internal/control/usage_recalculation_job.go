@@ -0,0 +1,76 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage"
+	"go.robinthrift.com/conveyor/internal/storage/database"
+)
+
+const RecalculateUsageJobKind = "recalculate_usage"
+
+type RecalculateUsageJobData struct {
+	AccountID domain.AccountID
+}
+
+type RecalculateUsageJob struct {
+	transactioner database.Transactioner
+	usageRepo     RecalculateUsageJobUsageRepo
+	blobs         RecalculateUsageJobBlobStorage
+}
+
+type RecalculateUsageJobUsageRepo interface {
+	CalculateAccountUsage(ctx context.Context, accountID domain.AccountID) (*domain.AccountUsage, error)
+	SetAccountUsage(ctx context.Context, usage *domain.AccountUsage) error
+}
+
+type RecalculateUsageJobBlobStorage interface {
+	WalkAccountBlobs(ctx context.Context, accountID domain.AccountID, fn func(filepath string, info *storage.BlobInfo) error) error
+}
+
+func NewRecalculateUsageJob(transactioner database.Transactioner, usageRepo RecalculateUsageJobUsageRepo, blobs RecalculateUsageJobBlobStorage) *RecalculateUsageJob {
+	return &RecalculateUsageJob{transactioner: transactioner, usageRepo: usageRepo, blobs: blobs}
+}
+
+// Exec replaces the account's usage with the size of all blobs stored for the account plus the usage recorded in the database.
+// This includes blobs that have no database record, like attachments uploaded directly.
+// Upload chunks are skipped, as the size of a pending upload is reserved in full when the upload is created.
+func (j *RecalculateUsageJob) Exec(ctx context.Context, data RecalculateUsageJobData) (*domain.JobResult, error) {
+	var blobsSizeBytes int64
+
+	err := j.blobs.WalkAccountBlobs(ctx, data.AccountID, func(filepath string, info *storage.BlobInfo) error {
+		if !strings.HasPrefix(filepath, "uploads/") {
+			blobsSizeBytes += info.SizeBytes
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing blobs of account %d: %w", data.AccountID, err)
+	}
+
+	var usage *domain.AccountUsage
+
+	err = j.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		usage, err = j.usageRepo.CalculateAccountUsage(ctx, data.AccountID)
+		if err != nil {
+			return err
+		}
+
+		usage.SizeBytes += blobsSizeBytes
+
+		return j.usageRepo.SetAccountUsage(ctx, usage)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.JobResult{
+		Message: fmt.Sprintf("recalculated usage of account %d: %d bytes, %d entries", data.AccountID, usage.SizeBytes, usage.NumEntries),
+	}, nil
+}
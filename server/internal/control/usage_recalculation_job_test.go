@@ -0,0 +1,54 @@
+package control
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
+)
+
+func TestRecalculateUsageJob(t *testing.T) {
+	t.Parallel()
+
+	setup := setupUploadCtrlTest(t)
+	ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: domain.AccountID(1)})
+	usageRepo := sqlite.NewUsageRepo(setup.db)
+
+	err := setup.syncCtrl.StoreAttachment(ctx, StoreAttachmentCmd{Filepath: "a/b/test.txt", Content: bytes.NewReader([]byte("0123456789"))})
+	require.NoError(t, err)
+
+	err = setup.syncCtrl.CreateChangelogEntries(ctx, CreateChangelogEntriesCmd{
+		Entries: []domain.ChangelogEntry{{SyncClientID: "client_a", Data: []byte("entry_0")}},
+	})
+	require.NoError(t, err)
+
+	hash := sha256.Sum256([]byte("0123456789abcdefghij"))
+	upload, err := setup.uploadCtrl.CreateUpload(ctx, CreateUploadCmd{Kind: domain.UploadKindFullSync, SizeBytes: 20, Sha256: hash[:]})
+	require.NoError(t, err)
+
+	_, err = setup.uploadCtrl.AppendUploadChunk(ctx, AppendUploadChunkCmd{ID: upload.ID, Data: bytes.NewReader([]byte("01234"))})
+	require.NoError(t, err)
+
+	expected, err := usageRepo.GetAccountUsage(t.Context(), domain.AccountID(1))
+	require.NoError(t, err)
+	assert.Equal(t, int64(10+7+20), expected.SizeBytes)
+
+	// e.g. attachments uploaded before the usage was tracked
+	err = usageRepo.SetAccountUsage(t.Context(), &domain.AccountUsage{AccountID: domain.AccountID(1)})
+	require.NoError(t, err)
+
+	job := NewRecalculateUsageJob(setup.db, usageRepo, setup.blobs)
+
+	_, err = job.Exec(t.Context(), RecalculateUsageJobData{AccountID: domain.AccountID(1)})
+	require.NoError(t, err)
+
+	usage, err := usageRepo.GetAccountUsage(t.Context(), domain.AccountID(1))
+	require.NoError(t, err)
+	assert.Equal(t, expected.SizeBytes, usage.SizeBytes)
+	assert.Equal(t, int64(1), usage.NumEntries)
+}
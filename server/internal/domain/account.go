@@ -9,6 +9,9 @@ import (
 var ErrAccountNotFound = errors.New("account not found")
 var ErrInvalidAccountReference = errors.New("invalid account reference")
 var ErrAccountKeyNotFound = errors.New("account key not found")
+var ErrAccountExists = errors.New("account already exists")
+var ErrAccountDisabled = errors.New("account disabled")
+var ErrAccountTOTPNotFound = errors.New("account totp not found")
 
 type AccountID int64
 
@@ -19,10 +22,28 @@ type Account struct {
 
 	Password AccountPassword
 
+	IsAdmin bool
+	// DisabledAt is the zero time for enabled accounts.
+	DisabledAt time.Time
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+func (a *Account) IsDisabled() bool {
+	return !a.DisabledAt.IsZero()
+}
+
+type AccountList struct {
+	Items []*Account
+	Next  *AccountID
+}
+
+type ListAccountsQuery struct {
+	PageSize  uint64
+	PageAfter *AccountID
+}
+
 type AccountPassword struct {
 	Algorithm      string
 	Params         string
@@ -46,3 +67,30 @@ type AccountKey struct {
 	Type      string
 	Data      []byte
 }
+
+// AccountTOTP is the secret for time-based one-time passwords, used as a second factor for password logins.
+type AccountTOTP struct {
+	AccountID AccountID
+	Secret    []byte
+	// LastUsedStep is the time step of the last accepted code, so codes can't be reused.
+	LastUsedStep int64
+	// EnabledAt is the zero time until the enrollment is confirmed with a valid code.
+	EnabledAt time.Time
+	CreatedAt time.Time
+}
+
+func (t *AccountTOTP) IsEnabled() bool {
+	return !t.EnabledAt.IsZero()
+}
+
+type AccountRecoveryCodeID int64
+
+// AccountRecoveryCode is a single-use code, that can be used instead of a one-time password.
+type AccountRecoveryCode struct {
+	ID        AccountRecoveryCodeID
+	AccountID AccountID
+	Params    string
+	Salt      []byte
+	Code      []byte
+	CreatedAt time.Time
+}
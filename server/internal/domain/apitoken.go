@@ -2,10 +2,14 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
 var ErrAPITokenNotFound = errors.New("api token not found")
+var ErrInvalidAPITokenScope = errors.New("invalid api token scope")
 
 type APITokenID int64
 
@@ -14,8 +18,24 @@ type APIToken struct {
 	AccountID AccountID
 	TokenID   int64
 	Name      string
+	Scopes    APITokenScopes
 	CreatedAt time.Time
 	ExpiresAt time.Time
+
+	// zero time if the token has never been used
+	LastUsedAt        time.Time
+	LastUsedIP        string
+	LastUsedUserAgent string
+	RequestCount      int64
+}
+
+// APITokenUsage aggregates the requests made with an API token since its usage was last written.
+type APITokenUsage struct {
+	APITokenID   APITokenID
+	LastUsedAt   time.Time
+	IP           string
+	UserAgent    string
+	RequestCount int64
 }
 
 type APITokenList struct {
@@ -27,3 +47,66 @@ type ListAPITokenQuery struct {
 	PageSize  uint64
 	PageAfter *APITokenID
 }
+
+type APITokenScope string
+
+const (
+	APITokenScopeMemosCreate       APITokenScope = "memos:create"
+	APITokenScopeAttachmentsCreate APITokenScope = "attachments:create"
+	APITokenScopeSyncRead          APITokenScope = "sync:read"
+	APITokenScopeSyncWrite         APITokenScope = "sync:write"
+	APITokenScopeTokensManage      APITokenScope = "tokens:manage"
+)
+
+func AllAPITokenScopes() APITokenScopes {
+	return APITokenScopes{
+		APITokenScopeMemosCreate,
+		APITokenScopeAttachmentsCreate,
+		APITokenScopeSyncRead,
+		APITokenScopeSyncWrite,
+		APITokenScopeTokensManage,
+	}
+}
+
+func ParseAPITokenScope(s string) (APITokenScope, error) {
+	scope := APITokenScope(s)
+	if !slices.Contains(AllAPITokenScopes(), scope) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidAPITokenScope, s)
+	}
+
+	return scope, nil
+}
+
+// APITokenScopes are stored as a comma separated list.
+type APITokenScopes []APITokenScope
+
+func ParseAPITokenScopes(s string) (APITokenScopes, error) {
+	scopes := APITokenScopes{}
+	if s == "" {
+		return scopes, nil
+	}
+
+	for _, raw := range strings.Split(s, ",") {
+		scope, err := ParseAPITokenScope(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
+
+func (s APITokenScopes) Has(scope APITokenScope) bool {
+	return slices.Contains(s, scope)
+}
+
+func (s APITokenScopes) String() string {
+	raw := make([]string, len(s))
+	for i, scope := range s {
+		raw[i] = string(scope)
+	}
+
+	return strings.Join(raw, ",")
+}
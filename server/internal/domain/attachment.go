@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrAttachmentNotFound = errors.New("attachment not found")
+var ErrInvalidAttachmentFilepath = errors.New("invalid attachment filepath")
+
+// Attachment indexes an attachment's blob by the hash of its plaintext content, so repeated uploads can be deduplicated.
+type Attachment struct {
+	ID        string
+	AccountID AccountID
+	Sha256    []byte
+	Filepath  string
+	SizeBytes int64
+	CreatedAt time.Time
+}
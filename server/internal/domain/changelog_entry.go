@@ -7,13 +7,36 @@ import (
 type ChangelogEntryID uint64
 
 type ChangelogEntry struct {
+	ID           ChangelogEntryID
 	SyncClientID SyncClientID
 	AccountID    AccountID
 	Data         []byte
 	Timestamp    time.Time
 }
 
+type ChangelogEntryList struct {
+	Items []ChangelogEntry
+	// Next is the cursor after which the following page starts. It is always set, so clients can continue from it
+	// once new entries were created.
+	Next *ChangelogEntryID
+	// HasMore reports whether the page was full, i.e. whether more entries may be available right away.
+	HasMore bool
+}
+
+// DeletedChangelogEntries summarizes the ChangelogEntries removed by a compaction.
+type DeletedChangelogEntries struct {
+	NumEntries int64
+	// SizeBytes is the total size of the entries' data, which counts towards the account's usage.
+	SizeBytes int64
+	// ReclaimedBytes is the storage space that was actually freed. As storage is allocated in pages, it can differ
+	// from SizeBytes, e.g. it's zero if the entries shared their pages with entries that are kept.
+	ReclaimedBytes int64
+}
+
 type ListChangelogEntriesQuery struct {
-	AccountID AccountID
-	Since     time.Time
+	AccountID           AccountID
+	Since               time.Time
+	ExcludeSyncClientID SyncClientID
+	PageSize            uint64
+	PageAfter           *ChangelogEntryID
 }
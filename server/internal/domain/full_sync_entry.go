@@ -6,11 +6,14 @@ import (
 )
 
 var ErrNoFullSyncEntriesFound = errors.New("no full sync entries found")
+var ErrFullSyncEntryCorrupted = errors.New("full sync entry is corrupted")
 
 type FullSyncEntry struct {
-	AccountID  AccountID
-	Timestamp  time.Time
-	Filepath   string
-	SizeBytes  int64
-	Sha256Hash []byte
+	ID          int64
+	AccountID   AccountID
+	Timestamp   time.Time
+	Filepath    string
+	SizeBytes   int64
+	Sha256Hash  []byte
+	CorruptedAt time.Time
 }
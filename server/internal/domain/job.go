@@ -1,26 +1,71 @@
 package domain
 
 import (
+	"errors"
 	"time"
 )
 
+var ErrJobNotFound = errors.New("job not found")
+var ErrJobLeaseLost = errors.New("job lease lost")
+
 type JobState string
 
 const (
 	JobStateScheduled JobState = "scheduled"
-	JobStateDone      JobState = "done"
-	JobStateError     JobState = "error"
+	// JobStateRunning marks a job that has been claimed by a worker until its lease expires.
+	JobStateRunning JobState = "running"
+	JobStateDone    JobState = "done"
+	// JobStateError is used by jobs that failed before retries were introduced and by failed runs of recurring jobs
+	// whose next occurrence had already been scheduled, so they can neither be retried nor dead-lettered.
+	JobStateError JobState = "error"
+	// JobStateDead is the terminal state of jobs that ran out of attempts, they can be re-queued manually.
+	JobStateDead JobState = "dead"
+	// JobStateCancelled is the terminal state of scheduled jobs that were cancelled before they ran.
+	JobStateCancelled JobState = "cancelled"
 )
 
 type Job struct {
-	ID           int64
-	State        JobState
-	Kind         string
-	Data         any
-	Result       *JobResult
+	ID int64
+	// Name uniquely identifies a scheduled job, empty for anonymous jobs.
+	Name   string
+	State  JobState
+	Kind   string
+	Data   any
+	Result *JobResult
+	// Schedule is the spec of a recurring job, empty for one-shot jobs.
+	Schedule     string
 	ScheduledFor time.Time
+	Attempts     int64
+	Errors       []JobError
+	CreatedAt    time.Time
+	FinishedAt   time.Time
+	// LeaseOwner identifies the instance of the job system that executes a running job.
+	LeaseOwner string
+	// LeaseExpiresAt is the time after which a running job is considered lost, e.g. when the server crashed. The
+	// owner renews the lease while the job is running.
+	LeaseExpiresAt time.Time
 }
 
 type JobResult struct {
-	Message string
+	Message        string
+	BytesReclaimed int64
+}
+
+// JobError records a failed attempt.
+type JobError struct {
+	Attempt  int64
+	Message  string
+	FailedAt time.Time
+}
+
+type JobList struct {
+	Items []*Job
+	Next  *int64
+}
+
+type ListJobsQuery struct {
+	Kind      string
+	State     JobState
+	PageSize  uint64
+	PageAfter *int64
 }
@@ -1,8 +1,30 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
+var ErrSyncClientNotFound = errors.New("sync client not found")
+
 type SyncClientID string
 
 type SyncClient struct {
-	ID        SyncClientID
-	AccountID AccountID
+	ID                    SyncClientID
+	AccountID             AccountID
+	AckedChangelogEntryID ChangelogEntryID
+	LastSeenAt            time.Time
+	CreatedAt             time.Time
+	LastUploadAt          time.Time
+	NumChangelogEntries   int64
+}
+
+type SyncClientList struct {
+	Items []*SyncClient
+	Next  *int64
+}
+
+type ListSyncClientsQuery struct {
+	PageSize  uint64
+	PageAfter *int64
 }
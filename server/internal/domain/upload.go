@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrUploadNotFound = errors.New("upload not found")
+var ErrInvalidUpload = errors.New("invalid upload")
+var ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+var ErrUploadTooLarge = errors.New("upload exceeds declared size")
+var ErrUploadIncomplete = errors.New("upload incomplete")
+var ErrUploadChecksumMismatch = errors.New("upload checksum mismatch")
+
+type UploadID string
+
+type UploadKind string
+
+const (
+	UploadKindFullSync   UploadKind = "full_sync"
+	UploadKindAttachment UploadKind = "attachment"
+)
+
+// Upload is a resumable upload of a full sync snapshot or an attachment. The data is uploaded in chunks which are
+// stored as separate blobs until the upload is finalized.
+type Upload struct {
+	ID          UploadID
+	AccountID   AccountID
+	Kind        UploadKind
+	Filepath    string
+	Sha256      []byte
+	SizeBytes   int64
+	OffsetBytes int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ExpiresAt   time.Time
+}
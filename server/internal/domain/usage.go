@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+var ErrAccountQuotaOverrideNotFound = errors.New("account quota override not found")
+
+// AccountUsage is the storage used by an account, i.e. the size of all blobs and ChangelogEntries and the number of ChangelogEntries.
+// When used to update the usage, the values are added to the current usage and may be negative.
+type AccountUsage struct {
+	AccountID  AccountID
+	SizeBytes  int64
+	NumEntries int64
+	UpdatedAt  time.Time
+}
+
+// AccountQuota limits the storage an account may use. A limit of 0 means unlimited.
+type AccountQuota struct {
+	MaxSizeBytes int64
+	MaxEntries   int64
+}
+
+// AccountQuotaOverride replaces the default quota for a single account. Limits that are nil fall back to the default.
+type AccountQuotaOverride struct {
+	AccountID    AccountID
+	MaxSizeBytes *int64
+	MaxEntries   *int64
+}
@@ -0,0 +1,256 @@
+package adminv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/control"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/x/httperrors"
+	"go.robinthrift.com/conveyor/internal/x/httpmiddleware"
+)
+
+type router struct {
+	authCtrl    *control.AuthController
+	accountCtrl *control.AccountControl
+	usageCtrl   *control.UsageController
+}
+
+func New(basePath string, mux *http.ServeMux, authCtrl *control.AuthController, accountCtrl *control.AccountControl, usageCtrl *control.UsageController) {
+	r := &router{authCtrl, accountCtrl, usageCtrl}
+
+	errorHandler := httperrors.ErrorHandler("conveyor/api/admin/v1")
+
+	HandlerWithOptions(NewStrictHandlerWithOptions(r, nil, StrictHTTPServerOptions{
+		RequestErrorHandlerFunc:  errorHandler,
+		ResponseErrorHandlerFunc: errorHandler,
+	}), StdHTTPServerOptions{
+		BaseRouter:       mux,
+		BaseURL:          basePath + "api/admin/v1",
+		ErrorHandlerFunc: errorHandler,
+		// middlewares are applied in reverse order, so the auth middleware runs before the admin check
+		Middlewares: []MiddlewareFunc{
+			requireAdmin(errorHandler),
+			httperrors.RecoverHandler,
+			// no route requires a scope, so API tokens can't be used for administration
+			httpmiddleware.NewAuthMiddleware(authCtrl, errorHandler, nil, nil),
+		},
+	})
+}
+
+// (GET /accounts).
+func (router *router) ListAccounts(ctx context.Context, req ListAccountsRequestObject) (ListAccountsResponseObject, error) {
+	var pageAfter *domain.AccountID
+
+	if req.Params.PageAfter != nil {
+		p, err := strconv.ParseInt(*req.Params.PageAfter, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid page[after]", httperrors.ErrBadRequest)
+		}
+
+		pageAfter = (*domain.AccountID)(&p)
+	}
+
+	accounts, err := router.accountCtrl.List(ctx, domain.ListAccountsQuery{
+		PageSize:  req.Params.PageSize,
+		PageAfter: pageAfter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := AccountList{Items: make([]Account, len(accounts.Items))}
+	for i, account := range accounts.Items {
+		list.Items[i] = mapAccountToAPI(account)
+	}
+
+	if accounts.Next != nil {
+		next := accounts.Next.String()
+		list.Next = &next
+	}
+
+	return ListAccounts200JSONResponse(list), nil
+}
+
+// (POST /accounts).
+func (router *router) CreateAccount(ctx context.Context, req CreateAccountRequestObject) (CreateAccountResponseObject, error) {
+	if req.Body.Username == "" {
+		return nil, fmt.Errorf("%w: username must not be empty", httperrors.ErrBadRequest)
+	}
+
+	isAdmin := req.Body.IsAdmin != nil && *req.Body.IsAdmin
+
+	err := router.authCtrl.CreateAccount(ctx, control.CreateAccountCmd{
+		Account:         &domain.Account{Username: req.Body.Username, IsAdmin: isAdmin},
+		PlaintextPasswd: auth.PlaintextPassword(req.Body.Password),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAccountExists):
+			return CreateAccount409JSONResponse{
+				ErrorConflictJSONResponse: ErrorConflictJSONResponse{
+					Code:   http.StatusConflict,
+					Title:  http.StatusText(http.StatusConflict),
+					Type:   "conveyor/api/admin/v1/Conflict",
+					Detail: err.Error(),
+				},
+			}, nil
+		case errors.Is(err, control.ErrInitialPasswordEmpty):
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	account, err := router.accountCtrl.GetByUsername(ctx, req.Body.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateAccount201JSONResponse(mapAccountToAPI(account)), nil
+}
+
+// (DELETE /accounts/{id}).
+func (router *router) DeleteAccount(ctx context.Context, req DeleteAccountRequestObject) (DeleteAccountResponseObject, error) {
+	err := router.accountCtrl.Delete(ctx, domain.AccountID(req.Id))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAccountNotFound):
+			return DeleteAccount404JSONResponse{ErrorNotFoundJSONResponse: accountNotFound(req.Id)}, nil
+		case errors.Is(err, control.ErrCurrentAccount):
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	return DeleteAccount204Response{}, nil
+}
+
+// (POST /accounts/{id}/disable).
+func (router *router) DisableAccount(ctx context.Context, req DisableAccountRequestObject) (DisableAccountResponseObject, error) {
+	err := router.accountCtrl.Disable(ctx, domain.AccountID(req.Id))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAccountNotFound):
+			return DisableAccount404JSONResponse{ErrorNotFoundJSONResponse: accountNotFound(req.Id)}, nil
+		case errors.Is(err, control.ErrCurrentAccount):
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	return DisableAccount204Response{}, nil
+}
+
+// (POST /accounts/{id}/enable).
+func (router *router) EnableAccount(ctx context.Context, req EnableAccountRequestObject) (EnableAccountResponseObject, error) {
+	err := router.accountCtrl.Enable(ctx, domain.AccountID(req.Id))
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			return EnableAccount404JSONResponse{ErrorNotFoundJSONResponse: accountNotFound(req.Id)}, nil
+		}
+
+		return nil, err
+	}
+
+	return EnableAccount204Response{}, nil
+}
+
+// (POST /accounts/{id}/reset-password).
+func (router *router) ResetAccountPassword(ctx context.Context, req ResetAccountPasswordRequestObject) (ResetAccountPasswordResponseObject, error) {
+	err := router.authCtrl.ForcePasswordReset(ctx, control.ForcePasswordResetCmd{
+		AccountID:       domain.AccountID(req.Id),
+		PlaintextPasswd: auth.PlaintextPassword(req.Body.Password),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAccountNotFound):
+			return ResetAccountPassword404JSONResponse{ErrorNotFoundJSONResponse: accountNotFound(req.Id)}, nil
+		case errors.Is(err, control.ErrPasswordEmpty):
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	return ResetAccountPassword204Response{}, nil
+}
+
+// (PUT /accounts/{id}/quota).
+func (router *router) SetAccountQuota(ctx context.Context, req SetAccountQuotaRequestObject) (SetAccountQuotaResponseObject, error) {
+	if (req.Body.MaxSizeBytes != nil && *req.Body.MaxSizeBytes < 0) || (req.Body.MaxEntries != nil && *req.Body.MaxEntries < 0) {
+		return nil, fmt.Errorf("%w: limits must not be negative", httperrors.ErrBadRequest)
+	}
+
+	_, err := router.accountCtrl.Get(ctx, domain.AccountID(req.Id))
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			return SetAccountQuota404JSONResponse{ErrorNotFoundJSONResponse: accountNotFound(req.Id)}, nil
+		}
+
+		return nil, err
+	}
+
+	err = router.usageCtrl.SetQuotaOverride(ctx, control.SetQuotaOverrideCmd{
+		AccountID:    domain.AccountID(req.Id),
+		MaxSizeBytes: req.Body.MaxSizeBytes,
+		MaxEntries:   req.Body.MaxEntries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return SetAccountQuota204Response{}, nil
+}
+
+func accountNotFound(id int64) ErrorNotFoundJSONResponse {
+	return ErrorNotFoundJSONResponse{
+		Code:   http.StatusNotFound,
+		Title:  http.StatusText(http.StatusNotFound),
+		Type:   "conveyor/api/admin/v1/NotFound",
+		Detail: fmt.Sprintf("Unknown account %d", id),
+	}
+}
+
+func mapAccountToAPI(account *domain.Account) Account {
+	mapped := Account{
+		Id:                     int64(account.ID),
+		Username:               account.Username,
+		IsAdmin:                account.IsAdmin,
+		RequiresPasswordChange: account.Password.RequiresChange,
+		CreatedAt:              account.CreatedAt,
+	}
+
+	if account.IsDisabled() {
+		mapped.DisabledAt = &account.DisabledAt
+	}
+
+	return mapped
+}
+
+func requireAdmin(errorHandler httperrors.ErrorHandlerFunc) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			account := auth.AccountFromCtx(r.Context())
+			if account == nil {
+				errorHandler(w, r, auth.ErrUnauthorized)
+
+				return
+			}
+
+			if !account.IsAdmin {
+				errorHandler(w, r, auth.ErrForbidden)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
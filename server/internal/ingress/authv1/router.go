@@ -27,12 +27,14 @@ func New(basePath string, mux *http.ServeMux, authCtrl *control.AuthController,
 
 	errorHandler := httperrors.ErrorHandler("conveyor/api/v1/auth")
 
+	baseURL := basePath + "api/auth/v1"
+
 	HandlerWithOptions(NewStrictHandlerWithOptions(r, nil, StrictHTTPServerOptions{
 		RequestErrorHandlerFunc:  errorHandler,
 		ResponseErrorHandlerFunc: errorHandler,
 	}), StdHTTPServerOptions{
 		BaseRouter:       mux,
-		BaseURL:          basePath + "api/auth/v1",
+		BaseURL:          baseURL,
 		ErrorHandlerFunc: errorHandler,
 		Middlewares: []MiddlewareFunc{
 			httperrors.RecoverHandler,
@@ -41,7 +43,17 @@ func New(basePath string, mux *http.ServeMux, authCtrl *control.AuthController,
 				errorHandler,
 				[]string{
 					basePath + "api/auth/v1/token",
-					basePath + "api/auth/v1/change-password"},
+					basePath + "api/auth/v1/change-password",
+					// validates the token itself, so it's usable with API tokens of any scope
+					basePath + "api/auth/v1/check-access",
+				},
+				map[string]domain.APITokenScope{
+					"GET " + baseURL + "/apitokens":           domain.APITokenScopeTokensManage,
+					"POST " + baseURL + "/apitokens":          domain.APITokenScopeTokensManage,
+					"DELETE " + baseURL + "/apitokens/{name}": domain.APITokenScopeTokensManage,
+					"GET " + baseURL + "/keys/{name}":         domain.APITokenScopeSyncRead,
+					"POST " + baseURL + "/keys":               domain.APITokenScopeSyncWrite,
+				},
 			),
 		},
 	})
@@ -71,11 +83,28 @@ func (router *router) RequestAuthToken(ctx context.Context, req RequestAuthToken
 }
 
 func (router *router) requestAuthTokenUsingPassword(ctx context.Context, req AuthTokenRequestPasswordGrant) (RequestAuthTokenResponseObject, error) {
-	token, err := router.authCtrl.CreateAuthTokenUsingCredentials(ctx, control.CreateAuthTokenUsingCredentialsCmd{
+	cmd := control.CreateAuthTokenUsingCredentialsCmd{
 		Username:        req.Username,
 		PlaintextPasswd: auth.PlaintextPassword(req.Password),
-	})
+	}
+
+	if req.Otp != nil {
+		cmd.OTP = *req.Otp
+	}
+
+	token, err := router.authCtrl.CreateAuthTokenUsingCredentials(ctx, cmd)
 	if err != nil {
+		if errors.Is(err, control.ErrOTPRequired) {
+			return RequestAuthToken401JSONResponse{
+				ErrorUnauthorizedJSONResponse: ErrorUnauthorizedJSONResponse{
+					Code:   http.StatusUnauthorized,
+					Title:  "OTPRequired",
+					Type:   "conveyor/api/auth/v1/OTPRequired",
+					Detail: err.Error(),
+				},
+			}, nil
+		}
+
 		if errors.Is(err, control.ErrInvalidCredentials) {
 			return nil, fmt.Errorf("%w: %w", auth.ErrUnauthorized, err)
 		}
@@ -135,18 +164,96 @@ func (router *router) ChangePassword(ctx context.Context, req ChangePasswordRequ
 		return nil, err
 	}
 
-	err = router.authCtrl.ChangeAccountPassword(ctx, control.ChangeAccountPasswordCmd{
+	cmd := control.ChangeAccountPasswordCmd{
 		Username:            req.Body.Username,
 		CurrPasswdPlaintext: auth.PlaintextPassword(req.Body.CurrentPassword),
 		NewPasswdPlaintext:  auth.PlaintextPassword(req.Body.NewPassword),
-	})
+	}
+
+	if req.Body.Otp != nil {
+		cmd.OTP = *req.Body.Otp
+	}
+
+	err = router.authCtrl.ChangeAccountPassword(ctx, cmd)
 	if err != nil {
+		if errors.Is(err, control.ErrOTPRequired) {
+			return ChangePassword401JSONResponse{
+				ErrorUnauthorizedJSONResponse: ErrorUnauthorizedJSONResponse{
+					Code:   http.StatusUnauthorized,
+					Title:  "OTPRequired",
+					Type:   "conveyor/api/auth/v1/OTPRequired",
+					Detail: err.Error(),
+				},
+			}, nil
+		}
+
+		if errors.Is(err, control.ErrInvalidCredentials) {
+			return nil, fmt.Errorf("%w: %w", auth.ErrUnauthorized, err)
+		}
+
 		return nil, err
 	}
 
 	return ChangePassword204Response{}, nil
 }
 
+// (POST /totp).
+func (router *router) EnrollTOTP(ctx context.Context, req EnrollTOTPRequestObject) (EnrollTOTPResponseObject, error) {
+	enrollment, err := router.authCtrl.EnrollTOTP(ctx, control.EnrollTOTPCmd{
+		CurrPasswdPlaintext: auth.PlaintextPassword(req.Body.CurrentPassword),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, control.ErrTOTPAlreadyEnabled):
+			return EnrollTOTP409JSONResponse{ErrorConflictJSONResponse: totpConflict(err)}, nil
+		case errors.Is(err, control.ErrInvalidCredentials):
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	return EnrollTOTP201JSONResponse{
+		Secret: enrollment.Secret,
+		KeyURI: enrollment.KeyURI,
+	}, nil
+}
+
+// (POST /totp/confirm).
+func (router *router) ConfirmTOTP(ctx context.Context, req ConfirmTOTPRequestObject) (ConfirmTOTPResponseObject, error) {
+	recoveryCodes, err := router.authCtrl.ConfirmTOTP(ctx, control.ConfirmTOTPCmd{
+		OTP: req.Body.Otp,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, control.ErrTOTPAlreadyEnabled):
+			return ConfirmTOTP409JSONResponse{ErrorConflictJSONResponse: totpConflict(err)}, nil
+		case errors.Is(err, control.ErrInvalidOTP), errors.Is(err, control.ErrTOTPNotEnrolled):
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	return ConfirmTOTP200JSONResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// (POST /totp/disable).
+func (router *router) DisableTOTP(ctx context.Context, req DisableTOTPRequestObject) (DisableTOTPResponseObject, error) {
+	err := router.authCtrl.DisableTOTP(ctx, control.DisableTOTPCmd{
+		CurrPasswdPlaintext: auth.PlaintextPassword(req.Body.CurrentPassword),
+	})
+	if err != nil {
+		if errors.Is(err, control.ErrInvalidCredentials) || errors.Is(err, control.ErrTOTPNotEnrolled) {
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	return DisableTOTP204Response{}, nil
+}
+
 // (POST /keys).
 func (router *router) AddAccountKey(ctx context.Context, req AddAccountKeyRequestObject) (AddAccountKeyResponseObject, error) {
 	err := router.accountCtrl.CreateAccountKey(ctx, &domain.AccountKey{
@@ -201,9 +308,17 @@ func (router *router) ListAPITokens(ctx context.Context, req ListAPITokensReques
 	apiTokens := APITokenList{Items: make([]APIToken, len(tokens.Items))}
 	for i, token := range tokens.Items {
 		apiTokens.Items[i] = APIToken{
-			Name:      token.Name,
-			CreatedAt: token.CreatedAt,
-			ExpiresAt: token.ExpiresAt,
+			Name:         token.Name,
+			Scopes:       mapAPITokenScopesToAPI(token.Scopes),
+			CreatedAt:    token.CreatedAt,
+			ExpiresAt:    token.ExpiresAt,
+			RequestCount: token.RequestCount,
+		}
+
+		if !token.LastUsedAt.IsZero() {
+			apiTokens.Items[i].LastUsedAt = &token.LastUsedAt
+			apiTokens.Items[i].LastUsedIP = &token.LastUsedIP
+			apiTokens.Items[i].LastUsedUserAgent = &token.LastUsedUserAgent
 		}
 	}
 
@@ -217,11 +332,29 @@ func (router *router) ListAPITokens(ctx context.Context, req ListAPITokensReques
 
 // (POST /apitokens).
 func (router *router) CreateAPIToken(ctx context.Context, req CreateAPITokenRequestObject) (CreateAPITokenResponseObject, error) {
+	scopes := domain.AllAPITokenScopes()
+	if req.Body.Scopes != nil {
+		scopes = make(domain.APITokenScopes, len(*req.Body.Scopes))
+		for i, scope := range *req.Body.Scopes {
+			parsed, err := domain.ParseAPITokenScope(string(scope))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+			}
+
+			scopes[i] = parsed
+		}
+	}
+
 	value, err := router.apiTokenCtrl.CreateAPIToken(ctx, control.CreateAPITokenCmd{
 		Name:      req.Body.Name,
+		Scopes:    scopes,
 		ExpiresAt: req.Body.ExpiresAt,
 	})
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAPITokenScope) {
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
 		return nil, err
 	}
 
@@ -309,3 +442,21 @@ func validateChangePasswordData(body *ChangePasswordJSONRequestBody) error {
 
 	return nil
 }
+
+func totpConflict(err error) ErrorConflictJSONResponse {
+	return ErrorConflictJSONResponse{
+		Code:   http.StatusConflict,
+		Title:  http.StatusText(http.StatusConflict),
+		Type:   "conveyor/api/auth/v1/Conflict",
+		Detail: err.Error(),
+	}
+}
+
+func mapAPITokenScopesToAPI(scopes domain.APITokenScopes) []APITokenScope {
+	mapped := make([]APITokenScope, len(scopes))
+	for i, scope := range scopes {
+		mapped[i] = APITokenScope(scope)
+	}
+
+	return mapped
+}
@@ -23,6 +23,15 @@ const (
 	TokenBearerAuthScopes = "tokenBearerAuth.Scopes"
 )
 
+// Defines values for APITokenScope.
+const (
+	AttachmentsCreate APITokenScope = "attachments:create"
+	MemosCreate       APITokenScope = "memos:create"
+	SyncRead          APITokenScope = "sync:read"
+	SyncWrite         APITokenScope = "sync:write"
+	TokensManage      APITokenScope = "tokens:manage"
+)
+
 // Defines values for AuthTokenRequestPasswordGrantGrantType.
 const (
 	Password AuthTokenRequestPasswordGrantGrantType = "password"
@@ -37,7 +46,16 @@ const (
 type APIToken struct {
 	CreatedAt time.Time `json:"createdAt"`
 	ExpiresAt time.Time `json:"expiresAt"`
-	Name      string    `json:"name"`
+
+	// LastUsedAt Time the token was last used, unset if the token was never used.
+	LastUsedAt        *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP        *string    `json:"lastUsedIP,omitempty"`
+	LastUsedUserAgent *string    `json:"lastUsedUserAgent,omitempty"`
+	Name              string     `json:"name"`
+
+	// RequestCount Number of requests made using the token.
+	RequestCount int64           `json:"requestCount"`
+	Scopes       []APITokenScope `json:"scopes"`
 }
 
 // APITokenList A paginated list of API Tokens.
@@ -46,6 +64,9 @@ type APITokenList struct {
 	Next  *string    `json:"next,omitempty"`
 }
 
+// APITokenScope Permission granted to an API Token.
+type APITokenScope string
+
 // AccountKey An account's public key.
 type AccountKey struct {
 	Data []byte `json:"data"`
@@ -69,8 +90,11 @@ type AuthTokenRequest struct {
 // AuthTokenRequestPasswordGrant Request a new auth token using username and password.
 type AuthTokenRequestPasswordGrant struct {
 	GrantType AuthTokenRequestPasswordGrantGrantType `json:"grant_type"`
-	Password  string                                 `json:"password"`
-	Username  string                                 `json:"username"`
+
+	// Otp A TOTP code or a recovery code, required once two-factor authentication is enabled.
+	Otp      *string `json:"otp,omitempty"`
+	Password string  `json:"password"`
+	Username string  `json:"username"`
 }
 
 // AuthTokenRequestPasswordGrantGrantType defines model for AuthTokenRequestPasswordGrant.GrantType.
@@ -88,9 +112,26 @@ type AuthTokenRequestRefreshTokenGrantGrantType string
 // Error Follows RFC7807 (https://datatracker.ietf.org/doc/html/rfc7807)
 type Error = httperrors.Error
 
+// RecoveryCodes Single-use recovery codes.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// TOTPEnrollment A new TOTP secret, which uses HMAC-SHA1, 6 digits and a period of 30 seconds.
+type TOTPEnrollment struct {
+	// KeyURI The otpauth URI of the secret, usually shown as a QR code.
+	KeyURI string `json:"keyURI"`
+
+	// Secret The base32 encoded secret, for entering it into an authenticator app manually.
+	Secret string `json:"secret"`
+}
+
 // ErrorBadRequest Follows RFC7807 (https://datatracker.ietf.org/doc/html/rfc7807)
 type ErrorBadRequest = Error
 
+// ErrorConflict Follows RFC7807 (https://datatracker.ietf.org/doc/html/rfc7807)
+type ErrorConflict = Error
+
 // ErrorNotFound Follows RFC7807 (https://datatracker.ietf.org/doc/html/rfc7807)
 type ErrorNotFound = Error
 
@@ -112,13 +153,35 @@ type ChangePasswordRequest struct {
 	CurrentPassword   string `json:"currentPassword"`
 	NewPassword       string `json:"newPassword"`
 	NewPasswordRepeat string `json:"newPasswordRepeat"`
-	Username          string `json:"username"`
+
+	// Otp A TOTP code or a recovery code, required once two-factor authentication is enabled.
+	Otp      *string `json:"otp,omitempty"`
+	Username string  `json:"username"`
+}
+
+// ConfirmTOTPRequest defines model for ConfirmTOTPRequest.
+type ConfirmTOTPRequest struct {
+	// Otp A TOTP code generated from the enrolled secret.
+	Otp string `json:"otp"`
 }
 
 // CreateAPITokenRequest defines model for CreateAPITokenRequest.
 type CreateAPITokenRequest struct {
 	ExpiresAt time.Time `json:"expiresAt"`
 	Name      string    `json:"name"`
+
+	// Scopes Scopes granted to the API Token. Defaults to all scopes when omitted.
+	Scopes *[]APITokenScope `json:"scopes,omitempty"`
+}
+
+// DisableTOTPRequest defines model for DisableTOTPRequest.
+type DisableTOTPRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+}
+
+// EnrollTOTPRequest defines model for EnrollTOTPRequest.
+type EnrollTOTPRequest struct {
+	CurrentPassword string `json:"currentPassword"`
 }
 
 // ListAPITokensParams defines parameters for ListAPITokens.
@@ -134,6 +197,9 @@ type ListAPITokensParams struct {
 type CreateAPITokenJSONBody struct {
 	ExpiresAt time.Time `json:"expiresAt"`
 	Name      string    `json:"name"`
+
+	// Scopes Scopes granted to the API Token. Defaults to all scopes when omitted.
+	Scopes *[]APITokenScope `json:"scopes,omitempty"`
 }
 
 // ChangePasswordJSONBody defines parameters for ChangePassword.
@@ -141,7 +207,10 @@ type ChangePasswordJSONBody struct {
 	CurrentPassword   string `json:"currentPassword"`
 	NewPassword       string `json:"newPassword"`
 	NewPasswordRepeat string `json:"newPasswordRepeat"`
-	Username          string `json:"username"`
+
+	// Otp A TOTP code or a recovery code, required once two-factor authentication is enabled.
+	Otp      *string `json:"otp,omitempty"`
+	Username string  `json:"username"`
 }
 
 // CheckAccessParams defines parameters for CheckAccess.
@@ -157,6 +226,22 @@ type AddAccountKeyJSONBody struct {
 	Type string `json:"type"`
 }
 
+// EnrollTOTPJSONBody defines parameters for EnrollTOTP.
+type EnrollTOTPJSONBody struct {
+	CurrentPassword string `json:"currentPassword"`
+}
+
+// ConfirmTOTPJSONBody defines parameters for ConfirmTOTP.
+type ConfirmTOTPJSONBody struct {
+	// Otp A TOTP code generated from the enrolled secret.
+	Otp string `json:"otp"`
+}
+
+// DisableTOTPJSONBody defines parameters for DisableTOTP.
+type DisableTOTPJSONBody struct {
+	CurrentPassword string `json:"currentPassword"`
+}
+
 // CreateAPITokenJSONRequestBody defines body for CreateAPIToken for application/json ContentType.
 type CreateAPITokenJSONRequestBody CreateAPITokenJSONBody
 
@@ -169,6 +254,15 @@ type AddAccountKeyJSONRequestBody AddAccountKeyJSONBody
 // RequestAuthTokenJSONRequestBody defines body for RequestAuthToken for application/json ContentType.
 type RequestAuthTokenJSONRequestBody = AuthTokenRequest
 
+// EnrollTOTPJSONRequestBody defines body for EnrollTOTP for application/json ContentType.
+type EnrollTOTPJSONRequestBody EnrollTOTPJSONBody
+
+// ConfirmTOTPJSONRequestBody defines body for ConfirmTOTP for application/json ContentType.
+type ConfirmTOTPJSONRequestBody ConfirmTOTPJSONBody
+
+// DisableTOTPJSONRequestBody defines body for DisableTOTP for application/json ContentType.
+type DisableTOTPJSONRequestBody DisableTOTPJSONBody
+
 // AsAuthTokenRequestPasswordGrant returns the union data inside the AuthTokenRequest as a AuthTokenRequestPasswordGrant
 func (t AuthTokenRequest) AsAuthTokenRequestPasswordGrant() (AuthTokenRequestPasswordGrant, error) {
 	var body AuthTokenRequestPasswordGrant
@@ -257,6 +351,15 @@ type ServerInterface interface {
 	// Request a new AuthToken pair.
 	// (POST /token)
 	RequestAuthToken(w http.ResponseWriter, r *http.Request)
+	// Enroll in two-factor authentication.
+	// (POST /totp)
+	EnrollTOTP(w http.ResponseWriter, r *http.Request)
+	// Confirm the two-factor authentication enrollment.
+	// (POST /totp/confirm)
+	ConfirmTOTP(w http.ResponseWriter, r *http.Request)
+	// Disable two-factor authentication.
+	// (POST /totp/disable)
+	DisableTOTP(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -508,6 +611,66 @@ func (siw *ServerInterfaceWrapper) RequestAuthToken(w http.ResponseWriter, r *ht
 	handler.ServeHTTP(w, r)
 }
 
+// EnrollTOTP operation middleware
+func (siw *ServerInterfaceWrapper) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EnrollTOTP(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ConfirmTOTP operation middleware
+func (siw *ServerInterfaceWrapper) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ConfirmTOTP(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DisableTOTP operation middleware
+func (siw *ServerInterfaceWrapper) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DisableTOTP(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -636,12 +799,17 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 	m.HandleFunc("POST "+options.BaseURL+"/keys", wrapper.AddAccountKey)
 	m.HandleFunc("GET "+options.BaseURL+"/keys/{name}", wrapper.GetAccountKey)
 	m.HandleFunc("POST "+options.BaseURL+"/token", wrapper.RequestAuthToken)
+	m.HandleFunc("POST "+options.BaseURL+"/totp", wrapper.EnrollTOTP)
+	m.HandleFunc("POST "+options.BaseURL+"/totp/confirm", wrapper.ConfirmTOTP)
+	m.HandleFunc("POST "+options.BaseURL+"/totp/disable", wrapper.DisableTOTP)
 
 	return m
 }
 
 type ErrorBadRequestJSONResponse Error
 
+type ErrorConflictJSONResponse Error
+
 type ErrorNotFoundJSONResponse Error
 
 type ErrorOtherJSONResponse Error
@@ -1090,6 +1258,164 @@ func (response RequestAuthTokendefaultJSONResponse) VisitRequestAuthTokenRespons
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type EnrollTOTPRequestObject struct {
+	Body *EnrollTOTPJSONRequestBody
+}
+
+type EnrollTOTPResponseObject interface {
+	VisitEnrollTOTPResponse(w http.ResponseWriter) error
+}
+
+type EnrollTOTP201JSONResponse TOTPEnrollment
+
+func (response EnrollTOTP201JSONResponse) VisitEnrollTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EnrollTOTP400JSONResponse struct{ ErrorBadRequestJSONResponse }
+
+func (response EnrollTOTP400JSONResponse) VisitEnrollTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EnrollTOTP401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response EnrollTOTP401JSONResponse) VisitEnrollTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EnrollTOTP409JSONResponse struct{ ErrorConflictJSONResponse }
+
+func (response EnrollTOTP409JSONResponse) VisitEnrollTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EnrollTOTPdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response EnrollTOTPdefaultJSONResponse) VisitEnrollTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ConfirmTOTPRequestObject struct {
+	Body *ConfirmTOTPJSONRequestBody
+}
+
+type ConfirmTOTPResponseObject interface {
+	VisitConfirmTOTPResponse(w http.ResponseWriter) error
+}
+
+type ConfirmTOTP200JSONResponse RecoveryCodes
+
+func (response ConfirmTOTP200JSONResponse) VisitConfirmTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ConfirmTOTP400JSONResponse struct{ ErrorBadRequestJSONResponse }
+
+func (response ConfirmTOTP400JSONResponse) VisitConfirmTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ConfirmTOTP401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response ConfirmTOTP401JSONResponse) VisitConfirmTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ConfirmTOTP409JSONResponse struct{ ErrorConflictJSONResponse }
+
+func (response ConfirmTOTP409JSONResponse) VisitConfirmTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ConfirmTOTPdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ConfirmTOTPdefaultJSONResponse) VisitConfirmTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DisableTOTPRequestObject struct {
+	Body *DisableTOTPJSONRequestBody
+}
+
+type DisableTOTPResponseObject interface {
+	VisitDisableTOTPResponse(w http.ResponseWriter) error
+}
+
+type DisableTOTP204Response struct {
+}
+
+func (response DisableTOTP204Response) VisitDisableTOTPResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DisableTOTP400JSONResponse struct{ ErrorBadRequestJSONResponse }
+
+func (response DisableTOTP400JSONResponse) VisitDisableTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DisableTOTP401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response DisableTOTP401JSONResponse) VisitDisableTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DisableTOTPdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response DisableTOTPdefaultJSONResponse) VisitDisableTOTPResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
 	// List API Tokens paginated
@@ -1116,6 +1442,15 @@ type StrictServerInterface interface {
 	// Request a new AuthToken pair.
 	// (POST /token)
 	RequestAuthToken(ctx context.Context, request RequestAuthTokenRequestObject) (RequestAuthTokenResponseObject, error)
+	// Enroll in two-factor authentication.
+	// (POST /totp)
+	EnrollTOTP(ctx context.Context, request EnrollTOTPRequestObject) (EnrollTOTPResponseObject, error)
+	// Confirm the two-factor authentication enrollment.
+	// (POST /totp/confirm)
+	ConfirmTOTP(ctx context.Context, request ConfirmTOTPRequestObject) (ConfirmTOTPResponseObject, error)
+	// Disable two-factor authentication.
+	// (POST /totp/disable)
+	DisableTOTP(ctx context.Context, request DisableTOTPRequestObject) (DisableTOTPResponseObject, error)
 }
 
 type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
@@ -1374,3 +1709,96 @@ func (sh *strictHandler) RequestAuthToken(w http.ResponseWriter, r *http.Request
 		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
 	}
 }
+
+// EnrollTOTP operation middleware
+func (sh *strictHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	var request EnrollTOTPRequestObject
+
+	var body EnrollTOTPJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.EnrollTOTP(ctx, request.(EnrollTOTPRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "EnrollTOTP")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(EnrollTOTPResponseObject); ok {
+		if err := validResponse.VisitEnrollTOTPResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ConfirmTOTP operation middleware
+func (sh *strictHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	var request ConfirmTOTPRequestObject
+
+	var body ConfirmTOTPJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ConfirmTOTP(ctx, request.(ConfirmTOTPRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ConfirmTOTP")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ConfirmTOTPResponseObject); ok {
+		if err := validResponse.VisitConfirmTOTPResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DisableTOTP operation middleware
+func (sh *strictHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	var request DisableTOTPRequestObject
+
+	var body DisableTOTPJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DisableTOTP(ctx, request.(DisableTOTPRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DisableTOTP")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DisableTOTPResponseObject); ok {
+		if err := validResponse.VisitDisableTOTPResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
@@ -0,0 +1,211 @@
+package jobsv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.robinthrift.com/conveyor/internal/control"
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/jobs"
+	"go.robinthrift.com/conveyor/internal/x/httperrors"
+	"go.robinthrift.com/conveyor/internal/x/httpmiddleware"
+)
+
+type router struct {
+	jobs *jobs.System
+}
+
+func New(basePath string, mux *http.ServeMux, authCtrl *control.AuthController, jobs *jobs.System) {
+	r := &router{jobs}
+
+	errorHandler := httperrors.ErrorHandler("conveyor/api/jobs/v1")
+
+	HandlerWithOptions(NewStrictHandlerWithOptions(r, nil, StrictHTTPServerOptions{
+		RequestErrorHandlerFunc:  errorHandler,
+		ResponseErrorHandlerFunc: errorHandler,
+	}), StdHTTPServerOptions{
+		BaseRouter:       mux,
+		BaseURL:          basePath + "api/jobs/v1",
+		ErrorHandlerFunc: errorHandler,
+		Middlewares: []MiddlewareFunc{
+			httperrors.RecoverHandler,
+			// no route requires a scope, so API tokens can't be used to manage jobs
+			httpmiddleware.NewAuthMiddleware(authCtrl, errorHandler, nil, nil),
+		},
+	})
+}
+
+// (GET /jobs).
+func (router *router) ListJobs(ctx context.Context, req ListJobsRequestObject) (ListJobsResponseObject, error) {
+	var pageAfter *int64
+
+	if req.Params.PageAfter != nil {
+		p, err := strconv.ParseInt(*req.Params.PageAfter, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid page[after]", httperrors.ErrBadRequest)
+		}
+
+		pageAfter = &p
+	}
+
+	query := domain.ListJobsQuery{
+		PageSize:  req.Params.PageSize,
+		PageAfter: pageAfter,
+	}
+
+	if req.Params.Kind != nil {
+		query.Kind = *req.Params.Kind
+	}
+
+	if req.Params.State != nil {
+		query.State = domain.JobState(*req.Params.State)
+	}
+
+	jobs, err := router.jobs.ListJobs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	list := JobList{Items: make([]Job, len(jobs.Items))}
+	for i, job := range jobs.Items {
+		list.Items[i] = mapJobToAPI(job)
+	}
+
+	if jobs.Next != nil {
+		next := strconv.FormatInt(*jobs.Next, 10)
+		list.Next = &next
+	}
+
+	return ListJobs200JSONResponse(list), nil
+}
+
+// (POST /jobs).
+func (router *router) TriggerJob(ctx context.Context, req TriggerJobRequestObject) (TriggerJobResponseObject, error) {
+	var data []byte
+	if req.Body.Data != nil {
+		data = *req.Body.Data
+	}
+
+	job, err := router.jobs.Trigger(ctx, req.Body.Kind, data)
+	if err != nil {
+		if errors.Is(err, jobs.ErrUnknownJobKind) {
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	return TriggerJob201JSONResponse(mapJobToAPI(job)), nil
+}
+
+// (GET /jobs/{id}).
+func (router *router) GetJob(ctx context.Context, req GetJobRequestObject) (GetJobResponseObject, error) {
+	job, err := router.jobs.GetJob(ctx, req.Id)
+	if err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) {
+			return GetJob404JSONResponse{ErrorNotFoundJSONResponse: jobNotFound(req.Id)}, nil
+		}
+
+		return nil, err
+	}
+
+	return GetJob200JSONResponse(mapJobToAPI(job)), nil
+}
+
+// (POST /jobs/{id}/cancel).
+func (router *router) CancelJob(ctx context.Context, req CancelJobRequestObject) (CancelJobResponseObject, error) {
+	job, err := router.jobs.Cancel(ctx, req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrJobNotFound):
+			return CancelJob404JSONResponse{ErrorNotFoundJSONResponse: jobNotFound(req.Id)}, nil
+		case errors.Is(err, jobs.ErrInvalidJobState):
+			return CancelJob409JSONResponse{ErrorConflictJSONResponse: invalidJobState(err)}, nil
+		}
+
+		return nil, err
+	}
+
+	return CancelJob200JSONResponse(mapJobToAPI(job)), nil
+}
+
+// (POST /jobs/{id}/retry).
+func (router *router) RetryJob(ctx context.Context, req RetryJobRequestObject) (RetryJobResponseObject, error) {
+	job, err := router.jobs.Retry(ctx, req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrJobNotFound):
+			return RetryJob404JSONResponse{ErrorNotFoundJSONResponse: jobNotFound(req.Id)}, nil
+		case errors.Is(err, jobs.ErrInvalidJobState):
+			return RetryJob409JSONResponse{ErrorConflictJSONResponse: invalidJobState(err)}, nil
+		}
+
+		return nil, err
+	}
+
+	return RetryJob200JSONResponse(mapJobToAPI(job)), nil
+}
+
+func jobNotFound(id int64) ErrorNotFoundJSONResponse {
+	return ErrorNotFoundJSONResponse{
+		Code:   http.StatusNotFound,
+		Title:  http.StatusText(http.StatusNotFound),
+		Type:   "conveyor/api/jobs/v1/NotFound",
+		Detail: fmt.Sprintf("Unknown job %d", id),
+	}
+}
+
+func invalidJobState(err error) ErrorConflictJSONResponse {
+	return ErrorConflictJSONResponse{
+		Code:   http.StatusConflict,
+		Title:  http.StatusText(http.StatusConflict),
+		Type:   "conveyor/api/jobs/v1/Conflict",
+		Detail: err.Error(),
+	}
+}
+
+func mapJobToAPI(job *domain.Job) Job {
+	mapped := Job{
+		Id:           job.ID,
+		Kind:         job.Kind,
+		State:        JobState(job.State),
+		Attempts:     job.Attempts,
+		Errors:       make([]JobError, len(job.Errors)),
+		ScheduledFor: job.ScheduledFor,
+		CreatedAt:    job.CreatedAt,
+	}
+
+	// jobs loaded from the repo always contain the raw JSON
+	if data, ok := job.Data.([]byte); ok {
+		mapped.Data = data
+	}
+
+	if job.Result != nil {
+		mapped.Result = &JobResult{Message: job.Result.Message}
+
+		if job.Result.BytesReclaimed != 0 {
+			mapped.Result.BytesReclaimed = &job.Result.BytesReclaimed
+		}
+	}
+
+	for i, jobErr := range job.Errors {
+		mapped.Errors[i] = JobError{Attempt: jobErr.Attempt, Message: jobErr.Message, FailedAt: jobErr.FailedAt}
+	}
+
+	if job.Name != "" {
+		mapped.Name = &job.Name
+	}
+
+	if job.Schedule != "" {
+		mapped.Schedule = &job.Schedule
+	}
+
+	if !job.FinishedAt.IsZero() {
+		mapped.FinishedAt = &job.FinishedAt
+	}
+
+	return mapped
+}
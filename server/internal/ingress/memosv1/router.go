@@ -1,13 +1,13 @@
 package memosv1
 
 import (
-	"compress/gzip"
 	"context"
 	"net/http"
 
 	"go.robinthrift.com/conveyor/internal/auth"
 	"go.robinthrift.com/conveyor/internal/control"
 	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/x/httpbody"
 	"go.robinthrift.com/conveyor/internal/x/httperrors"
 	"go.robinthrift.com/conveyor/internal/x/httpmiddleware"
 )
@@ -15,18 +15,47 @@ import (
 type router struct {
 	syncCtrl       *control.SyncController
 	accountFetcher AccountFetcher
-	errorHandler   httperrors.ErrorHandlerFunc
+
+	maxAttachmentSizeBytes int64
+	maxDecompressionRatio  int64
+	attachmentContentTypes []string
+
+	errorHandler httperrors.ErrorHandlerFunc
+}
+
+type RouterConfig struct {
+	BasePath string
+
+	// Size limits of the uploaded data, 0 means unlimited.
+	MaxAttachmentSizeBytes int64
+	MaxDecompressionRatio  int64
+
+	// AttachmentContentTypes restricts the Content-Type of uploaded attachments, e.g. `image/*`. Empty allows all types.
+	AttachmentContentTypes []string
 }
 
 type AccountFetcher interface {
-	GetAccountForAuthToken(ctx context.Context, value auth.PlaintextAuthTokenValue) (*domain.Account, error)
+	GetAccountAndAPITokenForAuthToken(ctx context.Context, value auth.PlaintextAuthTokenValue) (*domain.Account, *domain.APIToken, error)
+	RecordAPITokenUsage(apiToken *domain.APIToken, ip string, userAgent string)
 }
 
-func New(basePath string, mux *http.ServeMux, syncCtrl *control.SyncController, accountFetcher AccountFetcher) {
+func New(config RouterConfig, mux *http.ServeMux, syncCtrl *control.SyncController, accountFetcher AccountFetcher) {
 	r := &router{
 		syncCtrl:       syncCtrl,
 		accountFetcher: accountFetcher,
-		errorHandler:   httperrors.ErrorHandler("conveyor/api/memos/v1"),
+
+		maxAttachmentSizeBytes: config.MaxAttachmentSizeBytes,
+		maxDecompressionRatio:  config.MaxDecompressionRatio,
+		attachmentContentTypes: config.AttachmentContentTypes,
+
+		errorHandler: httperrors.ErrorHandler("conveyor/api/memos/v1"),
+	}
+
+	baseURL := config.BasePath + "api/memos/v1"
+
+	requiredScopes := map[string]domain.APITokenScope{
+		"POST " + baseURL + "/attachments": domain.APITokenScopeAttachmentsCreate,
+		"POST " + baseURL + "/memos":       domain.APITokenScopeMemosCreate,
 	}
 
 	HandlerWithOptions(NewStrictHandlerWithOptions(r, nil, StrictHTTPServerOptions{
@@ -34,9 +63,9 @@ func New(basePath string, mux *http.ServeMux, syncCtrl *control.SyncController,
 		ResponseErrorHandlerFunc: r.errorHandler,
 	}), StdHTTPServerOptions{
 		BaseRouter:       mux,
-		BaseURL:          basePath + "api/memos/v1",
+		BaseURL:          baseURL,
 		ErrorHandlerFunc: r.errorHandler,
-		Middlewares:      []MiddlewareFunc{httperrors.RecoverHandler, httpmiddleware.NewAuthMiddleware(accountFetcher, r.errorHandler, nil)},
+		Middlewares:      []MiddlewareFunc{httperrors.RecoverHandler, httpmiddleware.NewAuthMiddleware(accountFetcher, r.errorHandler, nil, requiredScopes)},
 	})
 }
 
@@ -74,23 +103,26 @@ func (router *router) CreateMemo(ctx context.Context, req CreateMemoRequestObjec
 
 // (POST /attachments).
 func (router *router) UploadAttachment(ctx context.Context, req UploadAttachmentRequestObject) (UploadAttachmentResponseObject, error) {
-	content := req.Body
-	if req.Params.ContentEncoding != nil && *req.Params.ContentEncoding == "gzip" {
-		gr, err := gzip.NewReader(content)
-		if err != nil {
-			return nil, err
-		}
-
-		content = gr
-
-		defer gr.Close()
-	}
-
 	contentType := ""
 	if req.Params.ContentType != nil {
 		contentType = *req.Params.ContentType
 	}
 
+	err := httpbody.CheckContentType(contentType, router.attachmentContentTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := httpbody.NewReader(req.Body, req.Params.ContentEncoding, httpbody.Limits{
+		MaxSizeBytes:          router.maxAttachmentSizeBytes,
+		MaxDecompressionRatio: router.maxDecompressionRatio,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer content.Close()
+
 	id, err := router.syncCtrl.CreateAttachmentChangelogEntry(ctx, control.CreateAttachmentChangelogEntryCmd{
 		OriginalFilename: req.Params.XFilename,
 		ContentType:      contentType,
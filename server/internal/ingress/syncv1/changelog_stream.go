@@ -0,0 +1,103 @@
+package syncv1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/control"
+	"go.robinthrift.com/conveyor/internal/domain"
+)
+
+const streamPageSize = 100
+
+// changelogEntryStream writes ChangelogEntries as Server-Sent Events until the request's context is done.
+// Every event's ID is the entry's cursor, so clients can resume using the Last-Event-ID header.
+type changelogEntryStream struct {
+	ctx               context.Context //nolint:containedctx // the response is written outside of the handler
+	syncCtrl          *control.SyncController
+	query             control.ListChangelogEntriesQuery
+	entries           *domain.ChangelogEntryList
+	notify            <-chan struct{}
+	unsubscribe       func()
+	heartbeatInterval time.Duration
+}
+
+func (s *changelogEntryStream) VisitStreamChangelogEntriesResponse(w http.ResponseWriter) error {
+	defer s.unsubscribe()
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	heartbeat := time.NewTicker(s.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	err := s.writePending(w)
+
+	for {
+		if err == nil {
+			err = rc.Flush()
+		}
+
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+		case <-s.notify:
+			err = s.writePending(w)
+		}
+	}
+}
+
+func (s *changelogEntryStream) writePending(w http.ResponseWriter) error {
+	for {
+		if s.entries == nil {
+			entries, err := s.syncCtrl.ListChangelogEntries(s.ctx, s.query)
+			if err != nil {
+				return err
+			}
+
+			s.entries = entries
+		}
+
+		for _, entry := range s.entries.Items {
+			data, err := json.Marshal(EncryptedChangelogEntry{
+				SyncClientID: string(entry.SyncClientID),
+				Data:         entry.Data,
+				Timestamp:    entry.Timestamp,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(w, "id: %d\nevent: changelogEntry\ndata: %s\n\n", entry.ID, data)
+			if err != nil {
+				return err
+			}
+		}
+
+		s.query.PageAfter = s.entries.Next
+
+		done := !s.entries.HasMore
+
+		s.entries = nil
+
+		if done {
+			return nil
+		}
+	}
+}
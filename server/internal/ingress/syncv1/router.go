@@ -1,17 +1,23 @@
 package syncv1
 
 import (
-	"compress/gzip"
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
-	"net/url"
+	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	"go.robinthrift.com/conveyor/internal/auth"
 	"go.robinthrift.com/conveyor/internal/control"
 	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage"
+	"go.robinthrift.com/conveyor/internal/x/httpbody"
 	"go.robinthrift.com/conveyor/internal/x/httperrors"
 	"go.robinthrift.com/conveyor/internal/x/httpmiddleware"
 )
@@ -20,30 +26,81 @@ type router struct {
 	baseURL string
 
 	syncCtrl       *control.SyncController
+	usageCtrl      *control.UsageController
+	uploadCtrl     *control.UploadController
 	accountFetcher AccountFetcher
 
-	serveBlobHandler http.Handler
+	blobs storage.BlobReader
+
+	streamHeartbeatInterval time.Duration
+
+	maxAttachmentSizeBytes  int64
+	maxFullSyncSizeBytes    int64
+	maxUploadChunkSizeBytes int64
+	maxDecompressionRatio   int64
 
 	errorHandler httperrors.ErrorHandlerFunc
 }
 
 type RouterConfig struct {
-	BasePath string
+	BasePath                string
+	StreamHeartbeatInterval time.Duration
+
+	// Size limits of the uploaded data, 0 means unlimited.
+	MaxAttachmentSizeBytes  int64
+	MaxFullSyncSizeBytes    int64
+	MaxUploadChunkSizeBytes int64
+	MaxDecompressionRatio   int64
 }
 
+const defaultStreamHeartbeatInterval = 30 * time.Second
+
 type AccountFetcher interface {
-	GetAccountForAuthToken(ctx context.Context, value auth.PlaintextAuthTokenValue) (*domain.Account, error)
+	GetAccountAndAPITokenForAuthToken(ctx context.Context, value auth.PlaintextAuthTokenValue) (*domain.Account, *domain.APIToken, error)
+	RecordAPITokenUsage(apiToken *domain.APIToken, ip string, userAgent string)
 }
 
-func New(config RouterConfig, mux *http.ServeMux, syncCtrl *control.SyncController, accountFetcher AccountFetcher, blobFS http.FileSystem) {
+func New(config RouterConfig, mux *http.ServeMux, syncCtrl *control.SyncController, usageCtrl *control.UsageController, uploadCtrl *control.UploadController, accountFetcher AccountFetcher, blobs storage.BlobReader) {
 	r := &router{
 		baseURL: config.BasePath,
 
 		syncCtrl:       syncCtrl,
+		usageCtrl:      usageCtrl,
+		uploadCtrl:     uploadCtrl,
 		accountFetcher: accountFetcher,
 
-		serveBlobHandler: http.FileServer(blobFS),
-		errorHandler:     httperrors.ErrorHandler("conveyor/api/v1/sync"),
+		blobs:                   blobs,
+		streamHeartbeatInterval: config.StreamHeartbeatInterval,
+		maxAttachmentSizeBytes:  config.MaxAttachmentSizeBytes,
+		maxFullSyncSizeBytes:    config.MaxFullSyncSizeBytes,
+		maxUploadChunkSizeBytes: config.MaxUploadChunkSizeBytes,
+		maxDecompressionRatio:   config.MaxDecompressionRatio,
+		errorHandler:            httperrors.ErrorHandler("conveyor/api/v1/sync"),
+	}
+
+	if r.streamHeartbeatInterval == 0 {
+		r.streamHeartbeatInterval = defaultStreamHeartbeatInterval
+	}
+
+	baseURL := config.BasePath + "api/sync/v1"
+
+	requiredScopes := map[string]domain.APITokenScope{
+		"POST " + baseURL + "/attachments":              domain.APITokenScopeSyncWrite,
+		"DELETE " + baseURL + "/attachments/{filename}": domain.APITokenScopeSyncWrite,
+		"GET " + baseURL + "/changes":                   domain.APITokenScopeSyncRead,
+		"POST " + baseURL + "/changes":                  domain.APITokenScopeSyncWrite,
+		"GET " + baseURL + "/changes/stream":            domain.APITokenScopeSyncRead,
+		"GET " + baseURL + "/clients":                   domain.APITokenScopeSyncRead,
+		"POST " + baseURL + "/clients":                  domain.APITokenScopeSyncWrite,
+		"DELETE " + baseURL + "/clients/{id}":           domain.APITokenScopeSyncWrite,
+		"POST " + baseURL + "/clients/{id}/ack":         domain.APITokenScopeSyncWrite,
+		"GET " + baseURL + "/full":                      domain.APITokenScopeSyncRead,
+		"POST " + baseURL + "/full":                     domain.APITokenScopeSyncWrite,
+		"POST " + baseURL + "/uploads":                  domain.APITokenScopeSyncWrite,
+		"GET " + baseURL + "/uploads/{id}":              domain.APITokenScopeSyncWrite,
+		"PATCH " + baseURL + "/uploads/{id}":            domain.APITokenScopeSyncWrite,
+		"POST " + baseURL + "/uploads/{id}/finalize":    domain.APITokenScopeSyncWrite,
+		"GET " + baseURL + "/usage":                     domain.APITokenScopeSyncRead,
 	}
 
 	HandlerWithOptions(NewStrictHandlerWithOptions(r, nil, StrictHTTPServerOptions{
@@ -51,9 +108,9 @@ func New(config RouterConfig, mux *http.ServeMux, syncCtrl *control.SyncControll
 		ResponseErrorHandlerFunc: r.errorHandler,
 	}), StdHTTPServerOptions{
 		BaseRouter:       mux,
-		BaseURL:          config.BasePath + "api/sync/v1",
+		BaseURL:          baseURL,
 		ErrorHandlerFunc: r.errorHandler,
-		Middlewares:      []MiddlewareFunc{httperrors.RecoverHandler, httpmiddleware.NewAuthMiddleware(accountFetcher, r.errorHandler, nil)},
+		Middlewares:      []MiddlewareFunc{httperrors.RecoverHandler, httpmiddleware.NewAuthMiddleware(accountFetcher, r.errorHandler, nil, requiredScopes)},
 	})
 
 	mux.Handle(
@@ -80,14 +137,103 @@ func (router *router) serveBlobs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fixedReq := new(http.Request)
-	*fixedReq = *r
-	fixedReq.URL = new(url.URL)
-	*fixedReq.URL = *r.URL
-	fixedReq.URL.Path = fmt.Sprint(account.ID) + "/" + strings.TrimPrefix(r.URL.Path, router.baseURL+"blobs/")
-	fixedReq.URL.RawPath = fmt.Sprint(account.ID) + "/" + strings.TrimPrefix(r.URL.RawPath, router.baseURL+"blobs/")
+	blobPath := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(r.URL.Path, router.baseURL+"blobs/")), "/")
+
+	info, err := router.blobs.StatBlob(r.Context(), account.ID, blobPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			router.errorHandler(w, r, err)
+		}
+
+		return
+	}
+
+	etag := info.ETag
+
+	entry, err := router.syncCtrl.GetFullSyncEntryByFilepath(r.Context(), blobPath)
+	switch {
+	case err == nil:
+		w.Header().Set("Repr-Digest", fullSyncEntryReprDigest(entry))
+		w.Header().Set("Digest", fullSyncEntryDigest(entry))
+		etag = `"` + hex.EncodeToString(entry.Sha256Hash) + `"`
+	case errors.Is(err, domain.ErrFullSyncEntryCorrupted):
+		router.errorHandler(w, r, errFullSyncEntryCorrupted)
+
+		return
+	case !errors.Is(err, domain.ErrNoFullSyncEntriesFound):
+		router.errorHandler(w, r, err)
+
+		return
+	}
+
+	if presigner, ok := router.blobs.(storage.BlobURLPresigner); ok {
+		http.Redirect(w, r, presigner.PresignGetURL(account.ID, blobPath), http.StatusTemporaryRedirect)
+
+		return
+	}
+
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+
+	content := storage.NewBlobReadSeeker(r.Context(), router.blobs, account.ID, blobPath, info.SizeBytes)
+	defer content.Close()
+
+	// handles Range and conditional requests
+	http.ServeContent(w, r, path.Base(blobPath), info.ModTime, content)
+}
+
+// (GET /clients).
+func (router *router) ListClients(ctx context.Context, req ListClientsRequestObject) (ListClientsResponseObject, error) {
+	var pageAfter *int64
+
+	if req.Params.PageAfter != nil {
+		p, err := strconv.ParseInt(*req.Params.PageAfter, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid pageAfter", httperrors.ErrBadRequest)
+		}
+
+		pageAfter = &p
+	}
+
+	clients, err := router.syncCtrl.ListClients(ctx, control.ListClientsQuery{
+		PageSize:  req.Params.PageSize,
+		PageAfter: pageAfter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := SyncClientList{Items: make([]SyncClient, len(clients.Items))}
+	for i, client := range clients.Items {
+		list.Items[i] = SyncClient{
+			ID:                  string(client.ID),
+			CreatedAt:           client.CreatedAt,
+			NumChangelogEntries: client.NumChangelogEntries,
+		}
+
+		if client.AckedChangelogEntryID != 0 {
+			ackedCursor := fmt.Sprint(client.AckedChangelogEntryID)
+			list.Items[i].AckedCursor = &ackedCursor
+		}
+
+		if !client.LastSeenAt.IsZero() {
+			list.Items[i].LastSeenAt = &client.LastSeenAt
+		}
 
-	router.serveBlobHandler.ServeHTTP(w, fixedReq)
+		if !client.LastUploadAt.IsZero() {
+			list.Items[i].LastUploadAt = &client.LastUploadAt
+		}
+	}
+
+	if clients.Next != nil {
+		next := fmt.Sprint(*clients.Next)
+		list.Next = &next
+	}
+
+	return ListClients200JSONResponse(list), nil
 }
 
 // (POST /clients).
@@ -114,6 +260,35 @@ func (router *router) UnregisterClient(ctx context.Context, req UnregisterClient
 	return UnregisterClient201Response{}, nil
 }
 
+// (POST /clients/{id}/ack).
+func (router *router) AckClient(ctx context.Context, req AckClientRequestObject) (AckClientResponseObject, error) {
+	cursor, err := strconv.ParseUint(req.Body.Cursor, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", httperrors.ErrBadRequest)
+	}
+
+	err = router.syncCtrl.AckClient(ctx, control.AckClientCmd{
+		ClientID:              domain.SyncClientID(req.Id),
+		AckedChangelogEntryID: domain.ChangelogEntryID(cursor),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrSyncClientNotFound) {
+			return AckClient404JSONResponse{
+				ErrorNotFoundJSONResponse: ErrorNotFoundJSONResponse{
+					Code:   http.StatusNotFound,
+					Title:  http.StatusText(http.StatusNotFound),
+					Type:   "conveyor/api/sync/v1/NotFound",
+					Detail: "Unknown client " + req.Id,
+				},
+			}, nil
+		}
+
+		return nil, err
+	}
+
+	return AckClient204Response{}, nil
+}
+
 // (GET /full).
 func (router *router) GetFullSync(ctx context.Context, _ GetFullSyncRequestObject) (GetFullSyncResponseObject, error) {
 	entry, err := router.syncCtrl.GetLatestFullSyncEntry(ctx)
@@ -129,19 +304,43 @@ func (router *router) GetFullSync(ctx context.Context, _ GetFullSyncRequestObjec
 			}, nil
 		}
 
+		if errors.Is(err, domain.ErrFullSyncEntryCorrupted) {
+			return nil, errFullSyncEntryCorrupted
+		}
+
 		return nil, err
 	}
 
 	return GetFullSync303Response{
 		Headers: GetFullSync303ResponseHeaders{
-			Location: router.baseURL + "blobs/" + entry.Filepath,
+			Digest:     fullSyncEntryDigest(entry),
+			Location:   router.baseURL + "blobs/" + entry.Filepath,
+			ReprDigest: fullSyncEntryReprDigest(entry),
 		},
 	}, nil
 }
 
+//nolint:gochecknoglobals
+var errFullSyncEntryCorrupted = &httperrors.Error{
+	Code:   http.StatusInternalServerError,
+	Title:  http.StatusText(http.StatusInternalServerError),
+	Type:   "conveyor/api/sync/v1/FullSyncCorrupted",
+	Detail: "The latest database is corrupted, please upload a new one from an existing device",
+}
+
+// fullSyncEntryReprDigest formats the entry's hash as a Repr-Digest header value (RFC 9530).
+func fullSyncEntryReprDigest(entry *domain.FullSyncEntry) string {
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(entry.Sha256Hash) + ":"
+}
+
+// fullSyncEntryDigest formats the entry's hash as a legacy Digest header value (RFC 3230).
+func fullSyncEntryDigest(entry *domain.FullSyncEntry) string {
+	return "sha-256=" + base64.StdEncoding.EncodeToString(entry.Sha256Hash)
+}
+
 // (POST /full).
 func (router *router) UploadFullSyncData(ctx context.Context, req UploadFullSyncDataRequestObject) (UploadFullSyncDataResponseObject, error) {
-	err := router.syncCtrl.SaveFullDB(ctx, control.SaveFullDBCmd{Data: req.Body})
+	err := router.syncCtrl.SaveFullDB(ctx, control.SaveFullDBCmd{Data: httpbody.LimitReader(req.Body, router.maxFullSyncSizeBytes)})
 	if err != nil {
 		return nil, err
 	}
@@ -151,15 +350,47 @@ func (router *router) UploadFullSyncData(ctx context.Context, req UploadFullSync
 
 // (GET /changes).
 func (router *router) ListChangelogEntries(ctx context.Context, req ListChangelogEntriesRequestObject) (ListChangelogEntriesResponseObject, error) {
-	entries, err := router.syncCtrl.ListChangelogEntries(ctx, control.ListChangelogEntriesQuery{
-		Since: req.Params.Since,
-	})
+	query := control.ListChangelogEntriesQuery{}
+
+	if req.Params.Since != nil {
+		query.Since = *req.Params.Since
+	}
+
+	if req.Params.ExcludeClient != nil {
+		query.ExcludeClientID = domain.SyncClientID(*req.Params.ExcludeClient)
+	}
+
+	if req.Params.PageSize != nil {
+		query.PageSize = *req.Params.PageSize
+	}
+
+	if req.Params.PageAfter != nil {
+		p, err := strconv.ParseUint(*req.Params.PageAfter, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid page[after]", httperrors.ErrBadRequest)
+		}
+
+		query.PageAfter = (*domain.ChangelogEntryID)(&p)
+	}
+
+	entries, err := router.syncCtrl.ListChangelogEntries(ctx, query)
 	if err != nil {
+		if errors.Is(err, domain.ErrSyncClientNotFound) {
+			return ListChangelogEntries404JSONResponse{
+				ErrorNotFoundJSONResponse: ErrorNotFoundJSONResponse{
+					Code:   http.StatusNotFound,
+					Title:  http.StatusText(http.StatusNotFound),
+					Type:   "conveyor/api/sync/v1/NotFound",
+					Detail: "Unknown client " + string(query.ExcludeClientID),
+				},
+			}, nil
+		}
+
 		return nil, err
 	}
 
-	apiEntries := make([]EncryptedChangelogEntry, 0, len(entries))
-	for _, entry := range entries {
+	apiEntries := make([]EncryptedChangelogEntry, 0, len(entries.Items))
+	for _, entry := range entries.Items {
 		apiEntries = append(apiEntries, EncryptedChangelogEntry{
 			SyncClientID: string(entry.SyncClientID),
 			Data:         entry.Data,
@@ -168,7 +399,9 @@ func (router *router) ListChangelogEntries(ctx context.Context, req ListChangelo
 	}
 
 	return ListChangelogEntries200JSONResponse{
-		Items: apiEntries,
+		Items:   apiEntries,
+		Next:    fmt.Sprint(*entries.Next),
+		HasMore: entries.HasMore,
 	}, nil
 }
 
@@ -193,25 +426,98 @@ func (router *router) CreateChangelogEntries(ctx context.Context, req CreateChan
 	return CreateChangelogEntries201Response{}, nil
 }
 
-// (POST /attachments/{filename}).
-func (router *router) UploadAttachment(ctx context.Context, req UploadAttachmentRequestObject) (UploadAttachmentResponseObject, error) {
-	content := req.Body
-	if req.Params.ContentEncoding != nil && *req.Params.ContentEncoding == "gzip" {
-		gr, err := gzip.NewReader(content)
+// (GET /changes/stream).
+func (router *router) StreamChangelogEntries(ctx context.Context, req StreamChangelogEntriesRequestObject) (StreamChangelogEntriesResponseObject, error) {
+	query := control.ListChangelogEntriesQuery{PageSize: streamPageSize}
+
+	if req.Params.ExcludeClient != nil {
+		query.ExcludeClientID = domain.SyncClientID(*req.Params.ExcludeClient)
+	}
+
+	cursor := req.Params.After
+	if req.Params.LastEventID != nil {
+		cursor = req.Params.LastEventID
+	}
+
+	if cursor != nil {
+		p, err := strconv.ParseUint(*cursor, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", httperrors.ErrBadRequest)
+		}
+
+		query.PageAfter = (*domain.ChangelogEntryID)(&p)
+	}
+
+	// subscribe before loading the first page, so entries created in between aren't missed
+	notify, unsubscribe, err := router.syncCtrl.SubscribeToChangelogEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// without a cursor only entries created from now on are streamed, clients catch up on the history by listing it
+	if query.PageAfter == nil {
+		var latest domain.ChangelogEntryID
+
+		latest, err = router.syncCtrl.GetLatestChangelogEntryID(ctx)
 		if err != nil {
+			unsubscribe()
+
 			return nil, err
 		}
 
-		content = gr
+		query.PageAfter = &latest
+	}
+
+	entries, err := router.syncCtrl.ListChangelogEntries(ctx, query)
+	if err != nil {
+		unsubscribe()
+
+		if errors.Is(err, domain.ErrSyncClientNotFound) {
+			return StreamChangelogEntries404JSONResponse{
+				ErrorNotFoundJSONResponse: ErrorNotFoundJSONResponse{
+					Code:   http.StatusNotFound,
+					Title:  http.StatusText(http.StatusNotFound),
+					Type:   "conveyor/api/sync/v1/NotFound",
+					Detail: "Unknown client " + string(query.ExcludeClientID),
+				},
+			}, nil
+		}
+
+		return nil, err
+	}
+
+	return &changelogEntryStream{
+		ctx:               ctx,
+		syncCtrl:          router.syncCtrl,
+		query:             query,
+		entries:           entries,
+		notify:            notify,
+		unsubscribe:       unsubscribe,
+		heartbeatInterval: router.streamHeartbeatInterval,
+	}, nil
+}
 
-		defer gr.Close()
+// (POST /attachments/{filename}).
+func (router *router) UploadAttachment(ctx context.Context, req UploadAttachmentRequestObject) (UploadAttachmentResponseObject, error) {
+	content, err := httpbody.NewReader(req.Body, req.Params.ContentEncoding, httpbody.Limits{
+		MaxSizeBytes:          router.maxAttachmentSizeBytes,
+		MaxDecompressionRatio: router.maxDecompressionRatio,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err := router.syncCtrl.StoreAttachment(ctx, control.StoreAttachmentCmd{
+	defer content.Close()
+
+	err = router.syncCtrl.StoreAttachment(ctx, control.StoreAttachmentCmd{
 		Filepath: req.Params.XFilepath,
 		Content:  content,
 	})
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAttachmentFilepath) {
+			return nil, fmt.Errorf("%w: invalid filepath", httperrors.ErrBadRequest)
+		}
+
 		return nil, err
 	}
 
@@ -219,10 +525,206 @@ func (router *router) UploadAttachment(ctx context.Context, req UploadAttachment
 }
 
 // (DELETE /attachments/{filename}).
-func (router *router) DeleteAttachment(_ context.Context, _ DeleteAttachmentRequestObject) (DeleteAttachmentResponseObject, error) {
+func (router *router) DeleteAttachment(ctx context.Context, req DeleteAttachmentRequestObject) (DeleteAttachmentResponseObject, error) {
+	err := router.syncCtrl.DeleteAttachment(ctx, control.DeleteAttachmentCmd{
+		Filepath: req.Filename,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAttachmentFilepath) {
+			return nil, fmt.Errorf("%w: invalid filename", httperrors.ErrBadRequest)
+		}
+
+		if errors.Is(err, domain.ErrAttachmentNotFound) {
+			return DeleteAttachment404JSONResponse{
+				ErrorNotFoundJSONResponse: ErrorNotFoundJSONResponse{
+					Code:   http.StatusNotFound,
+					Title:  http.StatusText(http.StatusNotFound),
+					Type:   "conveyor/api/sync/v1/NotFound",
+					Detail: "Unknown attachment " + req.Filename,
+				},
+			}, nil
+		}
+
+		return nil, err
+	}
+
 	return DeleteAttachment204Response{}, nil
 }
 
+// (POST /uploads).
+func (router *router) CreateUpload(ctx context.Context, req CreateUploadRequestObject) (CreateUploadResponseObject, error) {
+	sha256Hash, err := hex.DecodeString(req.Body.Sha256)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid sha256", httperrors.ErrBadRequest)
+	}
+
+	var kind domain.UploadKind
+
+	switch req.Body.Kind {
+	case UploadKindFullSync:
+		kind = domain.UploadKindFullSync
+	case UploadKindAttachment:
+		kind = domain.UploadKindAttachment
+	default:
+		return nil, fmt.Errorf("%w: invalid kind", httperrors.ErrBadRequest)
+	}
+
+	maxSizeBytes := router.maxFullSyncSizeBytes
+	if kind == domain.UploadKindAttachment {
+		maxSizeBytes = router.maxAttachmentSizeBytes
+	}
+
+	if maxSizeBytes > 0 && req.Body.SizeBytes > maxSizeBytes {
+		return nil, fmt.Errorf("%w: upload exceeds the limit of %d bytes", httperrors.ErrPayloadTooLarge, maxSizeBytes)
+	}
+
+	var filepath string
+	if req.Body.Filepath != nil {
+		filepath = *req.Body.Filepath
+	}
+
+	upload, err := router.uploadCtrl.CreateUpload(ctx, control.CreateUploadCmd{
+		Kind:      kind,
+		Filepath:  filepath,
+		SizeBytes: req.Body.SizeBytes,
+		Sha256:    sha256Hash,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidUpload) || errors.Is(err, domain.ErrInvalidAttachmentFilepath) {
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	return CreateUpload201JSONResponse(mapUploadToAPI(upload)), nil
+}
+
+// (GET /uploads/{id}).
+func (router *router) GetUpload(ctx context.Context, req GetUploadRequestObject) (GetUploadResponseObject, error) {
+	upload, err := router.uploadCtrl.GetUpload(ctx, domain.UploadID(req.Id))
+	if err != nil {
+		if errors.Is(err, domain.ErrUploadNotFound) {
+			return GetUpload404JSONResponse{
+				ErrorNotFoundJSONResponse: ErrorNotFoundJSONResponse{
+					Code:   http.StatusNotFound,
+					Title:  http.StatusText(http.StatusNotFound),
+					Type:   "conveyor/api/sync/v1/NotFound",
+					Detail: "Unknown upload " + req.Id,
+				},
+			}, nil
+		}
+
+		return nil, err
+	}
+
+	return GetUpload200JSONResponse(mapUploadToAPI(upload)), nil
+}
+
+// (PATCH /uploads/{id}).
+func (router *router) AppendUploadChunk(ctx context.Context, req AppendUploadChunkRequestObject) (AppendUploadChunkResponseObject, error) {
+	upload, err := router.uploadCtrl.AppendUploadChunk(ctx, control.AppendUploadChunkCmd{
+		ID:          domain.UploadID(req.Id),
+		OffsetBytes: req.Params.UploadOffset,
+		Data:        httpbody.LimitReader(req.Body, router.maxUploadChunkSizeBytes),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUploadNotFound):
+			return AppendUploadChunk404JSONResponse{
+				ErrorNotFoundJSONResponse: ErrorNotFoundJSONResponse{
+					Code:   http.StatusNotFound,
+					Title:  http.StatusText(http.StatusNotFound),
+					Type:   "conveyor/api/sync/v1/NotFound",
+					Detail: "Unknown upload " + req.Id,
+				},
+			}, nil
+		case errors.Is(err, domain.ErrUploadOffsetMismatch):
+			return AppendUploadChunk409JSONResponse{
+				ErrorConflictJSONResponse: ErrorConflictJSONResponse{
+					Code:   http.StatusConflict,
+					Title:  http.StatusText(http.StatusConflict),
+					Type:   "conveyor/api/sync/v1/Conflict",
+					Detail: err.Error(),
+				},
+			}, nil
+		case errors.Is(err, domain.ErrUploadTooLarge):
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	return AppendUploadChunk200JSONResponse(mapUploadToAPI(upload)), nil
+}
+
+// (POST /uploads/{id}/finalize).
+func (router *router) FinalizeUpload(ctx context.Context, req FinalizeUploadRequestObject) (FinalizeUploadResponseObject, error) {
+	err := router.uploadCtrl.FinalizeUpload(ctx, domain.UploadID(req.Id))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUploadNotFound):
+			return FinalizeUpload404JSONResponse{
+				ErrorNotFoundJSONResponse: ErrorNotFoundJSONResponse{
+					Code:   http.StatusNotFound,
+					Title:  http.StatusText(http.StatusNotFound),
+					Type:   "conveyor/api/sync/v1/NotFound",
+					Detail: "Unknown upload " + req.Id,
+				},
+			}, nil
+		case errors.Is(err, domain.ErrUploadIncomplete), errors.Is(err, domain.ErrUploadChecksumMismatch):
+			return nil, fmt.Errorf("%w: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	return FinalizeUpload201Response{}, nil
+}
+
+func mapUploadToAPI(upload *domain.Upload) Upload {
+	mapped := Upload{
+		ID:          string(upload.ID),
+		SizeBytes:   upload.SizeBytes,
+		OffsetBytes: upload.OffsetBytes,
+		Sha256:      hex.EncodeToString(upload.Sha256),
+		ExpiresAt:   upload.ExpiresAt,
+	}
+
+	switch upload.Kind {
+	case domain.UploadKindFullSync:
+		mapped.Kind = UploadKindFullSync
+	case domain.UploadKindAttachment:
+		mapped.Kind = UploadKindAttachment
+		mapped.Filepath = &upload.Filepath
+	}
+
+	return mapped
+}
+
+// (GET /usage).
+func (router *router) GetUsage(ctx context.Context, _ GetUsageRequestObject) (GetUsageResponseObject, error) {
+	report, err := router.usageCtrl.GetUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := AccountUsage{
+		SizeBytes:  report.Usage.SizeBytes,
+		NumEntries: report.Usage.NumEntries,
+	}
+
+	if report.Quota.MaxSizeBytes > 0 {
+		usage.MaxSizeBytes = &report.Quota.MaxSizeBytes
+	}
+
+	if report.Quota.MaxEntries > 0 {
+		usage.MaxEntries = &report.Quota.MaxEntries
+	}
+
+	return GetUsage200JSONResponse(usage), nil
+}
+
 func (router *router) checkAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token, ok := authTokenFromHeader(r.Header)
@@ -232,7 +734,7 @@ func (router *router) checkAuth(next http.Handler) http.Handler {
 			return
 		}
 
-		account, err := router.accountFetcher.GetAccountForAuthToken(r.Context(), *token)
+		account, apiToken, err := router.accountFetcher.GetAccountAndAPITokenForAuthToken(r.Context(), *token)
 		if account == nil || errors.Is(err, auth.ErrUnauthorized) {
 			router.errorHandler(w, r, auth.ErrUnauthorized)
 
@@ -250,6 +752,20 @@ func (router *router) checkAuth(next http.Handler) http.Handler {
 		}
 
 		ctx := auth.CtxWithAccount(r.Context(), account)
+
+		if apiToken != nil {
+			err = httpmiddleware.CheckScope(apiToken.Scopes, domain.APITokenScopeSyncRead)
+			if err != nil {
+				router.errorHandler(w, r, err)
+
+				return
+			}
+
+			router.accountFetcher.RecordAPITokenUsage(apiToken, httpmiddleware.RemoteIP(r), r.UserAgent())
+
+			ctx = auth.CtxWithAPITokenScopes(ctx, apiToken.Scopes)
+		}
+
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -24,9 +24,35 @@ const (
 	TokenBearerAuthScopes = "tokenBearerAuth.Scopes"
 )
 
+// Defines values for UploadKind.
+const (
+	UploadKindAttachment UploadKind = "attachment"
+	UploadKindFullSync   UploadKind = "fullSync"
+)
+
+// AccountUsage Storage used by an account and the account's quota.
+type AccountUsage struct {
+	// MaxEntries Maximum number of ChangelogEntries the account may store. Omitted if unlimited.
+	MaxEntries *int64 `json:"maxEntries,omitempty"`
+
+	// MaxSizeBytes Maximum total size the account may store. Omitted if unlimited.
+	MaxSizeBytes *int64 `json:"maxSizeBytes,omitempty"`
+
+	// NumEntries Number of stored ChangelogEntries.
+	NumEntries int64 `json:"numEntries"`
+
+	// SizeBytes Total size of all stored blobs and ChangelogEntries.
+	SizeBytes int64 `json:"sizeBytes"`
+}
+
 // EncryptedChangelogEntriesList A list of EncryptedChangelogEntry.
 type EncryptedChangelogEntriesList struct {
-	Items []EncryptedChangelogEntry `json:"items"`
+	// HasMore Whether the page is full, i.e. more entries may be available right away.
+	HasMore bool                      `json:"hasMore"`
+	Items   []EncryptedChangelogEntry `json:"items"`
+
+	// Next Opaque cursor to pass as `page[after]` to request the next page, or as `after` to stream the entries created afterwards. Always set, even if the page is empty.
+	Next string `json:"next"`
 }
 
 // EncryptedChangelogEntry An encrypted payload describing a change.
@@ -42,6 +68,9 @@ type Error = httperrors.Error
 // ErrorBadRequest Follows RFC7807 (https://datatracker.ietf.org/doc/html/rfc7807)
 type ErrorBadRequest = Error
 
+// ErrorConflict Follows RFC7807 (https://datatracker.ietf.org/doc/html/rfc7807)
+type ErrorConflict = Error
+
 // ErrorNotFound Follows RFC7807 (https://datatracker.ietf.org/doc/html/rfc7807)
 type ErrorNotFound = Error
 
@@ -51,11 +80,80 @@ type ErrorOther = Error
 // ErrorUnauthorized Follows RFC7807 (https://datatracker.ietf.org/doc/html/rfc7807)
 type ErrorUnauthorized = Error
 
+// SyncClient A client registered for syncing.
+type SyncClient struct {
+	// AckedCursor Cursor of the last ChangelogEntry the client has acknowledged.
+	AckedCursor *string   `json:"ackedCursor,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	ID          string    `json:"id"`
+
+	// LastSeenAt Time of the client's last acknowledgement.
+	LastSeenAt *time.Time `json:"lastSeenAt,omitempty"`
+
+	// LastUploadAt Time of the newest stored ChangelogEntry authored by the client.
+	LastUploadAt *time.Time `json:"lastUploadAt,omitempty"`
+
+	// NumChangelogEntries Number of stored ChangelogEntries authored by the client.
+	NumChangelogEntries int64 `json:"numChangelogEntries"`
+}
+
+// SyncClientList A paginated list of SyncClients.
+type SyncClientList struct {
+	Items []SyncClient `json:"items"`
+	Next  *string      `json:"next,omitempty"`
+}
+
+// Upload A resumable upload.
+type Upload struct {
+	// ExpiresAt Time after which the upload is deleted if it wasn't finalized.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Filepath Full filepath of the attachment, only set for attachment uploads.
+	Filepath *string `json:"filepath,omitempty"`
+	ID       string  `json:"id"`
+
+	// Kind What is being uploaded.
+	Kind UploadKind `json:"kind"`
+
+	// OffsetBytes Number of bytes uploaded so far, i.e. the offset at which to append the next chunk.
+	OffsetBytes int64 `json:"offsetBytes"`
+
+	// Sha256 Hex encoded SHA-256 hash of the complete upload.
+	Sha256 string `json:"sha256"`
+
+	// SizeBytes Total size of the upload.
+	SizeBytes int64 `json:"sizeBytes"`
+}
+
+// UploadKind What is being uploaded.
+type UploadKind string
+
+// AckClientRequest Acknowledgement of applied ChangelogEntries.
+type AckClientRequest struct {
+	// Cursor Cursor of the last applied ChangelogEntry, i.e. the `next` value of a `GET /changes` response or the ID of the last received event.
+	Cursor string `json:"cursor"`
+}
+
 // CreateChangelogEntriesRequest The list of EncryptedChangelogEntry to create.
 type CreateChangelogEntriesRequest struct {
 	Items []EncryptedChangelogEntry `json:"items"`
 }
 
+// CreateUploadRequest Data for the upload to be started.
+type CreateUploadRequest struct {
+	// Filepath Full filepath of the attachment, required for attachment uploads.
+	Filepath *string `json:"filepath,omitempty"`
+
+	// Kind What is being uploaded.
+	Kind UploadKind `json:"kind"`
+
+	// Sha256 Hex encoded SHA-256 hash of the complete upload.
+	Sha256 string `json:"sha256"`
+
+	// SizeBytes Total size of the upload.
+	SizeBytes int64 `json:"sizeBytes"`
+}
+
 // RegisterClientRequest Data for the client to be reqistered with the sync server.
 type RegisterClientRequest struct {
 	ClientID string `json:"clientID"`
@@ -63,7 +161,7 @@ type RegisterClientRequest struct {
 
 // UploadAttachmentParams defines parameters for UploadAttachment.
 type UploadAttachmentParams struct {
-	// XFilepath Full filepath of the attachment.
+	// XFilepath Full filepath of the attachment. Paths starting with `dbs/` or `uploads/` are reserved for internal use.
 	XFilepath string `json:"X-Filepath"`
 
 	// ContentEncoding Encoding of the uploaded data.
@@ -73,7 +171,16 @@ type UploadAttachmentParams struct {
 // ListChangelogEntriesParams defines parameters for ListChangelogEntries.
 type ListChangelogEntriesParams struct {
 	// Since Updates to return that are newer than this timestamp
-	Since time.Time `form:"since" json:"since"`
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// ExcludeClient ID of a registered client whose own entries should be left out, so a client does not download the changes it uploaded itself.
+	ExcludeClient *string `form:"excludeClient,omitempty" json:"excludeClient,omitempty"`
+
+	// PageSize Maximum number of ChangelogEntries returned per page, at most 1000. Defaults to 250 if omitted.
+	PageSize *uint64 `form:"page[size],omitempty" json:"page[size],omitempty"`
+
+	// PageAfter Opaque cursor from which to start the requested page of ChangelogEntries. Use the `next` value of a previous response.
+	PageAfter *string `form:"page[after],omitempty" json:"page[after],omitempty"`
 }
 
 // CreateChangelogEntriesJSONBody defines parameters for CreateChangelogEntries.
@@ -81,49 +188,127 @@ type CreateChangelogEntriesJSONBody struct {
 	Items []EncryptedChangelogEntry `json:"items"`
 }
 
+// StreamChangelogEntriesParams defines parameters for StreamChangelogEntries.
+type StreamChangelogEntriesParams struct {
+	// After Cursor after which to start streaming ChangelogEntries. Use the `next` value of the last `GET /changes` response, so entries created since then are not missed. Ignored when the `Last-Event-ID` header is set. Without either, only entries created after the stream was opened are sent.
+	After *string `form:"after,omitempty" json:"after,omitempty"`
+
+	// ExcludeClient ID of a registered client whose own entries should be left out, so a client does not download the changes it uploaded itself.
+	ExcludeClient *string `form:"excludeClient,omitempty" json:"excludeClient,omitempty"`
+
+	// LastEventID ID of the last received event, sent automatically by clients when reconnecting.
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// ListClientsParams defines parameters for ListClients.
+type ListClientsParams struct {
+	// PageSize Number of clients returned per page.
+	PageSize uint64 `form:"page[size]" json:"page[size]"`
+
+	// PageAfter Marker from which to start the requested page of clients from.
+	PageAfter *string `form:"page[after],omitempty" json:"page[after],omitempty"`
+}
+
 // RegisterClientJSONBody defines parameters for RegisterClient.
 type RegisterClientJSONBody struct {
 	ClientID string `json:"clientID"`
 }
 
+// AckClientJSONBody defines parameters for AckClient.
+type AckClientJSONBody struct {
+	// Cursor Cursor of the last applied ChangelogEntry, i.e. the `next` value of a `GET /changes` response or the ID of the last received event.
+	Cursor string `json:"cursor"`
+}
+
 // UploadFullSyncDataParams defines parameters for UploadFullSyncData.
 type UploadFullSyncDataParams struct {
 	// ContentEncoding Encoding of the uploaded blob.
 	ContentEncoding *string `json:"Content-Encoding,omitempty"`
 }
 
+// CreateUploadJSONBody defines parameters for CreateUpload.
+type CreateUploadJSONBody struct {
+	// Filepath Full filepath of the attachment, required for attachment uploads.
+	Filepath *string `json:"filepath,omitempty"`
+
+	// Kind What is being uploaded.
+	Kind UploadKind `json:"kind"`
+
+	// Sha256 Hex encoded SHA-256 hash of the complete upload.
+	Sha256 string `json:"sha256"`
+
+	// SizeBytes Total size of the upload.
+	SizeBytes int64 `json:"sizeBytes"`
+}
+
+// AppendUploadChunkParams defines parameters for AppendUploadChunk.
+type AppendUploadChunkParams struct {
+	// UploadOffset Offset at which to append the chunk, must match the upload's current offset.
+	UploadOffset int64 `json:"Upload-Offset"`
+}
+
 // CreateChangelogEntriesJSONRequestBody defines body for CreateChangelogEntries for application/json ContentType.
 type CreateChangelogEntriesJSONRequestBody CreateChangelogEntriesJSONBody
 
 // RegisterClientJSONRequestBody defines body for RegisterClient for application/json ContentType.
 type RegisterClientJSONRequestBody RegisterClientJSONBody
 
+// AckClientJSONRequestBody defines body for AckClient for application/json ContentType.
+type AckClientJSONRequestBody AckClientJSONBody
+
+// CreateUploadJSONRequestBody defines body for CreateUpload for application/json ContentType.
+type CreateUploadJSONRequestBody CreateUploadJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
-	// Delete an attachment
-	// (DELETE /attachments)
-	DeleteAttachment(w http.ResponseWriter, r *http.Request)
 	// Upload an attachment
 	// (POST /attachments)
 	UploadAttachment(w http.ResponseWriter, r *http.Request, params UploadAttachmentParams)
+	// Delete an attachment
+	// (DELETE /attachments/{filename})
+	DeleteAttachment(w http.ResponseWriter, r *http.Request, filename string)
 	// Get list of changes since the specified timestamp.
 	// (GET /changes)
 	ListChangelogEntries(w http.ResponseWriter, r *http.Request, params ListChangelogEntriesParams)
 	// Create new EncryptedChangelogEntries for other clients to download.
 	// (POST /changes)
 	CreateChangelogEntries(w http.ResponseWriter, r *http.Request)
+	// Stream new changes as they are created.
+	// (GET /changes/stream)
+	StreamChangelogEntries(w http.ResponseWriter, r *http.Request, params StreamChangelogEntriesParams)
+	// List registered clients paginated.
+	// (GET /clients)
+	ListClients(w http.ResponseWriter, r *http.Request, params ListClientsParams)
 	// Register a new client.
 	// (POST /clients)
 	RegisterClient(w http.ResponseWriter, r *http.Request)
 	// Unregister a new client.
 	// (DELETE /clients/{id})
 	UnregisterClient(w http.ResponseWriter, r *http.Request, id string)
+	// Acknowledge applied changes.
+	// (POST /clients/{id}/ack)
+	AckClient(w http.ResponseWriter, r *http.Request, id string)
 	// Get the full database.
 	// (GET /full)
 	GetFullSync(w http.ResponseWriter, r *http.Request)
 	// Upload the full encrypted database.
 	// (POST /full)
 	UploadFullSyncData(w http.ResponseWriter, r *http.Request, params UploadFullSyncDataParams)
+	// Start a resumable upload.
+	// (POST /uploads)
+	CreateUpload(w http.ResponseWriter, r *http.Request)
+	// Get the state of an upload.
+	// (GET /uploads/{id})
+	GetUpload(w http.ResponseWriter, r *http.Request, id string)
+	// Append a chunk to an upload.
+	// (PATCH /uploads/{id})
+	AppendUploadChunk(w http.ResponseWriter, r *http.Request, id string, params AppendUploadChunkParams)
+	// Finalize an upload.
+	// (POST /uploads/{id}/finalize)
+	FinalizeUpload(w http.ResponseWriter, r *http.Request, id string)
+	// Get the storage usage.
+	// (GET /usage)
+	GetUsage(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -135,26 +320,6 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
-// DeleteAttachment operation middleware
-func (siw *ServerInterfaceWrapper) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
-
-	ctx := r.Context()
-
-	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
-
-	r = r.WithContext(ctx)
-
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteAttachment(w, r)
-	}))
-
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
-
-	handler.ServeHTTP(w, r)
-}
-
 // UploadAttachment operation middleware
 func (siw *ServerInterfaceWrapper) UploadAttachment(w http.ResponseWriter, r *http.Request) {
 
@@ -224,6 +389,37 @@ func (siw *ServerInterfaceWrapper) UploadAttachment(w http.ResponseWriter, r *ht
 	handler.ServeHTTP(w, r)
 }
 
+// DeleteAttachment operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "filename" -------------
+	var filename string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "filename", r.PathValue("filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAttachment(w, r, filename)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // ListChangelogEntries operation middleware
 func (siw *ServerInterfaceWrapper) ListChangelogEntries(w http.ResponseWriter, r *http.Request) {
 
@@ -238,18 +434,35 @@ func (siw *ServerInterfaceWrapper) ListChangelogEntries(w http.ResponseWriter, r
 	// Parameter object where we will unmarshal all parameters from the context
 	var params ListChangelogEntriesParams
 
-	// ------------- Required query parameter "since" -------------
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", r.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "since", Err: err})
+		return
+	}
 
-	if paramValue := r.URL.Query().Get("since"); paramValue != "" {
+	// ------------- Optional query parameter "excludeClient" -------------
 
-	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "since"})
+	err = runtime.BindQueryParameter("form", true, false, "excludeClient", r.URL.Query(), &params.ExcludeClient)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "excludeClient", Err: err})
 		return
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "since", r.URL.Query(), &params.Since)
+	// ------------- Optional query parameter "page[size]" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page[size]", r.URL.Query(), &params.PageSize)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "since", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page[size]", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page[after]" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page[after]", r.URL.Query(), &params.PageAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page[after]", Err: err})
 		return
 	}
 
@@ -284,6 +497,116 @@ func (siw *ServerInterfaceWrapper) CreateChangelogEntries(w http.ResponseWriter,
 	handler.ServeHTTP(w, r)
 }
 
+// StreamChangelogEntries operation middleware
+func (siw *ServerInterfaceWrapper) StreamChangelogEntries(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamChangelogEntriesParams
+
+	// ------------- Optional query parameter "after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "excludeClient" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "excludeClient", r.URL.Query(), &params.ExcludeClient)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "excludeClient", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Last-Event-ID", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Last-Event-ID", valueList[0], &LastEventID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Last-Event-ID", Err: err})
+			return
+		}
+
+		params.LastEventID = &LastEventID
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StreamChangelogEntries(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListClients operation middleware
+func (siw *ServerInterfaceWrapper) ListClients(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListClientsParams
+
+	// ------------- Required query parameter "page[size]" -------------
+
+	if paramValue := r.URL.Query().Get("page[size]"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "page[size]"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "page[size]", r.URL.Query(), &params.PageSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page[size]", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page[after]" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page[after]", r.URL.Query(), &params.PageAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page[after]", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListClients(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // RegisterClient operation middleware
 func (siw *ServerInterfaceWrapper) RegisterClient(w http.ResponseWriter, r *http.Request) {
 
@@ -335,6 +658,37 @@ func (siw *ServerInterfaceWrapper) UnregisterClient(w http.ResponseWriter, r *ht
 	handler.ServeHTTP(w, r)
 }
 
+// AckClient operation middleware
+func (siw *ServerInterfaceWrapper) AckClient(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AckClient(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // GetFullSync operation middleware
 func (siw *ServerInterfaceWrapper) GetFullSync(w http.ResponseWriter, r *http.Request) {
 
@@ -401,37 +755,198 @@ func (siw *ServerInterfaceWrapper) UploadFullSyncData(w http.ResponseWriter, r *
 	handler.ServeHTTP(w, r)
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+// CreateUpload operation middleware
+func (siw *ServerInterfaceWrapper) CreateUpload(w http.ResponseWriter, r *http.Request) {
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	ctx := r.Context()
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
-}
+	r = r.WithContext(ctx)
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateUpload(w, r)
+	}))
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type RequiredParamError struct {
-	ParamName string
+	handler.ServeHTTP(w, r)
 }
 
-func (e *RequiredParamError) Error() string {
+// GetUpload operation middleware
+func (siw *ServerInterfaceWrapper) GetUpload(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUpload(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// AppendUploadChunk operation middleware
+func (siw *ServerInterfaceWrapper) AppendUploadChunk(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AppendUploadChunkParams
+
+	headers := r.Header
+
+	// ------------- Required header parameter "Upload-Offset" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Upload-Offset")]; found {
+		var UploadOffset int64
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Upload-Offset", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Upload-Offset", valueList[0], &UploadOffset, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Upload-Offset", Err: err})
+			return
+		}
+
+		params.UploadOffset = UploadOffset
+
+	} else {
+		err := fmt.Errorf("Header parameter Upload-Offset is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "Upload-Offset", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AppendUploadChunk(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// FinalizeUpload operation middleware
+func (siw *ServerInterfaceWrapper) FinalizeUpload(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FinalizeUpload(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetUsage operation middleware
+func (siw *ServerInterfaceWrapper) GetUsage(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenBearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsage(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
 	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
 }
 
@@ -521,130 +1036,141 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
-	m.HandleFunc("DELETE "+options.BaseURL+"/attachments", wrapper.DeleteAttachment)
 	m.HandleFunc("POST "+options.BaseURL+"/attachments", wrapper.UploadAttachment)
+	m.HandleFunc("DELETE "+options.BaseURL+"/attachments/{filename}", wrapper.DeleteAttachment)
 	m.HandleFunc("GET "+options.BaseURL+"/changes", wrapper.ListChangelogEntries)
 	m.HandleFunc("POST "+options.BaseURL+"/changes", wrapper.CreateChangelogEntries)
+	m.HandleFunc("GET "+options.BaseURL+"/changes/stream", wrapper.StreamChangelogEntries)
+	m.HandleFunc("GET "+options.BaseURL+"/clients", wrapper.ListClients)
 	m.HandleFunc("POST "+options.BaseURL+"/clients", wrapper.RegisterClient)
 	m.HandleFunc("DELETE "+options.BaseURL+"/clients/{id}", wrapper.UnregisterClient)
+	m.HandleFunc("POST "+options.BaseURL+"/clients/{id}/ack", wrapper.AckClient)
 	m.HandleFunc("GET "+options.BaseURL+"/full", wrapper.GetFullSync)
 	m.HandleFunc("POST "+options.BaseURL+"/full", wrapper.UploadFullSyncData)
+	m.HandleFunc("POST "+options.BaseURL+"/uploads", wrapper.CreateUpload)
+	m.HandleFunc("GET "+options.BaseURL+"/uploads/{id}", wrapper.GetUpload)
+	m.HandleFunc("PATCH "+options.BaseURL+"/uploads/{id}", wrapper.AppendUploadChunk)
+	m.HandleFunc("POST "+options.BaseURL+"/uploads/{id}/finalize", wrapper.FinalizeUpload)
+	m.HandleFunc("GET "+options.BaseURL+"/usage", wrapper.GetUsage)
 
 	return m
 }
 
 type ErrorBadRequestJSONResponse Error
 
+type ErrorConflictJSONResponse Error
+
 type ErrorNotFoundJSONResponse Error
 
 type ErrorOtherJSONResponse Error
 
 type ErrorUnauthorizedJSONResponse Error
 
-type DeleteAttachmentRequestObject struct {
+type UploadAttachmentRequestObject struct {
+	Params UploadAttachmentParams
+	Body   io.Reader
 }
 
-type DeleteAttachmentResponseObject interface {
-	VisitDeleteAttachmentResponse(w http.ResponseWriter) error
+type UploadAttachmentResponseObject interface {
+	VisitUploadAttachmentResponse(w http.ResponseWriter) error
 }
 
-type DeleteAttachment204Response struct {
+type UploadAttachment201Response struct {
 }
 
-func (response DeleteAttachment204Response) VisitDeleteAttachmentResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
+func (response UploadAttachment201Response) VisitUploadAttachmentResponse(w http.ResponseWriter) error {
+	w.WriteHeader(201)
 	return nil
 }
 
-type DeleteAttachment400JSONResponse struct{ ErrorBadRequestJSONResponse }
+type UploadAttachment400JSONResponse struct{ ErrorBadRequestJSONResponse }
 
-func (response DeleteAttachment400JSONResponse) VisitDeleteAttachmentResponse(w http.ResponseWriter) error {
+func (response UploadAttachment400JSONResponse) VisitUploadAttachmentResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type DeleteAttachment401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+type UploadAttachment401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
 
-func (response DeleteAttachment401JSONResponse) VisitDeleteAttachmentResponse(w http.ResponseWriter) error {
+func (response UploadAttachment401JSONResponse) VisitUploadAttachmentResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type DeleteAttachment404JSONResponse struct{ ErrorNotFoundJSONResponse }
+type UploadAttachment404JSONResponse struct{ ErrorNotFoundJSONResponse }
 
-func (response DeleteAttachment404JSONResponse) VisitDeleteAttachmentResponse(w http.ResponseWriter) error {
+func (response UploadAttachment404JSONResponse) VisitUploadAttachmentResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type DeleteAttachmentdefaultJSONResponse struct {
+type UploadAttachmentdefaultJSONResponse struct {
 	Body       Error
 	StatusCode int
 }
 
-func (response DeleteAttachmentdefaultJSONResponse) VisitDeleteAttachmentResponse(w http.ResponseWriter) error {
+func (response UploadAttachmentdefaultJSONResponse) VisitUploadAttachmentResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(response.StatusCode)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type UploadAttachmentRequestObject struct {
-	Params UploadAttachmentParams
-	Body   io.Reader
+type DeleteAttachmentRequestObject struct {
+	Filename string `json:"filename"`
 }
 
-type UploadAttachmentResponseObject interface {
-	VisitUploadAttachmentResponse(w http.ResponseWriter) error
+type DeleteAttachmentResponseObject interface {
+	VisitDeleteAttachmentResponse(w http.ResponseWriter) error
 }
 
-type UploadAttachment201Response struct {
+type DeleteAttachment204Response struct {
 }
 
-func (response UploadAttachment201Response) VisitUploadAttachmentResponse(w http.ResponseWriter) error {
-	w.WriteHeader(201)
+func (response DeleteAttachment204Response) VisitDeleteAttachmentResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
 	return nil
 }
 
-type UploadAttachment400JSONResponse struct{ ErrorBadRequestJSONResponse }
+type DeleteAttachment400JSONResponse struct{ ErrorBadRequestJSONResponse }
 
-func (response UploadAttachment400JSONResponse) VisitUploadAttachmentResponse(w http.ResponseWriter) error {
+func (response DeleteAttachment400JSONResponse) VisitDeleteAttachmentResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type UploadAttachment401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+type DeleteAttachment401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
 
-func (response UploadAttachment401JSONResponse) VisitUploadAttachmentResponse(w http.ResponseWriter) error {
+func (response DeleteAttachment401JSONResponse) VisitDeleteAttachmentResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type UploadAttachment404JSONResponse struct{ ErrorNotFoundJSONResponse }
+type DeleteAttachment404JSONResponse struct{ ErrorNotFoundJSONResponse }
 
-func (response UploadAttachment404JSONResponse) VisitUploadAttachmentResponse(w http.ResponseWriter) error {
+func (response DeleteAttachment404JSONResponse) VisitDeleteAttachmentResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type UploadAttachmentdefaultJSONResponse struct {
+type DeleteAttachmentdefaultJSONResponse struct {
 	Body       Error
 	StatusCode int
 }
 
-func (response UploadAttachmentdefaultJSONResponse) VisitUploadAttachmentResponse(w http.ResponseWriter) error {
+func (response DeleteAttachmentdefaultJSONResponse) VisitDeleteAttachmentResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(response.StatusCode)
 
@@ -762,89 +1288,211 @@ func (response CreateChangelogEntriesdefaultJSONResponse) VisitCreateChangelogEn
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type RegisterClientRequestObject struct {
-	Body *RegisterClientJSONRequestBody
+type StreamChangelogEntriesRequestObject struct {
+	Params StreamChangelogEntriesParams
 }
 
-type RegisterClientResponseObject interface {
-	VisitRegisterClientResponse(w http.ResponseWriter) error
+type StreamChangelogEntriesResponseObject interface {
+	VisitStreamChangelogEntriesResponse(w http.ResponseWriter) error
 }
 
-type RegisterClient201Response struct {
+type StreamChangelogEntries200TexteventStreamResponse struct {
+	Body          io.Reader
+	ContentLength int64
 }
 
-func (response RegisterClient201Response) VisitRegisterClientResponse(w http.ResponseWriter) error {
-	w.WriteHeader(201)
-	return nil
+func (response StreamChangelogEntries200TexteventStreamResponse) VisitStreamChangelogEntriesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
 }
 
-type RegisterClient400JSONResponse struct{ ErrorBadRequestJSONResponse }
+type StreamChangelogEntries400JSONResponse struct{ ErrorBadRequestJSONResponse }
 
-func (response RegisterClient400JSONResponse) VisitRegisterClientResponse(w http.ResponseWriter) error {
+func (response StreamChangelogEntries400JSONResponse) VisitStreamChangelogEntriesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type RegisterClient401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+type StreamChangelogEntries401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
 
-func (response RegisterClient401JSONResponse) VisitRegisterClientResponse(w http.ResponseWriter) error {
+func (response StreamChangelogEntries401JSONResponse) VisitStreamChangelogEntriesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type RegisterClient404JSONResponse struct{ ErrorNotFoundJSONResponse }
+type StreamChangelogEntries404JSONResponse struct{ ErrorNotFoundJSONResponse }
 
-func (response RegisterClient404JSONResponse) VisitRegisterClientResponse(w http.ResponseWriter) error {
+func (response StreamChangelogEntries404JSONResponse) VisitStreamChangelogEntriesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type RegisterClientdefaultJSONResponse struct {
+type StreamChangelogEntriesdefaultJSONResponse struct {
 	Body       Error
 	StatusCode int
 }
 
-func (response RegisterClientdefaultJSONResponse) VisitRegisterClientResponse(w http.ResponseWriter) error {
+func (response StreamChangelogEntriesdefaultJSONResponse) VisitStreamChangelogEntriesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(response.StatusCode)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type UnregisterClientRequestObject struct {
-	Id string `json:"id"`
+type ListClientsRequestObject struct {
+	Params ListClientsParams
 }
 
-type UnregisterClientResponseObject interface {
-	VisitUnregisterClientResponse(w http.ResponseWriter) error
+type ListClientsResponseObject interface {
+	VisitListClientsResponse(w http.ResponseWriter) error
 }
 
-type UnregisterClient201Response struct {
-}
+type ListClients200JSONResponse SyncClientList
 
-func (response UnregisterClient201Response) VisitUnregisterClientResponse(w http.ResponseWriter) error {
-	w.WriteHeader(201)
-	return nil
+func (response ListClients200JSONResponse) VisitListClientsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-type UnregisterClient400JSONResponse struct{ ErrorBadRequestJSONResponse }
+type ListClients400JSONResponse struct{ ErrorBadRequestJSONResponse }
 
-func (response UnregisterClient400JSONResponse) VisitUnregisterClientResponse(w http.ResponseWriter) error {
+func (response ListClients400JSONResponse) VisitListClientsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type UnregisterClient401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+type ListClients401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
 
-func (response UnregisterClient401JSONResponse) VisitUnregisterClientResponse(w http.ResponseWriter) error {
+func (response ListClients401JSONResponse) VisitListClientsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListClients404JSONResponse struct{ ErrorNotFoundJSONResponse }
+
+func (response ListClients404JSONResponse) VisitListClientsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListClientsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ListClientsdefaultJSONResponse) VisitListClientsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RegisterClientRequestObject struct {
+	Body *RegisterClientJSONRequestBody
+}
+
+type RegisterClientResponseObject interface {
+	VisitRegisterClientResponse(w http.ResponseWriter) error
+}
+
+type RegisterClient201Response struct {
+}
+
+func (response RegisterClient201Response) VisitRegisterClientResponse(w http.ResponseWriter) error {
+	w.WriteHeader(201)
+	return nil
+}
+
+type RegisterClient400JSONResponse struct{ ErrorBadRequestJSONResponse }
+
+func (response RegisterClient400JSONResponse) VisitRegisterClientResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterClient401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response RegisterClient401JSONResponse) VisitRegisterClientResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterClient404JSONResponse struct{ ErrorNotFoundJSONResponse }
+
+func (response RegisterClient404JSONResponse) VisitRegisterClientResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterClientdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response RegisterClientdefaultJSONResponse) VisitRegisterClientResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UnregisterClientRequestObject struct {
+	Id string `json:"id"`
+}
+
+type UnregisterClientResponseObject interface {
+	VisitUnregisterClientResponse(w http.ResponseWriter) error
+}
+
+type UnregisterClient201Response struct {
+}
+
+func (response UnregisterClient201Response) VisitUnregisterClientResponse(w http.ResponseWriter) error {
+	w.WriteHeader(201)
+	return nil
+}
+
+type UnregisterClient400JSONResponse struct{ ErrorBadRequestJSONResponse }
+
+func (response UnregisterClient400JSONResponse) VisitUnregisterClientResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UnregisterClient401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response UnregisterClient401JSONResponse) VisitUnregisterClientResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(401)
 
@@ -872,6 +1520,62 @@ func (response UnregisterClientdefaultJSONResponse) VisitUnregisterClientRespons
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type AckClientRequestObject struct {
+	Id   string `json:"id"`
+	Body *AckClientJSONRequestBody
+}
+
+type AckClientResponseObject interface {
+	VisitAckClientResponse(w http.ResponseWriter) error
+}
+
+type AckClient204Response struct {
+}
+
+func (response AckClient204Response) VisitAckClientResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type AckClient400JSONResponse struct{ ErrorBadRequestJSONResponse }
+
+func (response AckClient400JSONResponse) VisitAckClientResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AckClient401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response AckClient401JSONResponse) VisitAckClientResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AckClient404JSONResponse struct{ ErrorNotFoundJSONResponse }
+
+func (response AckClient404JSONResponse) VisitAckClientResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AckClientdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response AckClientdefaultJSONResponse) VisitAckClientResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 type GetFullSyncRequestObject struct {
 }
 
@@ -899,7 +1603,9 @@ func (response GetFullSync200ApplicationoctetStreamResponse) VisitGetFullSyncRes
 }
 
 type GetFullSync303ResponseHeaders struct {
-	Location string
+	Digest     string
+	Location   string
+	ReprDigest string
 }
 
 type GetFullSync303Response struct {
@@ -907,7 +1613,9 @@ type GetFullSync303Response struct {
 }
 
 func (response GetFullSync303Response) VisitGetFullSyncResponse(w http.ResponseWriter) error {
+	w.Header().Set("Digest", fmt.Sprint(response.Headers.Digest))
 	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.Header().Set("Repr-Digest", fmt.Sprint(response.Headers.ReprDigest))
 	w.WriteHeader(303)
 	return nil
 }
@@ -921,86 +1629,339 @@ func (response GetFullSync400JSONResponse) VisitGetFullSyncResponse(w http.Respo
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetFullSync401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+type GetFullSync401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response GetFullSync401JSONResponse) VisitGetFullSyncResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFullSync404JSONResponse struct{ ErrorNotFoundJSONResponse }
+
+func (response GetFullSync404JSONResponse) VisitGetFullSyncResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFullSyncdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response GetFullSyncdefaultJSONResponse) VisitGetFullSyncResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UploadFullSyncDataRequestObject struct {
+	Params UploadFullSyncDataParams
+	Body   io.Reader
+}
+
+type UploadFullSyncDataResponseObject interface {
+	VisitUploadFullSyncDataResponse(w http.ResponseWriter) error
+}
+
+type UploadFullSyncData201Response struct {
+}
+
+func (response UploadFullSyncData201Response) VisitUploadFullSyncDataResponse(w http.ResponseWriter) error {
+	w.WriteHeader(201)
+	return nil
+}
+
+type UploadFullSyncData400JSONResponse struct{ ErrorBadRequestJSONResponse }
+
+func (response UploadFullSyncData400JSONResponse) VisitUploadFullSyncDataResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UploadFullSyncData401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response UploadFullSyncData401JSONResponse) VisitUploadFullSyncDataResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UploadFullSyncData404JSONResponse struct{ ErrorNotFoundJSONResponse }
+
+func (response UploadFullSyncData404JSONResponse) VisitUploadFullSyncDataResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UploadFullSyncDatadefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response UploadFullSyncDatadefaultJSONResponse) VisitUploadFullSyncDataResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateUploadRequestObject struct {
+	Body *CreateUploadJSONRequestBody
+}
+
+type CreateUploadResponseObject interface {
+	VisitCreateUploadResponse(w http.ResponseWriter) error
+}
+
+type CreateUpload201JSONResponse Upload
+
+func (response CreateUpload201JSONResponse) VisitCreateUploadResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateUpload400JSONResponse struct{ ErrorBadRequestJSONResponse }
+
+func (response CreateUpload400JSONResponse) VisitCreateUploadResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateUpload401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response CreateUpload401JSONResponse) VisitCreateUploadResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateUploaddefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response CreateUploaddefaultJSONResponse) VisitCreateUploadResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetUploadRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetUploadResponseObject interface {
+	VisitGetUploadResponse(w http.ResponseWriter) error
+}
+
+type GetUpload200JSONResponse Upload
+
+func (response GetUpload200JSONResponse) VisitGetUploadResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUpload401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response GetUpload401JSONResponse) VisitGetUploadResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUpload404JSONResponse struct{ ErrorNotFoundJSONResponse }
+
+func (response GetUpload404JSONResponse) VisitGetUploadResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUploaddefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response GetUploaddefaultJSONResponse) VisitGetUploadResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type AppendUploadChunkRequestObject struct {
+	Id     string `json:"id"`
+	Params AppendUploadChunkParams
+	Body   io.Reader
+}
+
+type AppendUploadChunkResponseObject interface {
+	VisitAppendUploadChunkResponse(w http.ResponseWriter) error
+}
+
+type AppendUploadChunk200JSONResponse Upload
+
+func (response AppendUploadChunk200JSONResponse) VisitAppendUploadChunkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AppendUploadChunk400JSONResponse struct{ ErrorBadRequestJSONResponse }
+
+func (response AppendUploadChunk400JSONResponse) VisitAppendUploadChunkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AppendUploadChunk401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
 
-func (response GetFullSync401JSONResponse) VisitGetFullSyncResponse(w http.ResponseWriter) error {
+func (response AppendUploadChunk401JSONResponse) VisitAppendUploadChunkResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetFullSync404JSONResponse struct{ ErrorNotFoundJSONResponse }
+type AppendUploadChunk404JSONResponse struct{ ErrorNotFoundJSONResponse }
 
-func (response GetFullSync404JSONResponse) VisitGetFullSyncResponse(w http.ResponseWriter) error {
+func (response AppendUploadChunk404JSONResponse) VisitAppendUploadChunkResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetFullSyncdefaultJSONResponse struct {
+type AppendUploadChunk409JSONResponse struct{ ErrorConflictJSONResponse }
+
+func (response AppendUploadChunk409JSONResponse) VisitAppendUploadChunkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AppendUploadChunkdefaultJSONResponse struct {
 	Body       Error
 	StatusCode int
 }
 
-func (response GetFullSyncdefaultJSONResponse) VisitGetFullSyncResponse(w http.ResponseWriter) error {
+func (response AppendUploadChunkdefaultJSONResponse) VisitAppendUploadChunkResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(response.StatusCode)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type UploadFullSyncDataRequestObject struct {
-	Params UploadFullSyncDataParams
-	Body   io.Reader
+type FinalizeUploadRequestObject struct {
+	Id string `json:"id"`
 }
 
-type UploadFullSyncDataResponseObject interface {
-	VisitUploadFullSyncDataResponse(w http.ResponseWriter) error
+type FinalizeUploadResponseObject interface {
+	VisitFinalizeUploadResponse(w http.ResponseWriter) error
 }
 
-type UploadFullSyncData201Response struct {
+type FinalizeUpload201Response struct {
 }
 
-func (response UploadFullSyncData201Response) VisitUploadFullSyncDataResponse(w http.ResponseWriter) error {
+func (response FinalizeUpload201Response) VisitFinalizeUploadResponse(w http.ResponseWriter) error {
 	w.WriteHeader(201)
 	return nil
 }
 
-type UploadFullSyncData400JSONResponse struct{ ErrorBadRequestJSONResponse }
+type FinalizeUpload400JSONResponse struct{ ErrorBadRequestJSONResponse }
 
-func (response UploadFullSyncData400JSONResponse) VisitUploadFullSyncDataResponse(w http.ResponseWriter) error {
+func (response FinalizeUpload400JSONResponse) VisitFinalizeUploadResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type UploadFullSyncData401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+type FinalizeUpload401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
 
-func (response UploadFullSyncData401JSONResponse) VisitUploadFullSyncDataResponse(w http.ResponseWriter) error {
+func (response FinalizeUpload401JSONResponse) VisitFinalizeUploadResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type UploadFullSyncData404JSONResponse struct{ ErrorNotFoundJSONResponse }
+type FinalizeUpload404JSONResponse struct{ ErrorNotFoundJSONResponse }
 
-func (response UploadFullSyncData404JSONResponse) VisitUploadFullSyncDataResponse(w http.ResponseWriter) error {
+func (response FinalizeUpload404JSONResponse) VisitFinalizeUploadResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type UploadFullSyncDatadefaultJSONResponse struct {
+type FinalizeUploaddefaultJSONResponse struct {
 	Body       Error
 	StatusCode int
 }
 
-func (response UploadFullSyncDatadefaultJSONResponse) VisitUploadFullSyncDataResponse(w http.ResponseWriter) error {
+func (response FinalizeUploaddefaultJSONResponse) VisitFinalizeUploadResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetUsageRequestObject struct {
+}
+
+type GetUsageResponseObject interface {
+	VisitGetUsageResponse(w http.ResponseWriter) error
+}
+
+type GetUsage200JSONResponse AccountUsage
+
+func (response GetUsage200JSONResponse) VisitGetUsageResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUsage401JSONResponse struct{ ErrorUnauthorizedJSONResponse }
+
+func (response GetUsage401JSONResponse) VisitGetUsageResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUsagedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response GetUsagedefaultJSONResponse) VisitGetUsageResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(response.StatusCode)
 
@@ -1009,30 +1970,54 @@ func (response UploadFullSyncDatadefaultJSONResponse) VisitUploadFullSyncDataRes
 
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
-	// Delete an attachment
-	// (DELETE /attachments)
-	DeleteAttachment(ctx context.Context, request DeleteAttachmentRequestObject) (DeleteAttachmentResponseObject, error)
 	// Upload an attachment
 	// (POST /attachments)
 	UploadAttachment(ctx context.Context, request UploadAttachmentRequestObject) (UploadAttachmentResponseObject, error)
+	// Delete an attachment
+	// (DELETE /attachments/{filename})
+	DeleteAttachment(ctx context.Context, request DeleteAttachmentRequestObject) (DeleteAttachmentResponseObject, error)
 	// Get list of changes since the specified timestamp.
 	// (GET /changes)
 	ListChangelogEntries(ctx context.Context, request ListChangelogEntriesRequestObject) (ListChangelogEntriesResponseObject, error)
 	// Create new EncryptedChangelogEntries for other clients to download.
 	// (POST /changes)
 	CreateChangelogEntries(ctx context.Context, request CreateChangelogEntriesRequestObject) (CreateChangelogEntriesResponseObject, error)
+	// Stream new changes as they are created.
+	// (GET /changes/stream)
+	StreamChangelogEntries(ctx context.Context, request StreamChangelogEntriesRequestObject) (StreamChangelogEntriesResponseObject, error)
+	// List registered clients paginated.
+	// (GET /clients)
+	ListClients(ctx context.Context, request ListClientsRequestObject) (ListClientsResponseObject, error)
 	// Register a new client.
 	// (POST /clients)
 	RegisterClient(ctx context.Context, request RegisterClientRequestObject) (RegisterClientResponseObject, error)
 	// Unregister a new client.
 	// (DELETE /clients/{id})
 	UnregisterClient(ctx context.Context, request UnregisterClientRequestObject) (UnregisterClientResponseObject, error)
+	// Acknowledge applied changes.
+	// (POST /clients/{id}/ack)
+	AckClient(ctx context.Context, request AckClientRequestObject) (AckClientResponseObject, error)
 	// Get the full database.
 	// (GET /full)
 	GetFullSync(ctx context.Context, request GetFullSyncRequestObject) (GetFullSyncResponseObject, error)
 	// Upload the full encrypted database.
 	// (POST /full)
 	UploadFullSyncData(ctx context.Context, request UploadFullSyncDataRequestObject) (UploadFullSyncDataResponseObject, error)
+	// Start a resumable upload.
+	// (POST /uploads)
+	CreateUpload(ctx context.Context, request CreateUploadRequestObject) (CreateUploadResponseObject, error)
+	// Get the state of an upload.
+	// (GET /uploads/{id})
+	GetUpload(ctx context.Context, request GetUploadRequestObject) (GetUploadResponseObject, error)
+	// Append a chunk to an upload.
+	// (PATCH /uploads/{id})
+	AppendUploadChunk(ctx context.Context, request AppendUploadChunkRequestObject) (AppendUploadChunkResponseObject, error)
+	// Finalize an upload.
+	// (POST /uploads/{id}/finalize)
+	FinalizeUpload(ctx context.Context, request FinalizeUploadRequestObject) (FinalizeUploadResponseObject, error)
+	// Get the storage usage.
+	// (GET /usage)
+	GetUsage(ctx context.Context, request GetUsageRequestObject) (GetUsageResponseObject, error)
 }
 
 type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
@@ -1064,23 +2049,27 @@ type strictHandler struct {
 	options     StrictHTTPServerOptions
 }
 
-// DeleteAttachment operation middleware
-func (sh *strictHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
-	var request DeleteAttachmentRequestObject
+// UploadAttachment operation middleware
+func (sh *strictHandler) UploadAttachment(w http.ResponseWriter, r *http.Request, params UploadAttachmentParams) {
+	var request UploadAttachmentRequestObject
+
+	request.Params = params
+
+	request.Body = r.Body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.DeleteAttachment(ctx, request.(DeleteAttachmentRequestObject))
+		return sh.ssi.UploadAttachment(ctx, request.(UploadAttachmentRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "DeleteAttachment")
+		handler = middleware(handler, "UploadAttachment")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(DeleteAttachmentResponseObject); ok {
-		if err := validResponse.VisitDeleteAttachmentResponse(w); err != nil {
+	} else if validResponse, ok := response.(UploadAttachmentResponseObject); ok {
+		if err := validResponse.VisitUploadAttachmentResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1088,27 +2077,25 @@ func (sh *strictHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request
 	}
 }
 
-// UploadAttachment operation middleware
-func (sh *strictHandler) UploadAttachment(w http.ResponseWriter, r *http.Request, params UploadAttachmentParams) {
-	var request UploadAttachmentRequestObject
-
-	request.Params = params
+// DeleteAttachment operation middleware
+func (sh *strictHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request, filename string) {
+	var request DeleteAttachmentRequestObject
 
-	request.Body = r.Body
+	request.Filename = filename
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.UploadAttachment(ctx, request.(UploadAttachmentRequestObject))
+		return sh.ssi.DeleteAttachment(ctx, request.(DeleteAttachmentRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "UploadAttachment")
+		handler = middleware(handler, "DeleteAttachment")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(UploadAttachmentResponseObject); ok {
-		if err := validResponse.VisitUploadAttachmentResponse(w); err != nil {
+	} else if validResponse, ok := response.(DeleteAttachmentResponseObject); ok {
+		if err := validResponse.VisitDeleteAttachmentResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1173,6 +2160,58 @@ func (sh *strictHandler) CreateChangelogEntries(w http.ResponseWriter, r *http.R
 	}
 }
 
+// StreamChangelogEntries operation middleware
+func (sh *strictHandler) StreamChangelogEntries(w http.ResponseWriter, r *http.Request, params StreamChangelogEntriesParams) {
+	var request StreamChangelogEntriesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.StreamChangelogEntries(ctx, request.(StreamChangelogEntriesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "StreamChangelogEntries")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(StreamChangelogEntriesResponseObject); ok {
+		if err := validResponse.VisitStreamChangelogEntriesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListClients operation middleware
+func (sh *strictHandler) ListClients(w http.ResponseWriter, r *http.Request, params ListClientsParams) {
+	var request ListClientsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListClients(ctx, request.(ListClientsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListClients")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListClientsResponseObject); ok {
+		if err := validResponse.VisitListClientsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // RegisterClient operation middleware
 func (sh *strictHandler) RegisterClient(w http.ResponseWriter, r *http.Request) {
 	var request RegisterClientRequestObject
@@ -1230,6 +2269,39 @@ func (sh *strictHandler) UnregisterClient(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// AckClient operation middleware
+func (sh *strictHandler) AckClient(w http.ResponseWriter, r *http.Request, id string) {
+	var request AckClientRequestObject
+
+	request.Id = id
+
+	var body AckClientJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.AckClient(ctx, request.(AckClientRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "AckClient")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(AckClientResponseObject); ok {
+		if err := validResponse.VisitAckClientResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // GetFullSync operation middleware
 func (sh *strictHandler) GetFullSync(w http.ResponseWriter, r *http.Request) {
 	var request GetFullSyncRequestObject
@@ -1281,3 +2353,139 @@ func (sh *strictHandler) UploadFullSyncData(w http.ResponseWriter, r *http.Reque
 		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
 	}
 }
+
+// CreateUpload operation middleware
+func (sh *strictHandler) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	var request CreateUploadRequestObject
+
+	var body CreateUploadJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateUpload(ctx, request.(CreateUploadRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateUpload")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateUploadResponseObject); ok {
+		if err := validResponse.VisitCreateUploadResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetUpload operation middleware
+func (sh *strictHandler) GetUpload(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetUploadRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetUpload(ctx, request.(GetUploadRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetUpload")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetUploadResponseObject); ok {
+		if err := validResponse.VisitGetUploadResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// AppendUploadChunk operation middleware
+func (sh *strictHandler) AppendUploadChunk(w http.ResponseWriter, r *http.Request, id string, params AppendUploadChunkParams) {
+	var request AppendUploadChunkRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	request.Body = r.Body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.AppendUploadChunk(ctx, request.(AppendUploadChunkRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "AppendUploadChunk")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(AppendUploadChunkResponseObject); ok {
+		if err := validResponse.VisitAppendUploadChunkResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// FinalizeUpload operation middleware
+func (sh *strictHandler) FinalizeUpload(w http.ResponseWriter, r *http.Request, id string) {
+	var request FinalizeUploadRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.FinalizeUpload(ctx, request.(FinalizeUploadRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "FinalizeUpload")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(FinalizeUploadResponseObject); ok {
+		if err := validResponse.VisitFinalizeUploadResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetUsage operation middleware
+func (sh *strictHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	var request GetUsageRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetUsage(ctx, request.(GetUsageRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetUsage")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetUsageResponseObject); ok {
+		if err := validResponse.VisitGetUsageResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
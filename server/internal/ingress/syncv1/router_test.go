@@ -1,10 +1,20 @@
 package syncv1
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +23,7 @@ import (
 	"go.robinthrift.com/conveyor/internal/auth"
 	"go.robinthrift.com/conveyor/internal/control"
 	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/jobs"
 	"go.robinthrift.com/conveyor/internal/storage/database/sqlite"
 	"go.robinthrift.com/conveyor/internal/storage/filesystem"
 	"go.robinthrift.com/conveyor/internal/testhelper"
@@ -94,16 +105,572 @@ func TestRouter_Attachments(t *testing.T) { //nolint:paralleltest // @TODO: chec
 		res := w.Result()
 		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 	})
+
+	t.Run("Delete", func(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+		uploadAttachment(t)
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/sync/v1/attachments/"+url.PathEscape("a/b/c/d/test"), nil)
+		req.Header.Add(authHeader, "Bearer "+token)
+
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+
+		req = httptest.NewRequest(http.MethodGet, "/blobs/a/b/c/d/test", nil)
+		req.Header.Add(authHeader, "Bearer "+token)
+
+		w = httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("Delete Not Found", func(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+		req := httptest.NewRequest(http.MethodDelete, "/api/sync/v1/attachments/"+url.PathEscape("a/b/c/d/not_found"), nil)
+		req.Header.Add(authHeader, "Bearer "+token)
+
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("Delete Path Traversal", func(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+		uploadAttachment(t)
+
+		for _, filename := range []string{"../1/a/b/c/d/test", "a/../../1/a/b/c/d/test", "/a/b/c/d/test"} {
+			req := httptest.NewRequest(http.MethodDelete, "/api/sync/v1/attachments/"+url.PathEscape(filename), nil)
+			req.Header.Add(authHeader, "Bearer "+token)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, filename)
+		}
+	})
+
+	t.Run("Reserved Paths", func(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+		for _, filepath := range []string{"dbs/conveyor_1.db", "uploads/id/0", "/dbs/conveyor_1.db", "a/../uploads/id/0"} {
+			req := httptest.NewRequest(http.MethodPost, "/api/sync/v1/attachments", bytes.NewReader([]byte(content)))
+			req.Header.Add(authHeader, "Bearer "+token)
+			req.Header.Add("X-Filepath", filepath)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, filepath)
+
+			req = httptest.NewRequest(http.MethodDelete, "/api/sync/v1/attachments/"+url.PathEscape(filepath), nil)
+			req.Header.Add(authHeader, "Bearer "+token)
+
+			w = httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, filepath)
+		}
+	})
+}
+
+func TestRouter_FullSync(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+	mux, token := setupSyncV1Router(t)
+
+	content := []byte("full database content")
+	hash := sha256.Sum256(content)
+	reprDigest := "sha-256=:" + base64.StdEncoding.EncodeToString(hash[:]) + ":"
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sync/v1/full", bytes.NewReader(content))
+	req.Header.Add(authHeader, "Bearer "+token)
+
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/sync/v1/full", nil)
+	req.Header.Add(authHeader, "Bearer "+token)
+
+	w = httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	require.Equal(t, http.StatusSeeOther, res.StatusCode)
+	assert.Equal(t, reprDigest, res.Header.Get("Repr-Digest"))
+	assert.Equal(t, "sha-256="+base64.StdEncoding.EncodeToString(hash[:]), res.Header.Get("Digest"))
+
+	blobURL := res.Header.Get("Location")
+
+	req = httptest.NewRequest(http.MethodGet, blobURL, nil)
+	req.Header.Add(authHeader, "Bearer "+token)
+
+	w = httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res = w.Result()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, content, body)
+	assert.Equal(t, reprDigest, res.Header.Get("Repr-Digest"))
+	assert.Equal(t, strconv.Itoa(len(content)), res.Header.Get("Content-Length"))
+
+	etag := res.Header.Get("ETag")
+	assert.Equal(t, `"`+hex.EncodeToString(hash[:])+`"`, etag)
+
+	req = httptest.NewRequest(http.MethodGet, blobURL, nil)
+	req.Header.Add(authHeader, "Bearer "+token)
+	req.Header.Add("Range", "bytes=5-12")
+
+	w = httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res = w.Result()
+	body, err = io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusPartialContent, res.StatusCode)
+	assert.Equal(t, "database", string(body))
+	assert.Equal(t, "bytes 5-12/21", res.Header.Get("Content-Range"))
+
+	req = httptest.NewRequest(http.MethodGet, blobURL, nil)
+	req.Header.Add(authHeader, "Bearer "+token)
+	req.Header.Add("If-None-Match", etag)
+
+	w = httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/blobs/dbs/unknown.db", nil)
+	req.Header.Add(authHeader, "Bearer "+token)
+
+	w = httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+}
+
+func TestRouter_Uploads(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+	mux, token := setupSyncV1Router(t)
+
+	content := []byte("full database content")
+	hash := sha256.Sum256(content)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sync/v1/uploads", strings.NewReader(
+		`{"kind":"fullSync","sizeBytes":`+strconv.Itoa(len(content))+`,"sha256":"`+hex.EncodeToString(hash[:])+`"}`,
+	))
+	req.Header.Add(authHeader, "Bearer "+token)
+	req.Header.Add("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var upload Upload
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&upload))
+	assert.Equal(t, UploadKindFullSync, upload.Kind)
+	assert.Equal(t, int64(0), upload.OffsetBytes)
+
+	appendChunk := func(t *testing.T, offset int, chunk []byte) *http.Response {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/sync/v1/uploads/"+upload.ID, bytes.NewReader(chunk))
+		req.Header.Add(authHeader, "Bearer "+token)
+		req.Header.Add("Upload-Offset", strconv.Itoa(offset))
+
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		return w.Result()
+	}
+
+	res = appendChunk(t, 0, content[:4])
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	res = appendChunk(t, 0, content[4:])
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/sync/v1/uploads/"+upload.ID, nil)
+	req.Header.Add(authHeader, "Bearer "+token)
+
+	w = httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&upload))
+	assert.Equal(t, int64(4), upload.OffsetBytes)
+
+	res = appendChunk(t, int(upload.OffsetBytes), content[upload.OffsetBytes:])
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/sync/v1/uploads/"+upload.ID+"/finalize", nil)
+	req.Header.Add(authHeader, "Bearer "+token)
+
+	w = httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/sync/v1/full", nil)
+	req.Header.Add(authHeader, "Bearer "+token)
+
+	w = httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res = w.Result()
+	require.Equal(t, http.StatusSeeOther, res.StatusCode)
+	assert.Equal(t, "sha-256="+base64.StdEncoding.EncodeToString(hash[:]), res.Header.Get("Digest"))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/sync/v1/uploads/"+upload.ID, nil)
+	req.Header.Add(authHeader, "Bearer "+token)
+
+	w = httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+}
+
+func TestRouter_UploadLimits(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+	mux, token := setupSyncV1RouterWithConfig(t, RouterConfig{
+		BasePath:                "/",
+		MaxAttachmentSizeBytes:  4 << 20,
+		MaxFullSyncSizeBytes:    10,
+		MaxUploadChunkSizeBytes: 10,
+		MaxDecompressionRatio:   10,
+	})
+
+	uploadAttachment := func(t *testing.T, body []byte, contentEncoding string) *http.Response {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/sync/v1/attachments", bytes.NewReader(body))
+		req.Header.Add(authHeader, "Bearer "+token)
+		req.Header.Add("X-Filepath", "a/b/test")
+
+		if contentEncoding != "" {
+			req.Header.Add("Content-Encoding", contentEncoding)
+		}
+
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		return w.Result()
+	}
+
+	gzipped := func(t *testing.T, data []byte) []byte {
+		t.Helper()
+
+		var buf bytes.Buffer
+
+		gw := gzip.NewWriter(&buf)
+		_, err := gw.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+
+		return buf.Bytes()
+	}
+
+	t.Run("Full Sync Too Large", func(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+		req := httptest.NewRequest(http.MethodPost, "/api/sync/v1/full", bytes.NewReader([]byte("full database content")))
+		req.Header.Add(authHeader, "Bearer "+token)
+
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		res := w.Result()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+		assert.Contains(t, string(body), `"type":"conveyor/api/v1/sync/PayloadTooLarge"`)
+	})
+
+	t.Run("Attachment Gzip", func(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+		res := uploadAttachment(t, gzipped(t, []byte("attachment content")), "gzip")
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+	})
+
+	t.Run("Attachment Too Large", func(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+		res := uploadAttachment(t, bytes.Repeat([]byte("0123456789"), 1<<19), "")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+	})
+
+	t.Run("Gzip Bomb", func(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+		res := uploadAttachment(t, gzipped(t, make([]byte, 3<<20)), "gzip")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+	})
+
+	t.Run("Unsupported Encoding", func(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+		res := uploadAttachment(t, []byte("attachment content"), "br")
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.StatusCode)
+	})
+
+	t.Run("Resumable Upload Too Large", func(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+		req := httptest.NewRequest(http.MethodPost, "/api/sync/v1/uploads", strings.NewReader(
+			`{"kind":"fullSync","sizeBytes":11,"sha256":"`+strings.Repeat("00", 32)+`"}`,
+		))
+		req.Header.Add(authHeader, "Bearer "+token)
+		req.Header.Add("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Result().StatusCode)
+	})
+}
+
+func TestRouter_Usage(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+	mux, token := setupSyncV1Router(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sync/v1/full", bytes.NewReader([]byte("full database content")))
+	req.Header.Add(authHeader, "Bearer "+token)
+
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/sync/v1/usage", nil)
+	req.Header.Add(authHeader, "Bearer "+token)
+
+	w = httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.JSONEq(t, `{"sizeBytes":21,"numEntries":0}`, string(body))
+}
+
+func TestRouter_StreamChangelogEntries(t *testing.T) { //nolint:paralleltest // @TODO: check why these fail when run in parallel
+	mux, token := setupSyncV1Router(t)
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	createEntry := func(t *testing.T, data string) {
+		t.Helper()
+
+		body := `{"items": [{"syncClientID": "client_a", "data": "` + data + `", "timestamp": "2024-11-29T13:22:00.000Z"}]}`
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, srv.URL+"/api/sync/v1/changes", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Add(authHeader, "Bearer "+token)
+		req.Header.Add("Content-Type", "application/json")
+
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+	}
+
+	listEntries := func(t *testing.T, pageAfter string) EncryptedChangelogEntriesList {
+		t.Helper()
+
+		query := url.Values{"page[size]": []string{"1"}}
+		if pageAfter != "" {
+			query.Set("page[after]", pageAfter)
+		}
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/api/sync/v1/changes?"+query.Encode(), nil)
+		require.NoError(t, err)
+		req.Header.Add(authHeader, "Bearer "+token)
+
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var list EncryptedChangelogEntriesList
+
+		err = json.NewDecoder(res.Body).Decode(&list)
+		require.NoError(t, err)
+
+		return list
+	}
+
+	openStream := func(t *testing.T, after string, lastEventID string) *bufio.Reader {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		t.Cleanup(cancel)
+
+		streamURL := srv.URL + "/api/sync/v1/changes/stream"
+		if after != "" {
+			streamURL += "?after=" + url.QueryEscape(after)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+		require.NoError(t, err)
+		req.Header.Add(authHeader, "Bearer "+token)
+
+		if lastEventID != "" {
+			req.Header.Add("Last-Event-ID", lastEventID)
+		}
+
+		res, err := srv.Client().Do(req) //nolint:bodyclose // closed by cancelling the context
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+		return bufio.NewReader(res.Body)
+	}
+
+	readEvent := func(t *testing.T, r *bufio.Reader) (string, string) {
+		t.Helper()
+
+		var id, data string
+
+		for {
+			line, err := r.ReadString('\n')
+			require.NoError(t, err)
+
+			line = strings.TrimSuffix(line, "\n")
+
+			switch {
+			case line == "":
+				return id, data
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}
+
+	createEntry(t, "AQ==")
+
+	// without a cursor the history is skipped
+	stream := openStream(t, "", "")
+
+	createEntry(t, "Ag==")
+
+	id, data := readEvent(t, stream)
+	assert.Equal(t, "2", id)
+	assert.Contains(t, data, `"data":"Ag=="`)
+
+	resumed := openStream(t, "", "0")
+
+	id, data = readEvent(t, resumed)
+	assert.Equal(t, "1", id)
+	assert.Contains(t, data, `"data":"AQ=="`)
+
+	id, data = readEvent(t, resumed)
+	assert.Equal(t, "2", id)
+	assert.Contains(t, data, `"data":"Ag=="`)
+
+	// a client that has caught up by paging through the changes continues streaming from the last cursor, so entries
+	// created in between aren't lost
+	list := listEntries(t, "")
+	for list.HasMore {
+		list = listEntries(t, list.Next)
+	}
+
+	assert.Equal(t, "2", list.Next)
+
+	createEntry(t, "Aw==")
+
+	caughtUp := openStream(t, list.Next, "")
+
+	id, data = readEvent(t, caughtUp)
+	assert.Equal(t, "3", id)
+	assert.Contains(t, data, `"data":"Aw=="`)
+}
+
+func TestRouter_APITokenScopes(t *testing.T) {
+	t.Parallel()
+
+	mux, token := setupSyncV1RouterWithAPITokenScopes(t, RouterConfig{BasePath: "/"}, domain.APITokenScopes{domain.APITokenScopeSyncRead})
+
+	t.Run("Granted Scope", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/sync/v1/usage", nil)
+		req.Header.Add(authHeader, "Bearer "+token)
+
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("Missing Scope", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/sync/v1/clients", strings.NewReader(`{"clientID":"client_a"}`))
+		req.Header.Add(authHeader, "Bearer "+token)
+		req.Header.Add("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		var apiErr map[string]any
+		err := json.NewDecoder(res.Body).Decode(&apiErr)
+		require.NoError(t, err)
+		assert.Equal(t, "conveyor/api/v1/sync/MissingScope", apiErr["type"])
+		assert.Contains(t, apiErr["detail"], string(domain.APITokenScopeSyncWrite))
+	})
+
+	t.Run("Blobs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/blobs/a/b/c/d/not_found", nil)
+		req.Header.Add(authHeader, "Bearer "+token)
+
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
 }
 
 func setupSyncV1Router(t *testing.T) (http.Handler, string) {
 	t.Helper()
 
+	return setupSyncV1RouterWithConfig(t, RouterConfig{BasePath: "/"})
+}
+
+func setupSyncV1RouterWithConfig(t *testing.T, routerConfig RouterConfig) (http.Handler, string) {
+	t.Helper()
+
+	return setupSyncV1RouterWithAPITokenScopes(t, routerConfig, nil)
+}
+
+// setupSyncV1RouterWithAPITokenScopes returns an API token with the given scopes instead of a session token, unless
+// the scopes are nil.
+func setupSyncV1RouterWithAPITokenScopes(t *testing.T, routerConfig RouterConfig, scopes domain.APITokenScopes) (http.Handler, string) {
+	t.Helper()
+
 	db := testhelper.NewInMemTestSQLite(t)
 
 	accountRepo := sqlite.NewAccountRepo(db)
 	syncRepo := sqlite.NewSyncRepo(db)
 	authTokenRepo := sqlite.NewAuthTokenRepo(db)
+	totpRepo := sqlite.NewTOTPRepo(db)
 
 	config := control.AuthConfig{
 		Argon2Params:              auth.Argon2Params{KeyLen: 32, Memory: 8192, Threads: 2, Time: 1},
@@ -118,10 +685,14 @@ func setupSyncV1Router(t *testing.T) (http.Handler, string) {
 		TmpDir:  t.TempDir(),
 	}
 
-	accountCtrl := control.NewAccountController(db, accountRepo)
-	authCtrl := control.NewAuthController(config, db, accountCtrl, authTokenRepo)
-	attachmentCtrl := control.NewAttachmentController(blobs)
-	syncCtrl := control.NewSyncController(db, syncRepo, accountCtrl, attachmentCtrl, blobs)
+	accountCtrl := control.NewAccountController(db, accountRepo, blobs)
+	apiTokenUsage := control.NewAPITokenUsageTracker(control.APITokenUsageConfig{}, db, sqlite.NewAPITokenRepo(db))
+	authCtrl := control.NewAuthController(config, db, accountCtrl, authTokenRepo, totpRepo, apiTokenUsage)
+	usageCtrl := control.NewUsageController(control.QuotaConfig{}, sqlite.NewUsageRepo(db))
+	attachmentCtrl := control.NewAttachmentController(db, blobs, sqlite.NewAttachmentRepo(db), usageCtrl)
+	jobSystem := jobs.NewSystem(jobs.SystemConfig{}, db, sqlite.NewJobRepo(db), accountCtrl, time.Now, nil)
+	syncCtrl := control.NewSyncController(control.SyncConfig{}, db, syncRepo, accountCtrl, attachmentCtrl, usageCtrl, blobs, jobSystem)
+	uploadCtrl := control.NewUploadController(control.UploadConfig{Expiry: time.Hour}, db, sqlite.NewUploadRepo(db), blobs, syncCtrl, usageCtrl, jobSystem)
 
 	err := authCtrl.CreateAccount(t.Context(), control.CreateAccountCmd{
 		Account: &domain.Account{
@@ -152,7 +723,27 @@ func setupSyncV1Router(t *testing.T) (http.Handler, string) {
 
 	mux := http.NewServeMux()
 
-	New(RouterConfig{BasePath: "/"}, mux, syncCtrl, authCtrl, http.Dir(blobDir))
+	New(routerConfig, mux, syncCtrl, usageCtrl, uploadCtrl, authCtrl, blobs)
+
+	if scopes == nil {
+		return mux, token.Plaintext.Export()
+	}
+
+	account, err := authCtrl.GetAccountForAuthToken(t.Context(), token.Plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiTokenCtrl := control.NewAPITokenController(config, db, sqlite.NewAPITokenRepo(db), authTokenRepo)
+
+	apiToken, err := apiTokenCtrl.CreateAPIToken(auth.CtxWithAccount(t.Context(), account), control.CreateAPITokenCmd{
+		Name:      t.Name(),
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	return mux, token.Plaintext.Export()
+	return mux, apiToken
 }
@@ -5,3 +5,9 @@ import (
 )
 
 var ErrUnknownJobKind = errors.New("unknown job kind")
+var ErrInvalidSchedule = errors.New("invalid schedule")
+var ErrJobTimeout = errors.New("job timed out")
+var ErrJobPanicked = errors.New("job panicked")
+var ErrLeaseExpired = errors.New("job lease expired")
+var ErrJobAbandoned = errors.New("job did not return after being cancelled")
+var ErrInvalidJobState = errors.New("invalid job state")
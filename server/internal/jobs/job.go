@@ -9,6 +9,9 @@ import (
 )
 
 type JobKind[T any] interface {
+	// Exec must return promptly once ctx is done, e.g. because the job timed out or its lease has been lost, as the job
+	// is only retried or picked up by another instance once the cancelled run has returned, or has been abandoned after
+	// the grace period.
 	Exec(ctx context.Context, data T) (*domain.JobResult, error)
 }
 
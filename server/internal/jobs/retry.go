@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how often a failed job is retried before it's moved to the dead-letter state.
+type RetryPolicy struct {
+	// MaxAttempts includes the first run, 1 disables retries.
+	MaxAttempts int64
+	// InitialBackoff is the delay before the first retry, it doubles with every further attempt.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Jitter randomly varies the backoff by the given fraction, e.g. 0.2 for ±20%.
+	Jitter float64
+}
+
+//nolint:gochecknoglobals,mnd
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: time.Second * 30,
+	MaxBackoff:     time.Hour,
+	Jitter:         0.2,
+}
+
+// Backoff returns the delay before the next attempt, after the given number of failed attempts.
+func (p RetryPolicy) Backoff(attempts int64) time.Duration {
+	backoff := p.InitialBackoff
+	for i := int64(1); i < attempts && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		backoff += time.Duration(float64(backoff) * p.Jitter * (rand.Float64()*2 - 1)) //nolint:gosec // no need for crypto randomness
+	}
+
+	return backoff
+}
@@ -0,0 +1,258 @@
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurringJob is a job that is rescheduled according to its Schedule after every run.
+// Recurring jobs are identified by their Name, so registering the same job again won't create a duplicate.
+// A RecurringJob without a Schedule removes a previously scheduled run.
+type RecurringJob struct {
+	Name     string
+	Kind     string
+	Data     any
+	Schedule Schedule
+}
+
+type Schedule interface {
+	// Next returns the next occurrence after t or the zero time if there is none.
+	Next(t time.Time) time.Time
+	// String returns the spec the schedule can be parsed from using ParseSchedule.
+	String() string
+}
+
+// Every schedules a job at a fixed interval, relative to the last run.
+func Every(interval time.Duration) Schedule {
+	return every(interval)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+func (e every) String() string {
+	return "@every " + time.Duration(e).String()
+}
+
+//nolint:gochecknoglobals
+var scheduleDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses either an interval in the form of `@every <duration>`, one of the descriptors `@yearly`,
+// `@monthly`, `@weekly`, `@daily` or `@hourly` or a standard five field cron expression. Cron expressions are
+// evaluated in UTC.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if interval, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidSchedule, spec, err)
+		}
+
+		if d <= 0 {
+			return nil, fmt.Errorf("%w: %s: interval must be positive", ErrInvalidSchedule, spec)
+		}
+
+		return Every(d), nil
+	}
+
+	if expr, ok := scheduleDescriptors[spec]; ok {
+		schedule, err := parseCron(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		schedule.spec = spec
+
+		return schedule, nil
+	}
+
+	return parseCron(spec)
+}
+
+type cronSchedule struct {
+	spec    string
+	minute  cronField
+	hour    cronField
+	dom     cronField
+	month   cronField
+	dow     cronField
+	domStar bool
+	dowStar bool
+}
+
+type cronField uint64
+
+func (f cronField) has(v int) bool {
+	return f&(1<<uint(v)) != 0 //nolint:gosec // v is always within the field bounds
+}
+
+type cronFieldBounds struct {
+	name string
+	min  int
+	max  int
+}
+
+//nolint:gochecknoglobals,mnd
+var cronFields = [5]cronFieldBounds{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+func parseCron(spec string) (*cronSchedule, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("%w: %s: expected %d fields, got %d", ErrInvalidSchedule, spec, len(cronFields), len(parts))
+	}
+
+	var fields [5]cronField
+
+	for i, part := range parts {
+		field, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidSchedule, spec, err)
+		}
+
+		fields[i] = field
+	}
+
+	// both 0 and 7 are sunday
+	if fields[4].has(7) {
+		fields[4] |= 1
+	}
+
+	return &cronSchedule{
+		spec:    spec,
+		minute:  fields[0],
+		hour:    fields[1],
+		dom:     fields[2],
+		month:   fields[3],
+		dow:     fields[4],
+		domStar: parts[2] == "*",
+		dowStar: parts[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, bounds cronFieldBounds) (cronField, error) {
+	var result cronField
+
+	for item := range strings.SplitSeq(field, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+
+		step := 1
+
+		if hasStep {
+			var err error
+
+			step, err = strconv.Atoi(stepExpr)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field: %s", bounds.name, item)
+			}
+		}
+
+		start, end := bounds.min, bounds.max
+
+		if rangeExpr != "*" {
+			from, to, isRange := strings.Cut(rangeExpr, "-")
+
+			var err error
+
+			start, err = strconv.Atoi(from)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value in %s field: %s", bounds.name, item)
+			}
+
+			end = start
+
+			if isRange {
+				end, err = strconv.Atoi(to)
+				if err != nil {
+					return 0, fmt.Errorf("invalid value in %s field: %s", bounds.name, item)
+				}
+			} else if hasStep {
+				end = bounds.max
+			}
+		}
+
+		if start < bounds.min || end > bounds.max || start > end {
+			return 0, fmt.Errorf("%s field out of range %d-%d: %s", bounds.name, bounds.min, bounds.max, item)
+		}
+
+		for v := start; v <= end; v += step {
+			result |= 1 << uint(v) //nolint:gosec // v is always within the field bounds
+		}
+	}
+
+	return result, nil
+}
+
+// maxCronSearch limits the search for the next occurrence, for expressions that never match, like `0 0 30 2 *`.
+const maxCronSearch = 5
+
+func (c *cronSchedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxCronSearch, 0, 0)
+
+	for t.Before(limit) {
+		if !c.month.has(int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+
+			continue
+		}
+
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+
+			continue
+		}
+
+		if !c.hour.has(t.Hour()) {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+
+			continue
+		}
+
+		if !c.minute.has(t.Minute()) {
+			t = t.Add(time.Minute)
+
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchesDay follows the cron semantics of matching either the day of month or the day of week if both are
+// restricted.
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	dom := c.dom.has(t.Day())
+	dow := c.dow.has(int(t.Weekday()))
+
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+func (c *cronSchedule) String() string {
+	return c.spec
+}
@@ -2,8 +2,13 @@ package jobs
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"runtime/debug"
 	"sync"
 	"time"
 
@@ -17,17 +22,38 @@ type Scheduler interface {
 	Schedule(ctx context.Context, job *domain.Job) error
 }
 
+type SystemConfig struct {
+	// Workers limits the number of jobs that are executed concurrently.
+	Workers int
+	// Timeout is the default execution timeout, which can be overridden per job kind using RegisterTimeout.
+	Timeout time.Duration
+	// InstanceID identifies the instance as the owner of the jobs it executes, when multiple instances share a
+	// database. Defaults to the hostname with a random suffix.
+	InstanceID string
+	// LeaseDuration is the time after which the jobs of an instance that stopped renewing their leases, e.g. because
+	// it crashed, are picked up by another instance.
+	LeaseDuration time.Duration
+	// CancelGracePeriod is the time a cancelled job is given to return. Jobs that ignore their context are abandoned
+	// afterwards, so they don't occupy a worker, keep their lease or block stopping the job system forever.
+	CancelGracePeriod time.Duration
+}
+
 type System struct {
+	config         SystemConfig
 	transactioner  database.Transactioner
 	repo           SystemJobRepo
 	accountFetcher SystemAccountFetcher
 	now            SystemTimeNowFunc
 	jobKinds       map[string]JobKindWithJSONData
-
-	wakeup    chan struct{}
-	timer     *time.Timer
-	isRunning bool
-	mu        sync.Mutex
+	recurring      []RecurringJob
+	retryPolicies  map[string]RetryPolicy
+	timeouts       map[string]time.Duration
+
+	wakeup  chan struct{}
+	workers chan struct{}
+	running sync.WaitGroup
+	timer   *time.Timer
+	mu      sync.Mutex
 }
 
 type SystemTimeNowFunc func() time.Time
@@ -37,35 +63,110 @@ type SystemAccountFetcher interface {
 }
 
 type SystemJobRepo interface {
-	ListNextJobs(ctx context.Context, scheduledFor time.Time) ([]*domain.Job, error)
+	GetNextDueJob(ctx context.Context, now time.Time) (*domain.Job, error)
+	ClaimJob(ctx context.Context, job *domain.Job) (bool, error)
+	RenewJobLease(ctx context.Context, job *domain.Job) (bool, error)
+	ListJobsWithExpiredLease(ctx context.Context, now time.Time) ([]*domain.Job, error)
 	CreateJob(ctx context.Context, job *domain.Job) error
 	UpdateJob(ctx context.Context, job *domain.Job) error
-	GetNextWakeUpTime(ctx context.Context) (time.Time, error)
+	GetNextWakeUpTime(ctx context.Context, now time.Time) (time.Time, error)
+	GetScheduledJobByName(ctx context.Context, name string) (*domain.Job, error)
+	GetRunningJobByName(ctx context.Context, name string) (*domain.Job, error)
+	UpdateScheduledJob(ctx context.Context, job *domain.Job) error
+	DeleteScheduledJobByName(ctx context.Context, name string) error
+	GetJob(ctx context.Context, id int64) (*domain.Job, error)
+	ListJobs(ctx context.Context, query domain.ListJobsQuery) (*domain.JobList, error)
+	RequeueFailedJob(ctx context.Context, id int64, scheduledFor time.Time) error
+	CancelScheduledJob(ctx context.Context, id int64, cancelledAt time.Time) error
 }
 
-func NewSystem(transactioner database.Transactioner, repo SystemJobRepo, accountFetcher SystemAccountFetcher, nowFunc SystemTimeNowFunc, jobKinds map[string]JobKindWithJSONData) *System {
+const (
+	defaultWorkers             = 2
+	defaultJobExecutionTimeout = time.Minute * 10
+	defaultLeaseDuration       = time.Minute
+	defaultCancelGracePeriod   = time.Second * 30
+	// leaseRenewals is the number of times a lease is renewed per LeaseDuration, so that a single failed renewal
+	// doesn't cause the lease to expire.
+	leaseRenewals = 3
+	// minWakeUpDelay prevents a busy loop for wake-up times that are already due but can't be handled yet, as times
+	// are only stored with a precision of seconds.
+	minWakeUpDelay = time.Millisecond * 100
+)
+
+func NewSystem(config SystemConfig, transactioner database.Transactioner, repo SystemJobRepo, accountFetcher SystemAccountFetcher, nowFunc SystemTimeNowFunc, jobKinds map[string]JobKindWithJSONData) *System {
+	if config.Workers <= 0 {
+		config.Workers = defaultWorkers
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = defaultJobExecutionTimeout
+	}
+
+	if config.LeaseDuration <= 0 {
+		config.LeaseDuration = defaultLeaseDuration
+	}
+
+	if config.CancelGracePeriod <= 0 {
+		config.CancelGracePeriod = defaultCancelGracePeriod
+	}
+
+	if config.InstanceID == "" {
+		config.InstanceID = newInstanceID()
+	}
+
 	return &System{
+		config:         config,
 		transactioner:  transactioner,
 		repo:           repo,
 		accountFetcher: accountFetcher,
 		now:            nowFunc,
 		jobKinds:       jobKinds,
+		retryPolicies:  map[string]RetryPolicy{},
+		timeouts:       map[string]time.Duration{},
 		wakeup:         make(chan struct{}, 1),
+		workers:        make(chan struct{}, config.Workers),
 	}
 }
 
+// RegisterRecurring registers jobs that will be scheduled on Start and rescheduled after every run.
+// Must be called before Start.
+func (s *System) RegisterRecurring(jobs ...RecurringJob) {
+	s.recurring = append(s.recurring, jobs...)
+}
+
+// RegisterRetryPolicy overrides the DefaultRetryPolicy for the job kind.
+// Must be called before Start.
+func (s *System) RegisterRetryPolicy(kind string, policy RetryPolicy) {
+	s.retryPolicies[kind] = policy
+}
+
+// RegisterTimeout overrides the default execution timeout for the job kind.
+// Must be called before Start.
+func (s *System) RegisterTimeout(kind string, timeout time.Duration) {
+	s.timeouts[kind] = timeout
+}
+
+// Start executes due jobs until the context is cancelled and waits for the running jobs to return or be abandoned.
 func (s *System) Start(ctx context.Context) {
-	slog.InfoContext(ctx, "starting job system")
+	slog.InfoContext(ctx, "starting job system", slog.String("instance_id", s.config.InstanceID))
+
+	err := s.transactioner.InTransaction(ctx, s.scheduleRecurringJobs)
+	if err != nil {
+		slog.ErrorContext(ctx, "error scheduling recurring jobs", slog.Any("error", err))
+	}
+
 	s.scheduleWakeup(ctx)
 
 	for {
 		select {
 		case <-ctx.Done():
 			slog.InfoContext(ctx, "stopping job system")
+			s.stopTimer()
+			s.running.Wait()
 
 			return
 		case <-s.wakeup:
-			s.startJobExecution(ctx)
+			s.dispatch(ctx)
 		}
 	}
 }
@@ -79,17 +180,34 @@ func (s *System) Schedule(ctx context.Context, job *domain.Job) error {
 	job.State = domain.JobStateScheduled
 	job.Result = nil
 
+	if job.Schedule != "" {
+		schedule, err := ParseSchedule(job.Schedule)
+		if err != nil {
+			return err
+		}
+
+		if job.ScheduledFor.IsZero() {
+			job.ScheduledFor = schedule.Next(s.now())
+		}
+
+		if job.ScheduledFor.IsZero() {
+			return fmt.Errorf("%w: %s never occurs", ErrInvalidSchedule, job.Schedule)
+		}
+	}
+
 	if job.ScheduledFor.IsZero() {
 		job.ScheduledFor = s.now()
 	}
 
-	err := s.repo.CreateJob(ctx, job)
+	err := s.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		return s.createOrReplaceJob(ctx, job)
+	})
 	if err != nil {
 		return err
 	}
 
 	if job.ScheduledFor.Sub(s.now()) <= time.Second {
-		s.wakeup <- struct{}{}
+		s.triggerWakeup()
 	} else {
 		s.scheduleWakeup(ctx)
 	}
@@ -101,11 +219,7 @@ func (s *System) scheduleWakeup(ctx context.Context) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.isRunning {
-		return
-	}
-
-	next, err := s.repo.GetNextWakeUpTime(ctx)
+	next, err := s.repo.GetNextWakeUpTime(ctx, s.now())
 	if err != nil {
 		slog.ErrorContext(ctx, "error getting next wake-up time", slog.Any("error", err))
 
@@ -120,73 +234,590 @@ func (s *System) scheduleWakeup(ctx context.Context) {
 		s.timer.Stop()
 	}
 
-	s.timer = time.AfterFunc(next.Sub(s.now()), func() {
-		s.wakeup <- struct{}{}
+	s.timer = time.AfterFunc(max(next.Sub(s.now()), minWakeUpDelay), s.triggerWakeup)
+}
+
+func (s *System) stopTimer() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
+
+// createOrReplaceJob replaces a scheduled job with the same name, so that named jobs are only scheduled once.
+func (s *System) createOrReplaceJob(ctx context.Context, job *domain.Job) error {
+	if job.Name == "" {
+		return s.repo.CreateJob(ctx, job)
+	}
+
+	existing, err := s.repo.GetScheduledJobByName(ctx, job.Name)
+	if err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) {
+			return s.repo.CreateJob(ctx, job)
+		}
+
+		return err
+	}
+
+	job.ID = existing.ID
+
+	return s.repo.UpdateScheduledJob(ctx, job)
+}
+
+// scheduleRecurringJobs schedules the registered recurring jobs. Runs that were persisted before a restart are kept,
+// unless their schedule changed. A run that is still running, e.g. because the instance crashed, schedules the next
+// occurrence itself once it has finished.
+func (s *System) scheduleRecurringJobs(ctx context.Context) error {
+	for _, recurring := range s.recurring {
+		if recurring.Schedule == nil {
+			err := s.repo.DeleteScheduledJobByName(ctx, recurring.Name)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		existing, err := s.repo.GetScheduledJobByName(ctx, recurring.Name)
+		if err != nil && !errors.Is(err, domain.ErrJobNotFound) {
+			return err
+		}
+
+		if existing == nil {
+			existing, err = s.repo.GetRunningJobByName(ctx, recurring.Name)
+			if err != nil && !errors.Is(err, domain.ErrJobNotFound) {
+				return err
+			}
+		}
+
+		if existing != nil && existing.Kind == recurring.Kind && existing.Schedule == recurring.Schedule.String() {
+			continue
+		}
+
+		next := recurring.Schedule.Next(s.now())
+		if next.IsZero() {
+			return fmt.Errorf("%w: %s never occurs", ErrInvalidSchedule, recurring.Schedule)
+		}
+
+		if existing != nil && existing.State == domain.JobStateScheduled {
+			err = s.repo.DeleteScheduledJobByName(ctx, recurring.Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.repo.CreateJob(ctx, &domain.Job{
+			Name:         recurring.Name,
+			State:        domain.JobStateScheduled,
+			Kind:         recurring.Kind,
+			Data:         recurring.Data,
+			Schedule:     recurring.Schedule.String(),
+			ScheduledFor: next,
+		})
+		if err != nil {
+			return fmt.Errorf("error scheduling recurring job %s: %w", recurring.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// scheduleNextRun persists the next occurrence of a recurring job, the finished run is kept as history.
+func (s *System) scheduleNextRun(ctx context.Context, job *domain.Job) error {
+	if job.Schedule == "" {
+		return nil
+	}
+
+	schedule, err := ParseSchedule(job.Schedule)
+	if err != nil {
+		return err
+	}
+
+	next := schedule.Next(s.now())
+	if next.IsZero() {
+		return nil
+	}
+
+	return s.repo.CreateJob(ctx, &domain.Job{
+		Name:         job.Name,
+		State:        domain.JobStateScheduled,
+		Kind:         job.Kind,
+		Data:         json.RawMessage(job.Data.([]byte)), //nolint:forcetypeassert // jobs loaded from the repo always contain the raw JSON
+		Schedule:     job.Schedule,
+		ScheduledFor: next,
 	})
 }
 
-const defaultJobExecutionTimeout = time.Minute * 10
+// triggerWakeup never blocks, as a pending wakeup will already execute all due jobs.
+func (s *System) triggerWakeup() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
 
-func (s *System) startJobExecution(ctx context.Context) {
-	s.mu.Lock()
-	s.isRunning = true
-	s.mu.Unlock()
+// dispatch claims due jobs as long as there are idle workers. Workers trigger a wakeup when they are done, so
+// dispatch never has to wait for a busy worker.
+func (s *System) dispatch(ctx context.Context) {
+	s.failExpiredLeases(ctx)
 
-	defer func() {
-		s.mu.Lock()
-		s.isRunning = false
-		s.mu.Unlock()
-		s.scheduleWakeup(ctx)
-	}()
+	for {
+		select {
+		case s.workers <- struct{}{}:
+		default:
+			return
+		}
 
-	ctx, cancel := context.WithTimeout(ctx, defaultJobExecutionTimeout)
-	defer cancel()
+		job, err := s.claimNextJob(ctx)
+		if err != nil || job == nil {
+			<-s.workers
+
+			if err != nil {
+				slog.ErrorContext(ctx, "error claiming job", slog.Any("error", err))
+			}
+
+			s.scheduleWakeup(ctx)
+
+			return
+		}
+
+		s.running.Add(1)
+
+		go func() {
+			defer func() {
+				<-s.workers
+				s.running.Done()
+				s.triggerWakeup()
+			}()
+
+			s.runJob(ctx, job)
+		}()
+	}
+}
+
+// claimNextJob marks the next due job as running, so that no other worker picks it up. Returns nil if no job is due.
+func (s *System) claimNextJob(ctx context.Context) (*domain.Job, error) {
+	var claimed *domain.Job
 
 	err := s.transactioner.InTransaction(ctx, func(ctx context.Context) error {
-		return s.execJobs(ctx)
+		job, err := s.repo.GetNextDueJob(ctx, s.now())
+		if err != nil {
+			if errors.Is(err, domain.ErrJobNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		job.LeaseOwner = s.config.InstanceID
+		job.LeaseExpiresAt = s.now().Add(s.config.LeaseDuration)
+
+		ok, err := s.repo.ClaimJob(ctx, job)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			claimed = job
+		}
+
+		return nil
 	})
+
+	return claimed, err
+}
+
+// failExpiredLeases records a failed attempt for jobs whose worker is gone. Every job is failed in its own
+// transaction, so that a single job that can't be updated doesn't block the others.
+func (s *System) failExpiredLeases(ctx context.Context) {
+	jobs, err := s.repo.ListJobsWithExpiredLease(ctx, s.now())
 	if err != nil {
-		slog.ErrorContext(ctx, "error executing jobs", slog.Any("error", err))
+		slog.ErrorContext(ctx, "error listing jobs with expired lease", slog.Any("error", err))
+
+		return
+	}
+
+	for _, job := range jobs {
+		slog.WarnContext(ctx, "job lease expired", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID))
+		s.recordFailure(job, ErrLeaseExpired)
+
+		err = s.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+			return s.finishJob(ctx, job)
+		})
+
+		switch {
+		// another instance has already failed the job
+		case errors.Is(err, domain.ErrJobLeaseLost):
+		case err != nil:
+			slog.ErrorContext(ctx, "error failing job with expired lease", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID), slog.Any("error", err))
+		}
 	}
 }
 
-func (s *System) execJobs(ctx context.Context) error {
-	ctx, cancel := context.WithCancel(ctx)
+// runJob executes the job outside of a transaction: SQLite only allows a single writer and transactions acquire the
+// write lock when they begin, so a transaction spanning the whole run would block the renewal of the job's lease, and
+// every other write, until the job returns. Jobs that need atomic writes use their own, short transactions instead.
+//
+// A job whose timeout has elapsed or whose lease has been lost is cancelled. The worker waits for the cancelled run to
+// return before the outcome is recorded, so a retry doesn't run concurrently with the previous attempt. A run that
+// hasn't returned within the CancelGracePeriod is abandoned: its lease is no longer renewed and the failure is
+// recorded, which frees the worker.
+func (s *System) runJob(ctx context.Context, job *domain.Job) {
+	ctx = tracing.RequestIDWithCtx(ctx, tracing.NewRequestID())
+
+	slog.InfoContext(ctx, "starting job", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID))
+
+	timeout := s.timeout(job.Kind)
+
+	execCtx, cancel := context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w after %s", ErrJobTimeout, timeout))
 	defer cancel()
 
-	jobs, err := s.repo.ListNextJobs(ctx, s.now())
+	execCtx, cancelExec := context.WithCancelCause(execCtx)
+	defer cancelExec(nil)
+
+	// the lease is renewed until the run has returned or has been abandoned, even if it has been cancelled, so no other
+	// instance picks up the job in the meantime
+	leaseCtx, stopRenewing := context.WithCancel(context.WithoutCancel(ctx))
+
+	renewed := make(chan struct{})
+
+	go func() {
+		s.renewLease(leaseCtx, cancelExec, &domain.Job{ID: job.ID, Kind: job.Kind, LeaseOwner: job.LeaseOwner})
+		close(renewed)
+	}()
+
+	// buffered, so an abandoned run can still return
+	returned := make(chan runOutcome, 1)
+
+	go func() {
+		result, err := s.execJob(execCtx, job)
+		returned <- runOutcome{result, err}
+	}()
+
+	var outcome runOutcome
+
+	select {
+	case outcome = <-returned:
+	case <-execCtx.Done():
+		grace := time.NewTimer(s.config.CancelGracePeriod)
+
+		select {
+		case outcome = <-returned:
+		case <-grace.C:
+			slog.ErrorContext(ctx, "cancelled job did not return, abandoning run", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID))
+
+			outcome.err = fmt.Errorf("%w: %w after %s", context.Cause(execCtx), ErrJobAbandoned, s.config.CancelGracePeriod)
+		}
+
+		grace.Stop()
+	}
+
+	stopRenewing()
+	<-renewed
+
+	result, err := outcome.result, outcome.err
+
+	if err != nil && execCtx.Err() != nil && !errors.Is(err, ErrJobAbandoned) {
+		err = context.Cause(execCtx)
+	}
+
+	if errors.Is(err, domain.ErrJobLeaseLost) {
+		slog.WarnContext(ctx, "job lease lost, abandoning job", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID))
+
+		return
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "error executing job", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID), slog.Int64("attempt", job.Attempts+1), slog.Any("error", err))
+
+		s.recordFailure(job, err)
+	} else {
+		job.State = domain.JobStateDone
+		job.Result = result
+	}
+
+	// the outcome must be recorded even when the job system is stopping
+	err = s.transactioner.InTransaction(context.WithoutCancel(ctx), func(ctx context.Context) error {
+		return s.finishJob(ctx, job)
+	})
+
+	switch {
+	case errors.Is(err, domain.ErrJobLeaseLost):
+		slog.WarnContext(ctx, "job lease lost, discarding outcome", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID))
+	case err != nil:
+		slog.ErrorContext(ctx, "error updating job", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID), slog.Any("error", err))
+	}
+}
+
+type runOutcome struct {
+	result *domain.JobResult
+	err    error
+}
+
+// renewLease renews the lease of the running job until the context is done. The job is cancelled when its lease has
+// been lost, which means it has already been picked up by another instance.
+func (s *System) renewLease(ctx context.Context, cancel context.CancelCauseFunc, lease *domain.Job) {
+	ticker := time.NewTicker(s.config.LeaseDuration / leaseRenewals)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		lease.LeaseExpiresAt = s.now().Add(s.config.LeaseDuration)
+
+		ok, err := s.repo.RenewJobLease(ctx, lease)
+		if err != nil {
+			slog.ErrorContext(ctx, "error renewing job lease", slog.String("job_name", lease.Kind), slog.Int64("job_id", lease.ID), slog.Any("error", err))
+
+			continue
+		}
+
+		if !ok {
+			cancel(domain.ErrJobLeaseLost)
+
+			return
+		}
+	}
+}
+
+// finishJob persists the outcome of a run and schedules the next occurrence of a recurring job, unless the job
+// will be retried. A failed run of a recurring job whose next occurrence has already been scheduled, e.g. after a
+// restart, is neither retried nor dead-lettered, as only one run per name can be scheduled.
+func (s *System) finishJob(ctx context.Context, job *domain.Job) error {
+	superseded, err := s.isSuperseded(ctx, job)
 	if err != nil {
 		return err
 	}
 
-	for _, job := range jobs {
-		slog.InfoContext(ctx, "starting job", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID))
+	if superseded && (job.State == domain.JobStateScheduled || job.State == domain.JobStateDead) {
+		job.State = domain.JobStateError
+	}
+
+	err = s.repo.UpdateJob(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	if job.State == domain.JobStateScheduled || superseded {
+		return nil
+	}
+
+	err = s.scheduleNextRun(ctx, job)
+	if err != nil {
+		return fmt.Errorf("error rescheduling recurring job: %w", err)
+	}
 
-		result, err := s.execJob(ctx, job)
+	return nil
+}
+
+// isSuperseded reports whether another run of the recurring job has already been scheduled.
+func (s *System) isSuperseded(ctx context.Context, job *domain.Job) (bool, error) {
+	if job.Name == "" {
+		return false, nil
+	}
+
+	scheduled, err := s.repo.GetScheduledJobByName(ctx, job.Name)
+	if err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return scheduled.ID != job.ID, nil
+}
+
+func (s *System) timeout(kind string) time.Duration {
+	timeout, ok := s.timeouts[kind]
+	if !ok {
+		return s.config.Timeout
+	}
+
+	return timeout
+}
+
+// recordFailure either reschedules the job according to its kind's RetryPolicy or moves it to the dead-letter state.
+func (s *System) recordFailure(job *domain.Job, err error) {
+	job.Attempts++
+	job.Errors = append(job.Errors, domain.JobError{Attempt: job.Attempts, Message: err.Error(), FailedAt: s.now()})
+	job.Result = &domain.JobResult{Message: err.Error()}
+
+	policy, ok := s.retryPolicies[job.Kind]
+	if !ok {
+		policy = DefaultRetryPolicy
+	}
+
+	if job.Attempts >= policy.MaxAttempts || errors.Is(err, ErrUnknownJobKind) {
+		job.State = domain.JobStateDead
+
+		return
+	}
+
+	job.State = domain.JobStateScheduled
+	job.ScheduledFor = s.now().Add(policy.Backoff(job.Attempts))
+}
+
+// ListJobs lists jobs of all states, optionally filtered by kind and state. Only admins can inspect jobs.
+func (s *System) ListJobs(ctx context.Context, query domain.ListJobsQuery) (*domain.JobList, error) {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListJobs(ctx, query)
+}
+
+// GetJob returns a job including its data, result and error history. Only admins can inspect jobs.
+func (s *System) GetJob(ctx context.Context, id int64) (*domain.Job, error) {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetJob(ctx, id)
+}
+
+// Trigger schedules a job of a registered kind to run immediately.
+func (s *System) Trigger(ctx context.Context, kind string, data json.RawMessage) (*domain.Job, error) {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.jobKinds[kind]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJobKind, kind)
+	}
+
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+
+	job := &domain.Job{Kind: kind, Data: data}
+
+	err = s.Schedule(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetJob(ctx, job.ID)
+}
+
+// Cancel cancels a scheduled job. Cancelling a run of a recurring job stops the recurrence until the job system is
+// restarted, as the next run is only scheduled after a job has been executed.
+func (s *System) Cancel(ctx context.Context, id int64) (*domain.Job, error) {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var job *domain.Job
+
+	err = s.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		job, err = s.repo.GetJob(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if job.State != domain.JobStateScheduled {
+			return fmt.Errorf("%w: can't cancel %s job %d", ErrInvalidJobState, job.State, id)
+		}
+
+		err = s.repo.CancelScheduledJob(ctx, id, s.now())
+		if err != nil {
+			return err
+		}
+
+		job, err = s.repo.GetJob(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Retry schedules a dead or failed job to run immediately with reset attempts. A re-run of a recurring job is not
+// rescheduled, as the next occurrence has already been scheduled when the job died.
+func (s *System) Retry(ctx context.Context, id int64) (*domain.Job, error) {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var job *domain.Job
+
+	err = s.transactioner.InTransaction(ctx, func(ctx context.Context) error {
+		job, err = s.repo.GetJob(ctx, id)
 		if err != nil {
-			slog.ErrorContext(ctx, "error executing job", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID), slog.Any("error", err))
-			job.State = domain.JobStateError
-			job.Result = &domain.JobResult{Message: err.Error()}
-		} else {
-			job.Result = result
+			return err
+		}
+
+		if job.State != domain.JobStateDead && job.State != domain.JobStateError {
+			return fmt.Errorf("%w: can't retry %s job %d", ErrInvalidJobState, job.State, id)
 		}
 
-		err = s.repo.UpdateJob(ctx, job)
+		err = s.repo.RequeueFailedJob(ctx, id, s.now())
 		if err != nil {
-			slog.ErrorContext(ctx, "error updating job", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID), slog.Any("error", err))
+			return err
 		}
+
+		job, err = s.repo.GetJob(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.triggerWakeup()
+
+	return job, nil
+}
+
+func newInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "conveyor"
+	}
+
+	return hostname + "-" + rand.Text()[:8]
+}
+
+func requireAdmin(ctx context.Context) error {
+	account := auth.AccountFromCtx(ctx)
+	if account == nil {
+		return auth.ErrUnauthorized
+	}
+
+	if !account.IsAdmin {
+		return auth.ErrForbidden
 	}
 
 	return nil
 }
 
-func (s *System) execJob(ctx context.Context, job *domain.Job) (*domain.JobResult, error) {
+// execJob recovers panics, so that a single job can't take down the job system.
+func (s *System) execJob(ctx context.Context, job *domain.Job) (result *domain.JobResult, err error) {
 	kind, ok := s.jobKinds[job.Kind]
 	if !ok {
 		return nil, fmt.Errorf("%w: %s", ErrUnknownJobKind, job.Kind)
 	}
 
-	ctx = tracing.RequestIDWithCtx(ctx, tracing.NewRequestID())
+	defer func() {
+		if r := recover(); r != nil {
+			slog.ErrorContext(ctx, "job panicked", slog.String("job_name", job.Kind), slog.Int64("job_id", job.ID), slog.String("stack", string(debug.Stack())))
+			err = fmt.Errorf("%w: %v", ErrJobPanicked, r)
+		}
+	}()
 
 	ctx = auth.CtxWithAccount(ctx, &domain.Account{})
 
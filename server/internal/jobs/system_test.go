@@ -2,6 +2,10 @@ package jobs_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -203,14 +207,632 @@ func TestSystem_Scheduled_Wakeup(t *testing.T) {
 	assert.WithinRange(t, now, scheduledFor.Add(-time.Millisecond), scheduledFor.Add(time.Second*10))
 }
 
+func TestSystem_Recurring(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second*10)
+	t.Cleanup(cancel)
+
+	runs := make(chan struct{}, 2)
+
+	system := setupJobSystem(t, time.Now, map[string]jobs.JobKindWithJSONData{
+		t.Name(): jobs.NewJobKindWithJSONData(jobKindFunc[struct{}](func(_ context.Context, _ struct{}) (*domain.JobResult, error) {
+			runs <- struct{}{}
+
+			return &domain.JobResult{}, nil
+		})),
+	})
+	system.RegisterRecurring(jobs.RecurringJob{
+		Name:     t.Name(),
+		Kind:     t.Name(),
+		Data:     struct{}{},
+		Schedule: jobs.Every(time.Second),
+	})
+	go system.Start(ctx)
+
+	// the job must be rescheduled after the first run
+	for range 2 {
+		select {
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		case <-runs:
+		}
+	}
+}
+
+func TestSystem_Schedule_Named(t *testing.T) {
+	t.Parallel()
+
+	ctx := auth.CtxWithAccount(t.Context(), &domain.Account{ID: 1})
+
+	db := testhelper.NewFileTestSQLite(t)
+	jobRepo := sqlite.NewJobRepo(db)
+	system := jobs.NewSystem(jobs.SystemConfig{}, db, jobRepo, control.NewAccountController(db, sqlite.NewAccountRepo(db), nil), time.Now, nil)
+
+	for _, schedule := range []string{"@every 1h", "0 3 * * *"} {
+		err := system.Schedule(ctx, &domain.Job{
+			Name:     t.Name(),
+			Kind:     t.Name(),
+			Schedule: schedule,
+		})
+		require.NoError(t, err)
+	}
+
+	job, err := jobRepo.GetScheduledJobByName(ctx, t.Name())
+	require.NoError(t, err)
+	assert.Equal(t, "0 3 * * *", job.Schedule)
+
+	next, err := jobRepo.ListNextJobs(ctx, time.Now().Add(time.Hour*25))
+	require.NoError(t, err)
+	assert.Len(t, next, 1)
+
+	err = system.Schedule(ctx, &domain.Job{Kind: t.Name(), Schedule: "0 0 30 2 *"})
+	require.ErrorIs(t, err, jobs.ErrInvalidSchedule)
+}
+
+func TestSystem_Retry(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(auth.CtxWithAccount(t.Context(), &domain.Account{ID: 1, IsAdmin: true}), time.Second*10)
+	t.Cleanup(cancel)
+
+	attempts := make(chan int64, 10)
+	failUntil := int64(2)
+
+	var count atomic.Int64
+
+	system := setupJobSystem(t, time.Now, map[string]jobs.JobKindWithJSONData{
+		t.Name(): jobs.NewJobKindWithJSONData(jobKindFunc[struct{}](func(_ context.Context, _ struct{}) (*domain.JobResult, error) {
+			attempt := count.Add(1)
+			attempts <- attempt
+
+			if attempt <= failUntil {
+				return nil, errors.New("transient error") //nolint:err113
+			}
+
+			return &domain.JobResult{}, nil
+		})),
+	})
+	system.RegisterRetryPolicy(t.Name(), jobs.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+	go system.Start(ctx)
+
+	err := system.Schedule(ctx, &domain.Job{Kind: t.Name(), Data: struct{}{}})
+	require.NoError(t, err)
+
+	for range 2 {
+		select {
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		case <-attempts:
+		}
+	}
+
+	var dead *domain.JobList
+
+	require.Eventually(t, func() bool {
+		dead, err = system.ListJobs(ctx, domain.ListJobsQuery{State: domain.JobStateDead})
+		require.NoError(t, err)
+
+		return len(dead.Items) == 1
+	}, time.Second*5, time.Millisecond*50)
+
+	assert.Equal(t, domain.JobStateDead, dead.Items[0].State)
+	assert.Equal(t, int64(2), dead.Items[0].Attempts)
+	assert.Len(t, dead.Items[0].Errors, 2)
+
+	requeued, err := system.Retry(ctx, dead.Items[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStateScheduled, requeued.State)
+	assert.Equal(t, int64(0), requeued.Attempts)
+	assert.Len(t, requeued.Errors, 2)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	case attempt := <-attempts:
+		assert.Equal(t, int64(3), attempt)
+	}
+
+	_, err = system.Retry(ctx, dead.Items[0].ID)
+	require.ErrorIs(t, err, jobs.ErrInvalidJobState)
+
+	_, err = system.Retry(ctx, 1000)
+	require.ErrorIs(t, err, domain.ErrJobNotFound)
+
+	_, err = system.ListJobs(auth.CtxWithAccount(ctx, &domain.Account{ID: 2}), domain.ListJobsQuery{})
+	require.ErrorIs(t, err, auth.ErrForbidden)
+}
+
+func TestSystem_Isolation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(auth.CtxWithAccount(t.Context(), &domain.Account{ID: 1, IsAdmin: true}), time.Second*10)
+	t.Cleanup(cancel)
+
+	var hangReturned atomic.Bool
+
+	done := make(chan string, 1)
+
+	system := setupJobSystem(t, time.Now, map[string]jobs.JobKindWithJSONData{
+		"panic": jobs.NewJobKindWithJSONData(jobKindFunc[struct{}](func(_ context.Context, _ struct{}) (*domain.JobResult, error) {
+			panic("boom")
+		})),
+		"hang": jobs.NewJobKindWithJSONData(jobKindFunc[struct{}](func(ctx context.Context, _ struct{}) (*domain.JobResult, error) {
+			<-ctx.Done()
+
+			// the failure must only be recorded once the cancelled run has returned
+			time.Sleep(time.Millisecond * 100)
+			hangReturned.Store(true)
+
+			return nil, ctx.Err()
+		})),
+		"ok": jobs.NewJobKindWithJSONData(jobKindFunc[struct{}](func(_ context.Context, _ struct{}) (*domain.JobResult, error) {
+			done <- "ok"
+
+			return &domain.JobResult{}, nil
+		})),
+	})
+	system.RegisterRetryPolicy("panic", jobs.RetryPolicy{MaxAttempts: 1})
+	system.RegisterRetryPolicy("hang", jobs.RetryPolicy{MaxAttempts: 1})
+	system.RegisterTimeout("hang", time.Millisecond*200)
+	go system.Start(ctx)
+
+	for _, kind := range []string{"panic", "hang", "ok"} {
+		err := system.Schedule(ctx, &domain.Job{Kind: kind, Data: struct{}{}})
+		require.NoError(t, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	case <-done:
+	}
+
+	var dead *domain.JobList
+
+	require.Eventually(t, func() bool {
+		var err error
+
+		dead, err = system.ListJobs(ctx, domain.ListJobsQuery{State: domain.JobStateDead})
+		require.NoError(t, err)
+
+		return len(dead.Items) == 2
+	}, time.Second*5, time.Millisecond*50)
+
+	failures := map[string]string{}
+	for _, job := range dead.Items {
+		failures[job.Kind] = job.Errors[0].Message
+	}
+
+	assert.Contains(t, failures["panic"], jobs.ErrJobPanicked.Error())
+	assert.Contains(t, failures["hang"], jobs.ErrJobTimeout.Error())
+	assert.True(t, hangReturned.Load())
+}
+
+func TestSystem_AbandonHungJob(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(auth.CtxWithAccount(t.Context(), &domain.Account{ID: 1, IsAdmin: true}), time.Second*10)
+	t.Cleanup(cancel)
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	done := make(chan string, 1)
+
+	db := setupJobSystemDB(t)
+	system := jobs.NewSystem(jobs.SystemConfig{Workers: 1, CancelGracePeriod: time.Millisecond * 200}, db, sqlite.NewJobRepo(db), control.NewAccountController(db, sqlite.NewAccountRepo(db), nil), time.Now, map[string]jobs.JobKindWithJSONData{
+		"stuck": jobs.NewJobKindWithJSONData(jobKindFunc[struct{}](func(_ context.Context, _ struct{}) (*domain.JobResult, error) {
+			// ignores its context
+			<-release
+
+			return &domain.JobResult{}, nil
+		})),
+		"ok": jobs.NewJobKindWithJSONData(jobKindFunc[struct{}](func(_ context.Context, _ struct{}) (*domain.JobResult, error) {
+			done <- "ok"
+
+			return &domain.JobResult{}, nil
+		})),
+	})
+	system.RegisterRetryPolicy("stuck", jobs.RetryPolicy{MaxAttempts: 1})
+	system.RegisterTimeout("stuck", time.Millisecond*200)
+	go system.Start(ctx)
+
+	err := system.Schedule(ctx, &domain.Job{Kind: "stuck", Data: struct{}{}})
+	require.NoError(t, err)
+
+	err = system.Schedule(ctx, &domain.Job{Kind: "ok", Data: struct{}{}, ScheduledFor: time.Now().Add(time.Millisecond * 100)})
+	require.NoError(t, err)
+
+	// the only worker is freed once the stuck job has been abandoned
+	select {
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	case <-done:
+	}
+
+	var dead *domain.JobList
+
+	require.Eventually(t, func() bool {
+		dead, err = system.ListJobs(ctx, domain.ListJobsQuery{State: domain.JobStateDead})
+		require.NoError(t, err)
+
+		return len(dead.Items) == 1
+	}, time.Second*5, time.Millisecond*50)
+
+	assert.Equal(t, "stuck", dead.Items[0].Kind)
+	assert.Contains(t, dead.Items[0].Errors[0].Message, jobs.ErrJobTimeout.Error())
+	assert.Contains(t, dead.Items[0].Errors[0].Message, jobs.ErrJobAbandoned.Error())
+}
+
+func TestSystem_MultipleInstances(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(auth.CtxWithAccount(t.Context(), &domain.Account{ID: 1}), time.Second*10)
+	t.Cleanup(cancel)
+
+	db := setupJobSystemDB(t)
+
+	numJobs := 20
+
+	var mu sync.Mutex
+
+	executions := map[int]int{}
+	done := make(chan struct{}, numJobs*2)
+
+	jobFuncs := map[string]jobs.JobKindWithJSONData{
+		t.Name(): jobs.NewJobKindWithJSONData(jobKindFunc[int](func(_ context.Context, i int) (*domain.JobResult, error) {
+			mu.Lock()
+			executions[i]++
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond * 10)
+
+			done <- struct{}{}
+
+			return &domain.JobResult{}, nil
+		})),
+	}
+
+	instances := make([]*jobs.System, 2)
+	for i := range instances {
+		instances[i] = jobs.NewSystem(jobs.SystemConfig{InstanceID: fmt.Sprintf("instance-%d", i)}, db, sqlite.NewJobRepo(db), control.NewAccountController(db, sqlite.NewAccountRepo(db), nil), time.Now, jobFuncs)
+	}
+
+	for i := range numJobs {
+		err := instances[i%2].Schedule(ctx, &domain.Job{Kind: t.Name(), Data: i})
+		require.NoError(t, err)
+	}
+
+	for _, instance := range instances {
+		go instance.Start(ctx)
+	}
+
+	for range numJobs {
+		select {
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		case <-done:
+		}
+	}
+
+	// give a duplicate execution the chance to show up
+	time.Sleep(time.Millisecond * 100)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Len(t, executions, numJobs)
+
+	for i, count := range executions {
+		assert.Equal(t, 1, count, "job %d", i)
+	}
+}
+
+func TestSystem_LeaseRenewal(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(auth.CtxWithAccount(t.Context(), &domain.Account{ID: 1, IsAdmin: true}), time.Second*10)
+	t.Cleanup(cancel)
+
+	db := setupJobSystemDB(t)
+
+	var executions atomic.Int64
+
+	jobFuncs := map[string]jobs.JobKindWithJSONData{
+		t.Name(): jobs.NewJobKindWithJSONData(jobKindFunc[struct{}](func(_ context.Context, _ struct{}) (*domain.JobResult, error) {
+			executions.Add(1)
+
+			// outlasts the initial lease multiple times
+			time.Sleep(time.Second)
+
+			return &domain.JobResult{}, nil
+		})),
+	}
+
+	instances := make([]*jobs.System, 2)
+	for i := range instances {
+		instances[i] = jobs.NewSystem(jobs.SystemConfig{InstanceID: fmt.Sprintf("instance-%d", i), LeaseDuration: time.Millisecond * 300}, db, sqlite.NewJobRepo(db), control.NewAccountController(db, sqlite.NewAccountRepo(db), nil), time.Now, jobFuncs)
+		go instances[i].Start(ctx)
+	}
+
+	err := instances[0].Schedule(ctx, &domain.Job{Kind: t.Name(), Data: struct{}{}})
+	require.NoError(t, err)
+
+	var job *domain.Job
+
+	require.Eventually(t, func() bool {
+		list, err := instances[1].ListJobs(ctx, domain.ListJobsQuery{Kind: t.Name()})
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+
+		job = list.Items[0]
+
+		return job.State == domain.JobStateDone
+	}, time.Second*5, time.Millisecond*50)
+
+	assert.Equal(t, int64(1), executions.Load())
+	assert.Empty(t, job.Errors)
+}
+
+func TestSystem_LeaseExpired(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(auth.CtxWithAccount(t.Context(), &domain.Account{ID: 1, IsAdmin: true}), time.Second*10)
+	t.Cleanup(cancel)
+
+	db := setupJobSystemDB(t)
+	repo := sqlite.NewJobRepo(db)
+
+	done := make(chan struct{}, 1)
+
+	system := jobs.NewSystem(jobs.SystemConfig{InstanceID: "instance-b"}, db, repo, control.NewAccountController(db, sqlite.NewAccountRepo(db), nil), time.Now, map[string]jobs.JobKindWithJSONData{
+		t.Name(): jobs.NewJobKindWithJSONData(jobKindFunc[struct{}](func(_ context.Context, _ struct{}) (*domain.JobResult, error) {
+			done <- struct{}{}
+
+			return &domain.JobResult{}, nil
+		})),
+	})
+	system.RegisterRetryPolicy(t.Name(), jobs.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	// simulates an instance that crashed while running the job
+	job := &domain.Job{Kind: t.Name(), Data: struct{}{}, ScheduledFor: time.Now()}
+	err := repo.CreateJob(ctx, job)
+	require.NoError(t, err)
+
+	job.LeaseOwner = "instance-a"
+	job.LeaseExpiresAt = time.Now().Add(time.Millisecond * 200)
+	claimed, err := repo.ClaimJob(ctx, job)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	go system.Start(ctx)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	case <-done:
+	}
+
+	require.Eventually(t, func() bool {
+		job, err = system.GetJob(ctx, job.ID)
+		require.NoError(t, err)
+
+		return job.State == domain.JobStateDone
+	}, time.Second*5, time.Millisecond*50)
+
+	require.Len(t, job.Errors, 1)
+	assert.Equal(t, jobs.ErrLeaseExpired.Error(), job.Errors[0].Message)
+}
+
+func TestSystem_RecurringLeaseExpired(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		schedule jobs.Schedule
+		// state of the crashed run after its lease expired
+		state domain.JobState
+	}{
+		// the crashed run is retried and schedules the next occurrence itself
+		{name: "Same Schedule", schedule: jobs.Every(time.Hour), state: domain.JobStateDone},
+		// the next occurrence has been scheduled on start, so the crashed run is not retried
+		{name: "Changed Schedule", schedule: jobs.Every(time.Hour * 2), state: domain.JobStateError},
+	}
+
+	for _, tt := range tt {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(auth.CtxWithAccount(t.Context(), &domain.Account{ID: 1, IsAdmin: true}), time.Second*10)
+			t.Cleanup(cancel)
+
+			db := setupJobSystemDB(t)
+			repo := &countingJobRepo{JobRepo: sqlite.NewJobRepo(db)}
+
+			var executions atomic.Int64
+
+			system := jobs.NewSystem(jobs.SystemConfig{InstanceID: "instance-b"}, db, repo, control.NewAccountController(db, sqlite.NewAccountRepo(db), nil), time.Now, map[string]jobs.JobKindWithJSONData{
+				t.Name(): jobs.NewJobKindWithJSONData(jobKindFunc[struct{}](func(_ context.Context, _ struct{}) (*domain.JobResult, error) {
+					executions.Add(1)
+
+					return &domain.JobResult{}, nil
+				})),
+			})
+			system.RegisterRetryPolicy(t.Name(), jobs.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+			system.RegisterRecurring(jobs.RecurringJob{Name: t.Name(), Kind: t.Name(), Data: struct{}{}, Schedule: tt.schedule})
+
+			// simulates an instance that crashed while running the recurring job and is restarted
+			crashed := &domain.Job{Name: t.Name(), Kind: t.Name(), Data: struct{}{}, Schedule: jobs.Every(time.Hour).String(), ScheduledFor: time.Now()}
+			err := repo.CreateJob(ctx, crashed)
+			require.NoError(t, err)
+
+			crashed.LeaseOwner = "instance-a"
+			crashed.LeaseExpiresAt = time.Now().Add(time.Millisecond * 200)
+			claimed, err := repo.ClaimJob(ctx, crashed)
+			require.NoError(t, err)
+			require.True(t, claimed)
+
+			go system.Start(ctx)
+
+			require.Eventually(t, func() bool {
+				crashed, err = system.GetJob(ctx, crashed.ID)
+				require.NoError(t, err)
+
+				return crashed.State == tt.state
+			}, time.Second*5, time.Millisecond*50)
+
+			require.Len(t, crashed.Errors, 1)
+			assert.Equal(t, jobs.ErrLeaseExpired.Error(), crashed.Errors[0].Message)
+
+			next, err := repo.GetScheduledJobByName(ctx, t.Name())
+			require.NoError(t, err)
+			assert.Equal(t, tt.schedule.String(), next.Schedule)
+
+			// the expired lease must not cause a busy loop
+			queries := repo.expiredLeaseQueries.Load()
+			time.Sleep(time.Second)
+			assert.Less(t, repo.expiredLeaseQueries.Load()-queries, int64(20))
+
+			if tt.state == domain.JobStateDone {
+				assert.Equal(t, int64(1), executions.Load())
+			} else {
+				assert.Zero(t, executions.Load())
+			}
+		})
+	}
+}
+
+func TestSystem_Administration(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(auth.CtxWithAccount(t.Context(), &domain.Account{ID: 1, IsAdmin: true}), time.Second*10)
+	t.Cleanup(cancel)
+
+	type jobData struct{ Foo string }
+
+	jobChan := make(chan jobData, 1)
+
+	system := setupJobSystem(t, time.Now, map[string]jobs.JobKindWithJSONData{
+		t.Name(): jobs.NewJobKindWithJSONData(jobKindFunc[jobData](func(_ context.Context, data jobData) (*domain.JobResult, error) {
+			jobChan <- data
+
+			return &domain.JobResult{Message: "Done " + data.Foo}, nil
+		})),
+	})
+	go system.Start(ctx)
+
+	_, err := system.Trigger(ctx, "unknown", nil)
+	require.ErrorIs(t, err, jobs.ErrUnknownJobKind)
+
+	_, err = system.Trigger(auth.CtxWithAccount(ctx, &domain.Account{ID: 2}), t.Name(), nil)
+	require.ErrorIs(t, err, auth.ErrForbidden)
+
+	triggered, err := system.Trigger(ctx, t.Name(), []byte(`{"Foo":"Bar"}`))
+	require.NoError(t, err)
+	assert.Equal(t, t.Name(), triggered.Kind)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	case data := <-jobChan:
+		assert.Equal(t, "Bar", data.Foo)
+	}
+
+	require.Eventually(t, func() bool {
+		job, err := system.GetJob(ctx, triggered.ID)
+		require.NoError(t, err)
+
+		return job.State == domain.JobStateDone && job.Result.Message == "Done Bar"
+	}, time.Second*5, time.Millisecond*50)
+
+	err = system.Schedule(ctx, &domain.Job{Kind: t.Name(), Data: jobData{}, ScheduledFor: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	scheduled, err := system.ListJobs(ctx, domain.ListJobsQuery{Kind: t.Name(), State: domain.JobStateScheduled})
+	require.NoError(t, err)
+	require.Len(t, scheduled.Items, 1)
+
+	cancelled, err := system.Cancel(ctx, scheduled.Items[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStateCancelled, cancelled.State)
+
+	_, err = system.Cancel(ctx, scheduled.Items[0].ID)
+	require.ErrorIs(t, err, jobs.ErrInvalidJobState)
+
+	_, err = system.Retry(ctx, triggered.ID)
+	require.ErrorIs(t, err, jobs.ErrInvalidJobState)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	policy := jobs.RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Second, MaxBackoff: time.Minute}
+
+	assert.Equal(t, time.Second, policy.Backoff(1))
+	assert.Equal(t, time.Second*2, policy.Backoff(2))
+	assert.Equal(t, time.Second*16, policy.Backoff(5))
+	assert.Equal(t, time.Minute, policy.Backoff(9))
+
+	policy.Jitter = 0.5
+	assert.InDelta(t, time.Second*4, policy.Backoff(3), float64(time.Second*2))
+}
+
+func TestParseSchedule(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 5, 31, 1, 10, 30, 0, time.UTC) // saturday
+
+	tt := []struct {
+		spec string
+		next time.Time
+	}{
+		{spec: "@every 1h30m0s", next: now.Add(time.Minute * 90)},
+		{spec: "@hourly", next: time.Date(2025, 5, 31, 2, 0, 0, 0, time.UTC)},
+		{spec: "@daily", next: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@monthly", next: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", next: time.Date(2025, 5, 31, 1, 15, 0, 0, time.UTC)},
+		{spec: "30 3 * * *", next: time.Date(2025, 5, 31, 3, 30, 0, 0, time.UTC)},
+		{spec: "0 9-17/4 * * 1-5", next: time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * 7", next: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 15 * 6", next: time.Date(2025, 6, 7, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", next: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 30 2 *", next: time.Time{}},
+	}
+
+	for _, tt := range tt {
+		t.Run(tt.spec, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := jobs.ParseSchedule(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.next, schedule.Next(now))
+			assert.Equal(t, tt.spec, schedule.String())
+		})
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "5-1 * * * *", "*/0 * * * *", "@every -1m", "@every x"} {
+		_, err := jobs.ParseSchedule(spec)
+		require.ErrorIs(t, err, jobs.ErrInvalidSchedule, spec)
+	}
+}
+
 func setupJobSystem(t *testing.T, timeNow jobs.SystemTimeNowFunc, jobFuncs map[string]jobs.JobKindWithJSONData) *jobs.System {
 	t.Helper()
 
+	db := setupJobSystemDB(t)
+
+	return jobs.NewSystem(jobs.SystemConfig{}, db, sqlite.NewJobRepo(db), control.NewAccountController(db, sqlite.NewAccountRepo(db), nil), timeNow, jobFuncs)
+}
+
+func setupJobSystemDB(t *testing.T) *sqlite.SQLite {
+	t.Helper()
+
 	db := testhelper.NewFileTestSQLite(t)
 
 	accountRepo := sqlite.NewAccountRepo(db)
-	jobRepo := sqlite.NewJobRepo(db)
 	authTokenRepo := sqlite.NewAuthTokenRepo(db)
+	totpRepo := sqlite.NewTOTPRepo(db)
 
 	config := control.AuthConfig{
 		Argon2Params:              auth.Argon2Params{KeyLen: 32, Memory: 8192, Threads: 2, Time: 1},
@@ -219,7 +841,8 @@ func setupJobSystem(t *testing.T, timeNow jobs.SystemTimeNowFunc, jobFuncs map[s
 		RefreshTokenValidDuration: time.Hour * 2,
 	}
 
-	authCtrl := control.NewAuthController(config, db, control.NewAccountController(db, accountRepo), authTokenRepo)
+	apiTokenUsage := control.NewAPITokenUsageTracker(control.APITokenUsageConfig{}, db, sqlite.NewAPITokenRepo(db))
+	authCtrl := control.NewAuthController(config, db, control.NewAccountController(db, accountRepo, nil), authTokenRepo, totpRepo, apiTokenUsage)
 
 	err := authCtrl.CreateAccount(t.Context(), control.CreateAccountCmd{
 		Account: &domain.Account{
@@ -231,7 +854,17 @@ func setupJobSystem(t *testing.T, timeNow jobs.SystemTimeNowFunc, jobFuncs map[s
 		t.Fatal(err)
 	}
 
-	return jobs.NewSystem(db, jobRepo, control.NewAccountController(db, accountRepo), timeNow, jobFuncs)
+	return db
+}
+
+type countingJobRepo struct {
+	*sqlite.JobRepo
+	expiredLeaseQueries atomic.Int64
+}
+
+func (r *countingJobRepo) ListJobsWithExpiredLease(ctx context.Context, now time.Time) ([]*domain.Job, error) {
+	r.expiredLeaseQueries.Add(1)
+	return r.JobRepo.ListJobsWithExpiredLease(ctx, now)
 }
 
 type jobKindFunc[T any] func(ctx context.Context, data T) (*domain.JobResult, error)
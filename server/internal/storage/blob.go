@@ -1,10 +1,35 @@
 package storage
 
 import (
+	"context"
 	"io"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/domain"
 )
 
 type BlobTarget interface {
 	io.WriteCloser
-	Finalize(filepath string) (err error)
+	Finalize(ctx context.Context, filepath string) (err error)
+}
+
+type BlobInfo struct {
+	SizeBytes int64
+	ModTime   time.Time
+	ETag      string
+}
+
+// BlobReader provides read access to stored blobs independent of the storage backend.
+// Missing blobs result in an [fs.ErrNotExist] error.
+type BlobReader interface {
+	StatBlob(ctx context.Context, accountID domain.AccountID, filepath string) (*BlobInfo, error)
+	OpenBlob(ctx context.Context, accountID domain.AccountID, filepath string) (io.ReadCloser, error)
+	// OpenBlobRange opens the blob for reading length bytes, starting at offset.
+	OpenBlobRange(ctx context.Context, accountID domain.AccountID, filepath string, offset int64, length int64) (io.ReadCloser, error)
+}
+
+// BlobURLPresigner can optionally be implemented by a [BlobReader] whose blobs can be downloaded directly,
+// without the need to proxy them.
+type BlobURLPresigner interface {
+	PresignGetURL(accountID domain.AccountID, filepath string) string
 }
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+)
+
+var ErrInvalidSeek = errors.New("invalid seek")
+
+// BlobReadSeeker adapts a [BlobReader] to an [io.ReadSeekCloser], e.g. for use with [http.ServeContent].
+// Only the part of the blob after the current offset is opened, when first read.
+// As [io.Reader] doesn't take a context, the context of the request the blob is read for is kept instead.
+type BlobReadSeeker struct {
+	ctx       context.Context //nolint:containedctx // Read doesn't take a context
+	blobs     BlobReader
+	accountID domain.AccountID
+	filepath  string
+	sizeBytes int64
+
+	offset int64
+	r      io.ReadCloser
+}
+
+func NewBlobReadSeeker(ctx context.Context, blobs BlobReader, accountID domain.AccountID, filepath string, sizeBytes int64) *BlobReadSeeker {
+	return &BlobReadSeeker{ctx: ctx, blobs: blobs, accountID: accountID, filepath: filepath, sizeBytes: sizeBytes}
+}
+
+func (brs *BlobReadSeeker) Read(p []byte) (int, error) {
+	if brs.offset >= brs.sizeBytes {
+		return 0, io.EOF
+	}
+
+	if brs.r == nil {
+		r, err := brs.blobs.OpenBlobRange(brs.ctx, brs.accountID, brs.filepath, brs.offset, brs.sizeBytes-brs.offset)
+		if err != nil {
+			return 0, err
+		}
+
+		brs.r = r
+	}
+
+	n, err := brs.r.Read(p)
+	brs.offset += int64(n)
+
+	return n, err
+}
+
+func (brs *BlobReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += brs.offset
+	case io.SeekEnd:
+		offset += brs.sizeBytes
+	default:
+		return 0, ErrInvalidSeek
+	}
+
+	if offset < 0 {
+		return 0, ErrInvalidSeek
+	}
+
+	if offset != brs.offset {
+		err := brs.Close()
+		if err != nil {
+			return 0, err
+		}
+
+		brs.offset = offset
+	}
+
+	return offset, nil
+}
+
+func (brs *BlobReadSeeker) Close() error {
+	if brs.r == nil {
+		return nil
+	}
+
+	err := brs.r.Close()
+	brs.r = nil
+
+	return err
+}
@@ -44,19 +44,7 @@ func (r *AccountRepo) Get(ctx context.Context, id domain.AccountID) (*domain.Acc
 		return nil, fmt.Errorf("error getting account by id: id %d: %w", id, err)
 	}
 
-	return &domain.Account{
-		ID:       account.ID,
-		Username: account.Username,
-		Password: domain.AccountPassword{
-			Algorithm:      account.Algorithm,
-			Params:         account.Params,
-			Salt:           account.Salt,
-			Password:       account.Password,
-			RequiresChange: account.RequiresPasswordChange,
-		},
-		CreatedAt: account.CreatedAt.Time,
-		UpdatedAt: account.UpdatedAt.Time,
-	}, nil
+	return mapAccountToDomain(account), nil
 }
 
 func (r *AccountRepo) GetByUsername(ctx context.Context, username string) (*domain.Account, error) {
@@ -69,19 +57,45 @@ func (r *AccountRepo) GetByUsername(ctx context.Context, username string) (*doma
 		return nil, fmt.Errorf("error getting account by username: %s: %w", username, err)
 	}
 
-	return &domain.Account{
-		ID:       account.ID,
-		Username: account.Username,
-		Password: domain.AccountPassword{
-			Algorithm:      account.Algorithm,
-			Params:         account.Params,
-			Salt:           account.Salt,
-			Password:       account.Password,
-			RequiresChange: account.RequiresPasswordChange,
-		},
-		CreatedAt: account.CreatedAt.Time,
-		UpdatedAt: account.UpdatedAt.Time,
-	}, nil
+	return mapAccountToDomain(account), nil
+}
+
+func (r *AccountRepo) List(ctx context.Context, query domain.ListAccountsQuery) (*domain.AccountList, error) {
+	pageAfter := domain.AccountID(0)
+	if query.PageAfter != nil {
+		pageAfter = *query.PageAfter
+	}
+
+	var pageSize int64
+	if query.PageSize >= maxPageSize || query.PageSize == 0 {
+		pageSize = maxPageSize
+	} else {
+		pageSize = int64(query.PageSize)
+	}
+
+	rows, err := queries.ListAccounts(ctx, r.db.Conn(ctx), sqlc.ListAccountsParams{
+		PageAfter: pageAfter,
+		PageSize:  pageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing accounts: %w", err)
+	}
+
+	list := &domain.AccountList{
+		Items: make([]*domain.Account, len(rows)),
+		Next:  nil,
+	}
+
+	for i, row := range rows {
+		list.Items[i] = mapAccountToDomain(row)
+	}
+
+	if int64(len(rows)) == pageSize {
+		next := rows[len(rows)-1].ID
+		list.Next = &next
+	}
+
+	return list, nil
 }
 
 func (r *AccountRepo) Create(ctx context.Context, toCreate *domain.Account) error {
@@ -91,6 +105,7 @@ func (r *AccountRepo) Create(ctx context.Context, toCreate *domain.Account) erro
 		Params:    toCreate.Password.Params,
 		Salt:      toCreate.Password.Salt,
 		Password:  toCreate.Password.Password,
+		IsAdmin:   toCreate.IsAdmin,
 	})
 }
 
@@ -106,6 +121,57 @@ func (r *AccountRepo) Update(ctx context.Context, toUpdate *domain.Account) erro
 	})
 }
 
+func (r *AccountRepo) Disable(ctx context.Context, id domain.AccountID, disabledAt time.Time) error {
+	return queries.DisableAccount(ctx, r.db.Conn(ctx), sqlc.DisableAccountParams{
+		ID:         id,
+		DisabledAt: types.NewSQLiteDatetime(disabledAt.UTC()),
+		UpdatedAt:  types.NewSQLiteDatetime(time.Now().UTC()),
+	})
+}
+
+func (r *AccountRepo) Enable(ctx context.Context, id domain.AccountID) error {
+	return queries.EnableAccount(ctx, r.db.Conn(ctx), sqlc.EnableAccountParams{
+		ID:        id,
+		UpdatedAt: types.NewSQLiteDatetime(time.Now().UTC()),
+	})
+}
+
+// Delete deletes the account and all data referencing it. It should be called in a transaction,
+// so the account is never left partially deleted.
+func (r *AccountRepo) Delete(ctx context.Context, id domain.AccountID) error {
+	conn := r.db.Conn(ctx)
+
+	deletes := []struct {
+		name string
+		fn   func(ctx context.Context, db sqlc.DBTX, accountID domain.AccountID) error
+	}{
+		{"api tokens", queries.DeleteAccountAPITokens},
+		{"auth tokens", queries.DeleteAccountAuthTokens},
+		{"account keys", func(ctx context.Context, db sqlc.DBTX, accountID domain.AccountID) error {
+			return queries.DeleteAccountKeys(ctx, db, int64(accountID))
+		}},
+		{"recovery codes", queries.DeleteAccountRecoveryCodes},
+		{"totp", queries.DeleteAccountTOTP},
+		{"changelog entries", queries.DeleteAccountChangelogEntries},
+		{"sync clients", queries.DeleteAccountSyncClients},
+		{"full sync entries", queries.DeleteAccountFullSyncEntries},
+		{"attachments", queries.DeleteAccountAttachments},
+		{"uploads", queries.DeleteAccountUploads},
+		{"usage", queries.DeleteAccountUsage},
+		{"quota override", queries.DeleteAccountQuotaOverride},
+		{"account", queries.DeleteAccount},
+	}
+
+	for _, d := range deletes {
+		err := d.fn(ctx, conn, id)
+		if err != nil {
+			return fmt.Errorf("error deleting %s of account %d: %w", d.name, id, err)
+		}
+	}
+
+	return nil
+}
+
 func (r *AccountRepo) CreateAccountKey(ctx context.Context, toCreate *domain.AccountKey) error {
 	return queries.CreateAccountKey(ctx, r.db.Conn(ctx), sqlc.CreateAccountKeyParams{
 		AccountID: int64(toCreate.AccountID),
@@ -136,3 +202,21 @@ func (r *AccountRepo) GetAccountKeyByName(ctx context.Context, accountID domain.
 		Data:      row.Data,
 	}, nil
 }
+
+func mapAccountToDomain(row sqlc.Account) *domain.Account {
+	return &domain.Account{
+		ID:       row.ID,
+		Username: row.Username,
+		Password: domain.AccountPassword{
+			Algorithm:      row.Algorithm,
+			Params:         row.Params,
+			Salt:           row.Salt,
+			Password:       row.Password,
+			RequiresChange: row.RequiresPasswordChange,
+		},
+		IsAdmin:    row.IsAdmin,
+		DisabledAt: row.DisabledAt.Time,
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+	}
+}
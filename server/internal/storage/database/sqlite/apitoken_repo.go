@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"go.robinthrift.com/conveyor/internal/domain"
 	"go.robinthrift.com/conveyor/internal/storage/database"
@@ -33,14 +35,7 @@ func (r *APITokenRepo) GetAPITokenByName(ctx context.Context, accountID domain.A
 		return nil, err
 	}
 
-	return &domain.APIToken{
-		ID:        row.ID,
-		TokenID:   row.TokenID,
-		AccountID: row.AccountID,
-		Name:      row.Name,
-		CreatedAt: row.CreatedAt.Time,
-		ExpiresAt: row.ExpiresAt.Time,
-	}, nil
+	return mapAPITokenToDomain(row)
 }
 
 const maxPageSize = 50
@@ -73,13 +68,9 @@ func (r *APITokenRepo) ListAPITokens(ctx context.Context, accountID domain.Accou
 	}
 
 	for i, row := range rows {
-		list.Items[i] = &domain.APIToken{
-			ID:        row.ID,
-			TokenID:   row.TokenID,
-			AccountID: row.AccountID,
-			Name:      row.Name,
-			CreatedAt: row.CreatedAt.Time,
-			ExpiresAt: row.ExpiresAt.Time,
+		list.Items[i], err = mapAPITokenToDomain(row)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -96,6 +87,7 @@ func (r *APITokenRepo) CreateAPIToken(ctx context.Context, token *domain.APIToke
 		TokenID:   token.TokenID,
 		AccountID: token.AccountID,
 		Name:      token.Name,
+		Scopes:    token.Scopes.String(),
 		ExpiresAt: types.NewSQLiteDatetime(token.ExpiresAt),
 	})
 	if err != nil {
@@ -125,3 +117,46 @@ func (r *APITokenRepo) DeleteAPITokenByName(ctx context.Context, accountID domai
 
 	return nil
 }
+
+func (r *APITokenRepo) RecordAPITokenUsage(ctx context.Context, usage *domain.APITokenUsage) error {
+	return queries.RecordAPITokenUsage(ctx, r.db.Conn(ctx), sqlc.RecordAPITokenUsageParams{
+		ID:                usage.APITokenID,
+		LastUsedAt:        types.NewSQLiteDatetime(usage.LastUsedAt),
+		LastUsedIp:        usage.IP,
+		LastUsedUserAgent: usage.UserAgent,
+		RequestCount:      usage.RequestCount,
+	})
+}
+
+// DeleteUnusedAPITokens invalidates and deletes all API tokens that haven't been used since the given time. Tokens that
+// have never been used are compared by their creation time.
+func (r *APITokenRepo) DeleteUnusedAPITokens(ctx context.Context, unusedSince time.Time) (int64, error) {
+	err := queries.InvalidateUnusedAPITokenAuthTokens(ctx, r.db.Conn(ctx), types.NewSQLiteDatetime(unusedSince))
+	if err != nil {
+		return 0, err
+	}
+
+	return queries.DeleteUnusedAPITokens(ctx, r.db.Conn(ctx), types.NewSQLiteDatetime(unusedSince))
+}
+
+func mapAPITokenToDomain(row sqlc.ApiToken) (*domain.APIToken, error) {
+	scopes, err := domain.ParseAPITokenScopes(row.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing scopes of api token %d: %w", row.ID, err)
+	}
+
+	return &domain.APIToken{
+		ID:        row.ID,
+		TokenID:   row.TokenID,
+		AccountID: row.AccountID,
+		Name:      row.Name,
+		Scopes:    scopes,
+		CreatedAt: row.CreatedAt.Time,
+		ExpiresAt: row.ExpiresAt.Time,
+
+		LastUsedAt:        row.LastUsedAt.Time,
+		LastUsedIP:        row.LastUsedIp,
+		LastUsedUserAgent: row.LastUsedUserAgent,
+		RequestCount:      row.RequestCount,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite/sqlc"
+)
+
+type AttachmentRepo struct {
+	db database.Database
+}
+
+func NewAttachmentRepo(db database.Database) *AttachmentRepo {
+	return &AttachmentRepo{db}
+}
+
+func (r *AttachmentRepo) GetAttachmentBySha256(ctx context.Context, accountID domain.AccountID, sha256 []byte) (*domain.Attachment, error) {
+	row, err := queries.GetAttachmentBySha256(ctx, r.db.Conn(ctx), sqlc.GetAttachmentBySha256Params{
+		AccountID: accountID,
+		Sha256:    sha256,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrAttachmentNotFound
+		}
+
+		return nil, err
+	}
+
+	return &domain.Attachment{
+		ID:        row.PublicID,
+		AccountID: row.AccountID,
+		Sha256:    row.Sha256,
+		Filepath:  row.Filepath,
+		SizeBytes: row.SizeBytes,
+		CreatedAt: row.CreatedAt.Time,
+	}, nil
+}
+
+func (r *AttachmentRepo) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	err := queries.CreateAttachment(ctx, r.db.Conn(ctx), sqlc.CreateAttachmentParams{
+		PublicID:  attachment.ID,
+		AccountID: attachment.AccountID,
+		Sha256:    attachment.Sha256,
+		Filepath:  attachment.Filepath,
+		SizeBytes: attachment.SizeBytes,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating attachment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AttachmentRepo) DeleteAttachmentsByFilepath(ctx context.Context, accountID domain.AccountID, filepath string) error {
+	err := queries.DeleteAttachmentsByFilepath(ctx, r.db.Conn(ctx), sqlc.DeleteAttachmentsByFilepathParams{
+		AccountID: accountID,
+		Filepath:  filepath,
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting attachments: %w", err)
+	}
+
+	return nil
+}
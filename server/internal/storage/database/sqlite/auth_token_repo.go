@@ -107,6 +107,21 @@ func (r *AuthTokenRepo) CreateAuthToken(ctx context.Context, token *auth.AuthTok
 	return id, nil
 }
 
+// GetAPITokenForAuthToken returns the API token the auth token belongs to, or [domain.ErrAPITokenNotFound] if it's
+// a session token.
+func (r *AuthTokenRepo) GetAPITokenForAuthToken(ctx context.Context, id auth.AuthTokenID) (*domain.APIToken, error) {
+	row, err := queries.GetAPITokenByTokenID(ctx, r.db.Conn(ctx), int64(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = domain.ErrAPITokenNotFound
+		}
+
+		return nil, err
+	}
+
+	return mapAPITokenToDomain(row)
+}
+
 func (r *AuthTokenRepo) InvalidateAuthToken(ctx context.Context, value auth.AuthTokenValue) error {
 	err := queries.InvalidateAuthToken(ctx, r.db.Conn(ctx), value)
 	if err != nil {
@@ -120,6 +135,11 @@ func (r *AuthTokenRepo) InvalidateAuthToken(ctx context.Context, value auth.Auth
 	return nil
 }
 
+// InvalidateAccountSessionTokens invalidates all auth tokens of the account, except those belonging to API tokens.
+func (r *AuthTokenRepo) InvalidateAccountSessionTokens(ctx context.Context, accountID domain.AccountID) error {
+	return queries.InvalidateAccountSessionTokens(ctx, r.db.Conn(ctx), accountID)
+}
+
 func (r *AuthTokenRepo) MarkExpiredAuthTokensAsInvalid(ctx context.Context) error {
 	err := queries.MarkExpiredAuthTokensAsInvalid(ctx, r.db.Conn(ctx))
 	if err != nil {
@@ -134,7 +154,13 @@ func (r *AuthTokenRepo) MarkExpiredAuthTokensAsInvalid(ctx context.Context) erro
 }
 
 func (r *AuthTokenRepo) DeleteInvalidTokens(ctx context.Context) error {
-	err := queries.DeleteInvalidTokens(ctx, r.db.Conn(ctx))
+	// api tokens reference their auth token, so they have to be deleted first
+	err := queries.DeleteAPITokensWithInvalidAuthTokens(ctx, r.db.Conn(ctx))
+	if err != nil {
+		return err
+	}
+
+	err = queries.DeleteInvalidTokens(ctx, r.db.Conn(ctx))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil
@@ -21,8 +21,10 @@ func NewJobRepo(db database.Database) *JobRepo {
 	return &JobRepo{db}
 }
 
-func (r *JobRepo) GetNextWakeUpTime(ctx context.Context) (time.Time, error) {
-	wakeup, err := queries.GetNextWakeUpTime(ctx, r.db.Conn(ctx))
+// GetNextWakeUpTime returns the earliest time a job is due or a lease expires. Leases that have already expired are
+// ignored, so that a job that can't be failed doesn't cause a busy loop.
+func (r *JobRepo) GetNextWakeUpTime(ctx context.Context, now time.Time) (time.Time, error) {
+	wakeup, err := queries.GetNextWakeUpTime(ctx, r.db.Conn(ctx), types.NewSQLiteDatetime(now).String())
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return time.Time{}, nil
@@ -34,6 +36,156 @@ func (r *JobRepo) GetNextWakeUpTime(ctx context.Context) (time.Time, error) {
 	return wakeup.Time, nil
 }
 
+func (r *JobRepo) GetScheduledJobByName(ctx context.Context, name string) (*domain.Job, error) {
+	res, err := queries.GetScheduledJobByName(ctx, r.db.Conn(ctx), name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("error getting scheduled job by name: %w", err)
+	}
+
+	return mapJobToDomain(res)
+}
+
+// GetRunningJobByName returns the latest running job with the name.
+func (r *JobRepo) GetRunningJobByName(ctx context.Context, name string) (*domain.Job, error) {
+	res, err := queries.GetRunningJobByName(ctx, r.db.Conn(ctx), name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("error getting running job by name: %w", err)
+	}
+
+	return mapJobToDomain(res)
+}
+
+func (r *JobRepo) GetJob(ctx context.Context, id int64) (*domain.Job, error) {
+	res, err := queries.GetJob(ctx, r.db.Conn(ctx), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("error getting job: %w", err)
+	}
+
+	return mapJobToDomain(res)
+}
+
+func (r *JobRepo) ListJobs(ctx context.Context, query domain.ListJobsQuery) (*domain.JobList, error) {
+	var pageAfter int64
+	if query.PageAfter != nil {
+		pageAfter = *query.PageAfter
+	}
+
+	var pageSize int64
+	if query.PageSize >= maxPageSize || query.PageSize == 0 {
+		pageSize = maxPageSize
+	} else {
+		pageSize = int64(query.PageSize)
+	}
+
+	rows, err := queries.ListJobs(ctx, r.db.Conn(ctx), sqlc.ListJobsParams{
+		Kind:      query.Kind,
+		State:     string(query.State),
+		PageAfter: pageAfter,
+		PageSize:  pageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing jobs: %w", err)
+	}
+
+	list := &domain.JobList{
+		Items: make([]*domain.Job, len(rows)),
+		Next:  nil,
+	}
+
+	for i, row := range rows {
+		list.Items[i], err = mapJobToDomain(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if int64(len(rows)) == pageSize {
+		next := rows[len(rows)-1].ID
+		list.Next = &next
+	}
+
+	return list, nil
+}
+
+func (r *JobRepo) GetNextDueJob(ctx context.Context, now time.Time) (*domain.Job, error) {
+	res, err := queries.GetNextDueJob(ctx, r.db.Conn(ctx), types.NewSQLiteDatetime(now).String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("error getting next due job: %w", err)
+	}
+
+	return mapJobToDomain(res)
+}
+
+// ClaimJob marks a scheduled job as running and leases it to the job's LeaseOwner until the lease expires. Returns
+// false when the job has already been claimed.
+func (r *JobRepo) ClaimJob(ctx context.Context, job *domain.Job) (bool, error) {
+	claimed, err := queries.ClaimJob(ctx, r.db.Conn(ctx), sqlc.ClaimJobParams{
+		ID:             job.ID,
+		LeaseOwner:     job.LeaseOwner,
+		LeaseExpiresAt: types.NewSQLiteDatetime(job.LeaseExpiresAt),
+	})
+	if err != nil {
+		return false, fmt.Errorf("error claiming job: %w", err)
+	}
+
+	if claimed == 0 {
+		return false, nil
+	}
+
+	job.State = domain.JobStateRunning
+
+	return true, nil
+}
+
+// RenewJobLease extends the lease of a running job. Returns false when the job's LeaseOwner no longer holds the lease.
+func (r *JobRepo) RenewJobLease(ctx context.Context, job *domain.Job) (bool, error) {
+	renewed, err := queries.RenewJobLease(ctx, r.db.Conn(ctx), sqlc.RenewJobLeaseParams{
+		ID:             job.ID,
+		LeaseOwner:     job.LeaseOwner,
+		LeaseExpiresAt: types.NewSQLiteDatetime(job.LeaseExpiresAt),
+	})
+	if err != nil {
+		return false, fmt.Errorf("error renewing job lease: %w", err)
+	}
+
+	return renewed != 0, nil
+}
+
+func (r *JobRepo) ListJobsWithExpiredLease(ctx context.Context, now time.Time) ([]*domain.Job, error) {
+	res, err := queries.ListJobsWithExpiredLease(ctx, r.db.Conn(ctx), types.NewSQLiteDatetime(now).String())
+	if err != nil {
+		return nil, fmt.Errorf("error listing jobs with expired lease: %w", err)
+	}
+
+	list := make([]*domain.Job, 0, len(res))
+	for _, j := range res {
+		job, err := mapJobToDomain(j)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, job)
+	}
+
+	return list, nil
+}
+
 func (r *JobRepo) ListNextJobs(ctx context.Context, scheduledFor time.Time) ([]*domain.Job, error) {
 	res, err := queries.ListNextJobs(ctx, r.db.Conn(ctx), types.NewSQLiteDatetime(scheduledFor).String())
 	if err != nil {
@@ -42,41 +194,152 @@ func (r *JobRepo) ListNextJobs(ctx context.Context, scheduledFor time.Time) ([]*
 
 	list := make([]*domain.Job, 0, len(res))
 	for _, j := range res {
-		list = append(list, &domain.Job{ //nolint:forcetypeassert // @TODO: check why this is cast
-			ID:           j.ID,
-			State:        domain.JobState(j.State.(string)),
-			Kind:         j.Kind,
-			Data:         j.Data.Raw,
-			ScheduledFor: j.ScheduledFor.Time,
-		})
+		job, err := mapJobToDomain(j)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, job)
 	}
 
 	return list, nil
 }
 
 func (r *JobRepo) CreateJob(ctx context.Context, job *domain.Job) error {
-	err := queries.CreateJob(ctx, r.db.Conn(ctx), sqlc.CreateJobParams{
+	id, err := queries.CreateJob(ctx, r.db.Conn(ctx), sqlc.CreateJobParams{
+		Name:         job.Name,
 		Kind:         job.Kind,
 		Data:         types.NewSQLiteJSON(job.Data),
+		Schedule:     job.Schedule,
 		ScheduledFor: types.NewSQLiteDatetime(job.ScheduledFor),
 	})
 	if err != nil {
 		return fmt.Errorf("error creating job: %w", err)
 	}
 
+	job.ID = id
+
 	return nil
 }
 
+// UpdateJob updates the job and releases its lease. Returns domain.ErrJobLeaseLost when the job is leased to another
+// owner than the job's LeaseOwner, e.g. because its lease expired and the job has been picked up again.
 func (r *JobRepo) UpdateJob(ctx context.Context, job *domain.Job) error {
-	err := queries.UpdateJob(ctx, r.db.Conn(ctx), sqlc.UpdateJobParams{
-		ID:         job.ID,
-		State:      job.State,
-		Result:     types.NewSQLiteJSON(&job.Result),
-		FinishedAt: types.NewSQLiteDatetime(time.Now()),
+	var finishedAt time.Time
+	if job.State != domain.JobStateScheduled {
+		finishedAt = time.Now()
+	}
+
+	updated, err := queries.UpdateJob(ctx, r.db.Conn(ctx), sqlc.UpdateJobParams{
+		ID:           job.ID,
+		LeaseOwner:   job.LeaseOwner,
+		State:        job.State,
+		Result:       types.NewSQLiteJSON(&job.Result),
+		Attempts:     job.Attempts,
+		Errors:       types.NewSQLiteJSON(job.Errors),
+		ScheduledFor: types.NewSQLiteDatetime(job.ScheduledFor),
+		FinishedAt:   types.NewSQLiteDatetime(finishedAt),
 	})
 	if err != nil {
 		return fmt.Errorf("error updating job: %w", err)
 	}
 
+	if updated == 0 {
+		return domain.ErrJobLeaseLost
+	}
+
+	job.LeaseOwner = ""
+	job.LeaseExpiresAt = time.Time{}
+
+	return nil
+}
+
+func (r *JobRepo) UpdateScheduledJob(ctx context.Context, job *domain.Job) error {
+	err := queries.UpdateScheduledJob(ctx, r.db.Conn(ctx), sqlc.UpdateScheduledJobParams{
+		ID:           job.ID,
+		Data:         types.NewSQLiteJSON(job.Data),
+		Schedule:     job.Schedule,
+		ScheduledFor: types.NewSQLiteDatetime(job.ScheduledFor),
+	})
+	if err != nil {
+		return fmt.Errorf("error updating scheduled job: %w", err)
+	}
+
+	return nil
+}
+
+// RequeueFailedJob schedules a dead or failed job again with reset attempts, the error history is kept.
+func (r *JobRepo) RequeueFailedJob(ctx context.Context, id int64, scheduledFor time.Time) error {
+	updated, err := queries.RequeueFailedJob(ctx, r.db.Conn(ctx), sqlc.RequeueFailedJobParams{
+		ID:           id,
+		ScheduledFor: types.NewSQLiteDatetime(scheduledFor),
+	})
+	if err != nil {
+		return fmt.Errorf("error requeueing job: %w", err)
+	}
+
+	if updated == 0 {
+		return domain.ErrJobNotFound
+	}
+
+	return nil
+}
+
+// CancelScheduledJob cancels a job that hasn't been claimed by a worker yet.
+func (r *JobRepo) CancelScheduledJob(ctx context.Context, id int64, cancelledAt time.Time) error {
+	updated, err := queries.CancelScheduledJob(ctx, r.db.Conn(ctx), sqlc.CancelScheduledJobParams{
+		ID:         id,
+		FinishedAt: types.NewSQLiteDatetime(cancelledAt),
+	})
+	if err != nil {
+		return fmt.Errorf("error cancelling job: %w", err)
+	}
+
+	if updated == 0 {
+		return domain.ErrJobNotFound
+	}
+
 	return nil
 }
+
+func (r *JobRepo) DeleteScheduledJobByName(ctx context.Context, name string) error {
+	err := queries.DeleteScheduledJobByName(ctx, r.db.Conn(ctx), name)
+	if err != nil {
+		return fmt.Errorf("error deleting scheduled job: %w", err)
+	}
+
+	return nil
+}
+
+func mapJobToDomain(j sqlc.Job) (*domain.Job, error) {
+	job := &domain.Job{ //nolint:forcetypeassert // @TODO: check why this is cast
+		ID:             j.ID,
+		Name:           j.Name,
+		State:          domain.JobState(j.State.(string)),
+		Kind:           j.Kind,
+		Data:           j.Data.Raw,
+		Schedule:       j.Schedule,
+		ScheduledFor:   j.ScheduledFor.Time,
+		Attempts:       j.Attempts,
+		CreatedAt:      j.CreatedAt.Time,
+		FinishedAt:     j.FinishedAt.Time,
+		LeaseExpiresAt: j.LeaseExpiresAt.Time,
+		LeaseOwner:     j.LeaseOwner,
+	}
+
+	if j.Result.Raw != nil {
+		err := j.Result.Unmarshal(&job.Result)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling job result: %w", err)
+		}
+	}
+
+	if j.Errors.Raw != nil {
+		err := j.Errors.Unmarshal(&job.Errors)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling job errors: %w", err)
+		}
+	}
+
+	return job, nil
+}
@@ -91,9 +91,231 @@ func TestJobRepo_GetNextWakeUpTime(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	next, err := repo.GetNextWakeUpTime(ctx)
+	next, err := repo.GetNextWakeUpTime(ctx, now)
 	require.NoError(t, err)
-	assert.Equal(t, now.Add(time.Second*time.Duration(numJobs/2)), next)
+	assert.Equal(t, now.Add(-time.Second*time.Duration(numJobs/2-1)), next)
+}
+
+func TestJobRepo_ScheduledJobByName(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	repo := setupJobRepo(ctx, t)
+
+	_, err := repo.GetScheduledJobByName(ctx, "recurring")
+	require.ErrorIs(t, err, domain.ErrJobNotFound)
+
+	err = repo.CreateJob(ctx, &domain.Job{Name: "recurring", Kind: "kind", Schedule: "@every 1h", ScheduledFor: time.Now()})
+	require.NoError(t, err)
+
+	// names of scheduled jobs are unique
+	err = repo.CreateJob(ctx, &domain.Job{Name: "recurring", Kind: "kind", Schedule: "@every 1h", ScheduledFor: time.Now()})
+	require.Error(t, err)
+
+	job, err := repo.GetScheduledJobByName(ctx, "recurring")
+	require.NoError(t, err)
+	assert.Equal(t, "@every 1h", job.Schedule)
+
+	job.Schedule = "@daily"
+	err = repo.UpdateScheduledJob(ctx, job)
+	require.NoError(t, err)
+
+	job.State = domain.JobStateDone
+	err = repo.UpdateJob(ctx, job)
+	require.NoError(t, err)
+
+	_, err = repo.GetScheduledJobByName(ctx, "recurring")
+	require.ErrorIs(t, err, domain.ErrJobNotFound)
+
+	// the finished run is kept, so the next run can use the same name
+	err = repo.CreateJob(ctx, &domain.Job{Name: "recurring", Kind: "kind", Schedule: "@daily", ScheduledFor: time.Now()})
+	require.NoError(t, err)
+
+	err = repo.DeleteScheduledJobByName(ctx, "recurring")
+	require.NoError(t, err)
+
+	_, err = repo.GetScheduledJobByName(ctx, "recurring")
+	require.ErrorIs(t, err, domain.ErrJobNotFound)
+}
+
+func TestJobRepo_DeadJobs(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	repo := setupJobRepo(ctx, t)
+
+	for i := range 3 {
+		err := repo.CreateJob(ctx, &domain.Job{Kind: fmt.Sprintf("job-%d", i), ScheduledFor: time.Now()})
+		require.NoError(t, err)
+	}
+
+	jobs, err := repo.ListNextJobs(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+
+	for _, job := range jobs[:2] {
+		job.State = domain.JobStateDead
+		job.Attempts = 3
+		job.Errors = []domain.JobError{{Attempt: 3, Message: "failed", FailedAt: time.Now().UTC().Round(time.Second)}}
+		err = repo.UpdateJob(ctx, job)
+		require.NoError(t, err)
+	}
+
+	dead, err := repo.ListJobs(ctx, domain.ListJobsQuery{State: domain.JobStateDead, PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, dead.Items, 1)
+	require.NotNil(t, dead.Next)
+	assert.Equal(t, int64(3), dead.Items[0].Attempts)
+	assert.Equal(t, "failed", dead.Items[0].Errors[0].Message)
+	assert.False(t, dead.Items[0].FinishedAt.IsZero())
+
+	dead, err = repo.ListJobs(ctx, domain.ListJobsQuery{State: domain.JobStateDead, PageAfter: dead.Next})
+	require.NoError(t, err)
+	require.Len(t, dead.Items, 1)
+
+	err = repo.RequeueFailedJob(ctx, dead.Items[0].ID, time.Now())
+	require.NoError(t, err)
+
+	job, err := repo.GetJob(ctx, dead.Items[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStateScheduled, job.State)
+	assert.Equal(t, int64(0), job.Attempts)
+	assert.Len(t, job.Errors, 1)
+
+	// only dead or failed jobs can be re-queued
+	err = repo.RequeueFailedJob(ctx, dead.Items[0].ID, time.Now())
+	require.ErrorIs(t, err, domain.ErrJobNotFound)
+}
+
+func TestJobRepo_ListJobs(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	repo := setupJobRepo(ctx, t)
+
+	for i := range 4 {
+		job := &domain.Job{Kind: fmt.Sprintf("job-%d", i%2), ScheduledFor: time.Now()}
+		err := repo.CreateJob(ctx, job)
+		require.NoError(t, err)
+		assert.NotZero(t, job.ID)
+	}
+
+	all, err := repo.ListJobs(ctx, domain.ListJobsQuery{})
+	require.NoError(t, err)
+	require.Len(t, all.Items, 4)
+
+	byKind, err := repo.ListJobs(ctx, domain.ListJobsQuery{Kind: "job-1"})
+	require.NoError(t, err)
+	require.Len(t, byKind.Items, 2)
+
+	for _, job := range byKind.Items {
+		assert.Equal(t, "job-1", job.Kind)
+	}
+
+	err = repo.CancelScheduledJob(ctx, byKind.Items[0].ID, time.Now())
+	require.NoError(t, err)
+
+	cancelled, err := repo.ListJobs(ctx, domain.ListJobsQuery{Kind: "job-1", State: domain.JobStateCancelled})
+	require.NoError(t, err)
+	require.Len(t, cancelled.Items, 1)
+	assert.Equal(t, byKind.Items[0].ID, cancelled.Items[0].ID)
+	assert.False(t, cancelled.Items[0].FinishedAt.IsZero())
+
+	// only scheduled jobs can be cancelled
+	err = repo.CancelScheduledJob(ctx, byKind.Items[0].ID, time.Now())
+	require.ErrorIs(t, err, domain.ErrJobNotFound)
+}
+
+func TestJobRepo_ClaimJob(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	repo := setupJobRepo(ctx, t)
+
+	now := time.Now().UTC().Round(time.Second)
+
+	err := repo.CreateJob(ctx, &domain.Job{Kind: "later", ScheduledFor: now.Add(time.Hour)})
+	require.NoError(t, err)
+
+	err = repo.CreateJob(ctx, &domain.Job{Kind: "due", ScheduledFor: now.Add(-time.Minute)})
+	require.NoError(t, err)
+
+	job, err := repo.GetNextDueJob(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, "due", job.Kind)
+
+	job.LeaseOwner = "instance-a"
+	job.LeaseExpiresAt = now.Add(time.Minute)
+	claimed, err := repo.ClaimJob(ctx, job)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, domain.JobStateRunning, job.State)
+
+	// a job can only be claimed once
+	claimed, err = repo.ClaimJob(ctx, job)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	_, err = repo.GetNextDueJob(ctx, now)
+	require.ErrorIs(t, err, domain.ErrJobNotFound)
+
+	expired, err := repo.ListJobsWithExpiredLease(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+
+	expired, err = repo.ListJobsWithExpiredLease(ctx, now.Add(time.Minute*2))
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, job.ID, expired[0].ID)
+	assert.Equal(t, "instance-a", expired[0].LeaseOwner)
+	assert.Equal(t, now.Add(time.Minute), expired[0].LeaseExpiresAt)
+
+	wakeup, err := repo.GetNextWakeUpTime(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), wakeup)
+
+	// expired leases don't cause immediate wake-ups
+	wakeup, err = repo.GetNextWakeUpTime(ctx, now.Add(time.Minute*2))
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), wakeup)
+
+	renewed, err := repo.RenewJobLease(ctx, &domain.Job{ID: job.ID, LeaseOwner: "instance-a", LeaseExpiresAt: now.Add(time.Minute * 3)})
+	require.NoError(t, err)
+	assert.True(t, renewed)
+
+	expired, err = repo.ListJobsWithExpiredLease(ctx, now.Add(time.Minute*2))
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// only the owner can renew the lease or finish the job
+	renewed, err = repo.RenewJobLease(ctx, &domain.Job{ID: job.ID, LeaseOwner: "instance-b", LeaseExpiresAt: now.Add(time.Minute * 3)})
+	require.NoError(t, err)
+	assert.False(t, renewed)
+
+	err = repo.UpdateJob(ctx, &domain.Job{ID: job.ID, LeaseOwner: "instance-b", State: domain.JobStateDone})
+	require.ErrorIs(t, err, domain.ErrJobLeaseLost)
+
+	job.State = domain.JobStateDone
+	err = repo.UpdateJob(ctx, job)
+	require.NoError(t, err)
+
+	job, err = repo.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStateDone, job.State)
+	assert.Empty(t, job.LeaseOwner)
+	assert.True(t, job.LeaseExpiresAt.IsZero())
+
+	wakeup, err = repo.GetNextWakeUpTime(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), wakeup)
 }
 
 func setupJobRepo(ctx context.Context, t *testing.T) *JobRepo {
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE sync_clients ADD COLUMN acked_changelog_entry_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sync_clients ADD COLUMN last_seen_at TEXT DEFAULT NULL;
+
+
+-- +goose Down
+
+ALTER TABLE sync_clients DROP COLUMN last_seen_at;
+ALTER TABLE sync_clients DROP COLUMN acked_changelog_entry_id;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE full_sync_enrires ADD COLUMN corrupted_at TEXT DEFAULT NULL;
+
+
+-- +goose Down
+
+ALTER TABLE full_sync_enrires DROP COLUMN corrupted_at;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE attachments (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id       TEXT    NOT NULL,
+    account_id      INTEGER NOT NULL,
+
+    sha256          BLOB    NOT NULL,
+    filepath        TEXT    NOT NULL,
+    size_bytes      INT     NOT NULL,
+
+    created_at      TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%SZ', CURRENT_TIMESTAMP)),
+
+    FOREIGN KEY(account_id) REFERENCES accounts(id)
+);
+CREATE UNIQUE INDEX unique_attachments_sha256 ON attachments(account_id, sha256);
+
+
+-- +goose Down
+
+DROP INDEX unique_attachments_sha256;
+DROP TABLE attachments;
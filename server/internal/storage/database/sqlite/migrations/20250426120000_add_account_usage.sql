@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TABLE account_usage (
+    account_id      INTEGER PRIMARY KEY,
+
+    size_bytes      INTEGER NOT NULL DEFAULT 0,
+    num_entries     INTEGER NOT NULL DEFAULT 0,
+
+    updated_at      TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%SZ', CURRENT_TIMESTAMP)),
+
+    FOREIGN KEY(account_id) REFERENCES accounts(id)
+);
+
+CREATE TABLE account_quota_overrides (
+    account_id      INTEGER PRIMARY KEY,
+
+    max_size_bytes  INTEGER DEFAULT NULL,
+    max_entries     INTEGER DEFAULT NULL,
+
+    FOREIGN KEY(account_id) REFERENCES accounts(id)
+);
+
+INSERT INTO account_usage(account_id, size_bytes, num_entries)
+SELECT
+    accounts.id,
+    (SELECT COALESCE(SUM(length(data)), 0) FROM changelog_entries WHERE account_id = accounts.id)
+        + (SELECT COALESCE(SUM(size_bytes), 0) FROM full_sync_enrires WHERE account_id = accounts.id)
+        + (SELECT COALESCE(SUM(size_bytes), 0) FROM attachments WHERE account_id = accounts.id),
+    (SELECT COUNT(*) FROM changelog_entries WHERE account_id = accounts.id)
+FROM accounts;
+
+
+-- +goose Down
+
+DROP TABLE account_quota_overrides;
+DROP TABLE account_usage;
@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE uploads (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id       TEXT    NOT NULL UNIQUE,
+    account_id      INTEGER NOT NULL,
+
+    kind            TEXT    NOT NULL,
+    filepath        TEXT    NOT NULL DEFAULT '',
+    sha256          BLOB    NOT NULL,
+    size_bytes      INTEGER NOT NULL,
+    offset_bytes    INTEGER NOT NULL DEFAULT 0,
+
+    created_at      TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%SZ', CURRENT_TIMESTAMP)),
+    updated_at      TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%SZ', CURRENT_TIMESTAMP)),
+    expires_at      TEXT    NOT NULL,
+
+    FOREIGN KEY(account_id) REFERENCES accounts(id)
+);
+CREATE INDEX uploads_expires_at ON uploads(account_id, expires_at);
+
+
+-- +goose Down
+
+DROP INDEX uploads_expires_at;
+DROP TABLE uploads;
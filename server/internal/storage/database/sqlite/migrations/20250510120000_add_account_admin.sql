@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE accounts ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE accounts ADD COLUMN disabled_at TEXT DEFAULT NULL;
+
+-- the initial account was created by the init setup and is the only one that could exist so far
+UPDATE accounts SET is_admin = true WHERE id = (SELECT MIN(id) FROM accounts);
+
+
+-- +goose Down
+
+ALTER TABLE accounts DROP COLUMN disabled_at;
+ALTER TABLE accounts DROP COLUMN is_admin;
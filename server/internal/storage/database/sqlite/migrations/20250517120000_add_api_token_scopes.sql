@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE api_tokens ADD COLUMN scopes TEXT NOT NULL DEFAULT '';
+
+-- existing tokens had unrestricted access, so they keep all scopes
+UPDATE api_tokens SET scopes = 'memos:create,attachments:create,sync:read,sync:write,tokens:manage';
+
+
+-- +goose Down
+
+ALTER TABLE api_tokens DROP COLUMN scopes;
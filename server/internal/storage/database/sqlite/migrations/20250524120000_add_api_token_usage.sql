@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE api_tokens ADD COLUMN last_used_at TEXT DEFAULT NULL;
+ALTER TABLE api_tokens ADD COLUMN last_used_ip TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_tokens ADD COLUMN last_used_user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_tokens ADD COLUMN request_count INTEGER NOT NULL DEFAULT 0;
+
+
+-- +goose Down
+
+ALTER TABLE api_tokens DROP COLUMN request_count;
+ALTER TABLE api_tokens DROP COLUMN last_used_user_agent;
+ALTER TABLE api_tokens DROP COLUMN last_used_ip;
+ALTER TABLE api_tokens DROP COLUMN last_used_at;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE jobs ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE jobs ADD COLUMN schedule TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX unique_scheduled_job_name ON jobs(name) WHERE name != '' AND state = 'scheduled';
+
+
+-- +goose Down
+
+DROP INDEX unique_scheduled_job_name;
+ALTER TABLE jobs DROP COLUMN schedule;
+ALTER TABLE jobs DROP COLUMN name;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE jobs ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE jobs ADD COLUMN errors BLOB DEFAULT NULL;
+CREATE INDEX jobs_state ON jobs(state, id);
+
+
+-- +goose Down
+
+DROP INDEX jobs_state;
+ALTER TABLE jobs DROP COLUMN errors;
+ALTER TABLE jobs DROP COLUMN attempts;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE jobs ADD COLUMN lease_expires_at TEXT DEFAULT NULL;
+
+
+-- +goose Down
+
+ALTER TABLE jobs DROP COLUMN lease_expires_at;
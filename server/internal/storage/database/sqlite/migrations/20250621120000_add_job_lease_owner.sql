@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE jobs ADD COLUMN lease_owner TEXT NOT NULL DEFAULT '';
+
+
+-- +goose Down
+
+ALTER TABLE jobs DROP COLUMN lease_owner;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE account_totp (
+    account_id      INTEGER PRIMARY KEY,
+
+    secret          BLOB    NOT NULL,
+    last_used_step  INTEGER NOT NULL DEFAULT 0,
+    enabled_at      TEXT    DEFAULT NULL,
+
+    created_at      TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%SZ', CURRENT_TIMESTAMP)),
+
+    FOREIGN KEY(account_id) REFERENCES accounts(id)
+);
+
+CREATE TABLE account_recovery_codes (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id      INTEGER NOT NULL,
+
+    params          TEXT    NOT NULL,
+    salt            BLOB    NOT NULL,
+    code            BLOB    NOT NULL,
+    used_at         TEXT    DEFAULT NULL,
+
+    created_at      TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%SZ', CURRENT_TIMESTAMP)),
+
+    FOREIGN KEY(account_id) REFERENCES accounts(id)
+);
+CREATE INDEX account_recovery_codes_account_id ON account_recovery_codes(account_id);
+
+
+-- +goose Down
+
+DROP INDEX account_recovery_codes_account_id;
+DROP TABLE account_recovery_codes;
+DROP TABLE account_totp;
@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- attachments uploaded directly have no database record and weren't counted when the usage was first calculated,
+-- so the usage of all accounts is recalculated from the blob storage
+INSERT INTO jobs(kind, data)
+SELECT 'recalculate_usage', json_object('AccountID', id) FROM accounts;
+
+
+-- +goose Down
+
+DELETE FROM jobs WHERE kind = 'recalculate_usage' AND state = 'scheduled';
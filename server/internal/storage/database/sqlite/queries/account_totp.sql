@@ -0,0 +1,50 @@
+-- name: GetAccountTOTP :one
+SELECT * FROM account_totp
+WHERE account_id = ?
+LIMIT 1;
+
+-- name: UpsertAccountTOTP :exec
+INSERT INTO account_totp(
+    account_id,
+    secret
+) VALUES (?, ?)
+ON CONFLICT (account_id) DO UPDATE SET
+    secret = excluded.secret,
+    last_used_step = 0,
+    enabled_at = NULL,
+    created_at = strftime('%Y-%m-%d %H:%M:%SZ', CURRENT_TIMESTAMP);
+
+-- name: EnableAccountTOTP :exec
+UPDATE account_totp SET
+    enabled_at = ?,
+    last_used_step = ?
+WHERE account_id = ?;
+
+-- name: UpdateAccountTOTPLastUsedStep :execrows
+UPDATE account_totp SET
+    last_used_step = @step
+WHERE account_id = @account_id AND last_used_step < @step;
+
+-- name: DeleteAccountTOTP :exec
+DELETE FROM account_totp WHERE account_id = ?;
+
+-- name: ListUnusedAccountRecoveryCodes :many
+SELECT * FROM account_recovery_codes
+WHERE account_id = ? AND used_at IS NULL
+ORDER BY id ASC;
+
+-- name: CreateAccountRecoveryCode :exec
+INSERT INTO account_recovery_codes(
+    account_id,
+    params,
+    salt,
+    code
+) VALUES (?, ?, ?, ?);
+
+-- name: MarkAccountRecoveryCodeUsed :execrows
+UPDATE account_recovery_codes SET
+    used_at = ?
+WHERE id = ? AND used_at IS NULL;
+
+-- name: DeleteAccountRecoveryCodes :exec
+DELETE FROM account_recovery_codes WHERE account_id = ?;
@@ -0,0 +1,53 @@
+-- name: GetAccountUsage :one
+SELECT * FROM account_usage WHERE account_id = ?;
+
+-- name: AddAccountUsage :exec
+INSERT INTO account_usage(
+    account_id,
+    size_bytes,
+    num_entries,
+    updated_at
+) VALUES (@account_id, @size_bytes, @num_entries, @updated_at)
+ON CONFLICT(account_id) DO UPDATE SET
+    size_bytes = MAX(account_usage.size_bytes + @size_bytes, 0),
+    num_entries = MAX(account_usage.num_entries + @num_entries, 0),
+    updated_at = @updated_at;
+
+-- name: SetAccountUsage :exec
+INSERT INTO account_usage(
+    account_id,
+    size_bytes,
+    num_entries,
+    updated_at
+) VALUES (?, ?, ?, ?)
+ON CONFLICT(account_id) DO UPDATE SET
+    size_bytes = excluded.size_bytes,
+    num_entries = excluded.num_entries,
+    updated_at = excluded.updated_at;
+
+-- name: CalculateAccountUsage :one
+SELECT
+    CAST(
+        (SELECT COALESCE(SUM(length(data)), 0) FROM changelog_entries WHERE changelog_entries.account_id = @account_id)
+        + (SELECT COALESCE(SUM(size_bytes), 0) FROM uploads WHERE uploads.account_id = @account_id)
+    AS INTEGER) AS size_bytes,
+    CAST((SELECT COUNT(*) FROM changelog_entries WHERE changelog_entries.account_id = @account_id) AS INTEGER) AS num_entries;
+
+-- name: GetAccountQuotaOverride :one
+SELECT * FROM account_quota_overrides WHERE account_id = ?;
+
+-- name: UpsertAccountQuotaOverride :exec
+INSERT INTO account_quota_overrides(
+    account_id,
+    max_size_bytes,
+    max_entries
+) VALUES (?, ?, ?)
+ON CONFLICT(account_id) DO UPDATE SET
+    max_size_bytes = excluded.max_size_bytes,
+    max_entries = excluded.max_entries;
+
+-- name: DeleteAccountQuotaOverride :exec
+DELETE FROM account_quota_overrides WHERE account_id = ?;
+
+-- name: DeleteAccountUsage :exec
+DELETE FROM account_usage WHERE account_id = ?;
@@ -15,14 +15,23 @@ FROM accounts
 WHERE username = ?
 LIMIT 1;
 
+-- name: ListAccounts :many
+SELECT
+    accounts.*
+FROM accounts
+WHERE id > @page_after
+ORDER BY id ASC
+LIMIT @page_size;
+
 -- name: CreateAccount :exec
 INSERT INTO accounts(
     username,
     algorithm,
     params,
     salt,
-    password
-) VALUES (?, ?, ?, ?, ?);
+    password,
+    is_admin
+) VALUES (?, ?, ?, ?, ?, ?);
 
 -- name: UpdateAccount :exec
 UPDATE accounts SET
@@ -34,6 +43,21 @@ UPDATE accounts SET
     updated_at = ?
 WHERE id = ?;
 
+-- name: DisableAccount :exec
+UPDATE accounts SET
+    disabled_at = ?,
+    updated_at = ?
+WHERE id = ?;
+
+-- name: EnableAccount :exec
+UPDATE accounts SET
+    disabled_at = NULL,
+    updated_at = ?
+WHERE id = ?;
+
+-- name: DeleteAccount :exec
+DELETE FROM accounts WHERE id = ?;
+
 
 -- name: GetAccountKeyByName :one
 SELECT * FROM account_keys
@@ -48,3 +72,6 @@ INSERT INTO account_keys(
     data
 ) VALUES (?, ?, ?, ?)
 ON CONFLICT (account_id, name, data) DO NOTHING;
+
+-- name: DeleteAccountKeys :exec
+DELETE FROM account_keys WHERE account_id = ?;
@@ -9,14 +9,36 @@ AND account_id = @account_id
 ORDER BY id DESC
 LIMIT @page_size;
 
+-- name: GetAPITokenByTokenID :one
+SELECT * FROM api_tokens WHERE token_id = ? LIMIT 1;
+
 -- name: CreateAPIToken :exec
 INSERT INTO api_tokens(
     account_id,
     token_id,
 	name,
+    scopes,
     expires_at
-) VALUES (?, ?, ?, ?);
+) VALUES (?, ?, ?, ?, ?);
 
 -- name: DeleteAPIToken :exec
 DELETE FROM api_tokens WHERE name = ? AND account_id = ?;
 
+-- name: DeleteAPITokensWithInvalidAuthTokens :exec
+DELETE FROM api_tokens WHERE token_id IN (SELECT id FROM auth_tokens WHERE is_valid = FALSE);
+
+-- name: DeleteAccountAPITokens :exec
+DELETE FROM api_tokens WHERE account_id = ?;
+
+-- name: RecordAPITokenUsage :exec
+UPDATE api_tokens SET
+    last_used_at = @last_used_at,
+    last_used_ip = @last_used_ip,
+    last_used_user_agent = @last_used_user_agent,
+    request_count = request_count + @request_count
+WHERE id = @id;
+
+-- name: DeleteUnusedAPITokens :execrows
+DELETE FROM api_tokens
+WHERE (last_used_at IS NULL AND created_at < @unused_since)
+OR last_used_at < @unused_since;
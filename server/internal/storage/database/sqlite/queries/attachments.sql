@@ -0,0 +1,18 @@
+-- name: GetAttachmentBySha256 :one
+SELECT * FROM attachments WHERE account_id = ? AND sha256 = ? LIMIT 1;
+
+-- name: CreateAttachment :exec
+INSERT INTO attachments(
+    public_id,
+    account_id,
+    sha256,
+    filepath,
+    size_bytes
+) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(account_id, sha256) DO NOTHING;
+
+-- name: DeleteAttachmentsByFilepath :exec
+DELETE FROM attachments WHERE account_id = ? AND filepath = ?;
+
+-- name: DeleteAccountAttachments :exec
+DELETE FROM attachments WHERE account_id = ?;
@@ -23,10 +23,27 @@ UPDATE auth_tokens
 SET is_valid = false
 WHERE value = ?;
 
+-- name: InvalidateAccountSessionTokens :exec
+UPDATE auth_tokens
+SET is_valid = false
+WHERE account_id = @account_id
+AND id NOT IN (SELECT token_id FROM api_tokens WHERE api_tokens.account_id = @account_id);
+
 -- name: MarkExpiredAuthTokensAsInvalid :exec
 UPDATE auth_tokens
 SET is_valid = false
-WHERE datetime(refresh_expires_at) > datetime("now");
+WHERE datetime(refresh_expires_at) < datetime("now");
 
 -- name: DeleteInvalidTokens :exec
 DELETE FROM auth_tokens WHERE is_valid = FALSE;
+
+-- name: DeleteAccountAuthTokens :exec
+DELETE FROM auth_tokens WHERE account_id = ?;
+
+-- name: InvalidateUnusedAPITokenAuthTokens :exec
+UPDATE auth_tokens SET is_valid = false
+WHERE id IN (
+    SELECT token_id FROM api_tokens
+    WHERE (last_used_at IS NULL AND created_at < @unused_since)
+    OR last_used_at < @unused_since
+);
@@ -1,11 +1,17 @@
 -- name: ListChangelogEntries :many
 SELECT * FROM changelog_entries
 WHERE
-    account_id = ?
+    account_id = @account_id
     AND timestamp >= datetime(@since)
-ORDER BY timestamp DESC;
+    AND id > @page_after
+    AND sync_client_id != @exclude_sync_client_id
+ORDER BY id ASC
+LIMIT @page_size;
 
 
+-- name: GetLatestChangelogEntryID :one
+SELECT CAST(COALESCE(MAX(id), 0) AS INTEGER) AS latest_id FROM changelog_entries WHERE account_id = ?;
+
 -- name: CreateChangelogEntry :exec
 INSERT INTO changelog_entries(
     account_id,
@@ -13,3 +19,13 @@ INSERT INTO changelog_entries(
     data,
     timestamp
 ) VALUES (?, ?, ?, ?);
+
+-- name: DeleteChangelogEntriesBefore :many
+DELETE FROM changelog_entries
+WHERE
+    account_id = @account_id
+    AND datetime(timestamp) < datetime(@before)
+RETURNING length(data);
+
+-- name: DeleteAccountChangelogEntries :exec
+DELETE FROM changelog_entries WHERE account_id = ?;
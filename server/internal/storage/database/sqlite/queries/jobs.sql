@@ -6,26 +6,123 @@ WHERE
     AND state = "scheduled"
 ORDER BY scheduled_for DESC;
 
+-- name: GetNextDueJob :one
+SELECT *
+FROM jobs
+WHERE
+    datetime(scheduled_for) <= datetime(CAST(@scheduled_for AS TEXT))
+    AND state = "scheduled"
+ORDER BY scheduled_for ASC
+LIMIT 1;
+
+-- name: ClaimJob :execrows
+UPDATE jobs
+SET
+    state = "running",
+    lease_owner = ?,
+    lease_expires_at = ?
+WHERE id = ? AND state = "scheduled";
+
+-- name: RenewJobLease :execrows
+UPDATE jobs
+SET lease_expires_at = ?
+WHERE id = ? AND state = "running" AND lease_owner = ?;
+
+-- name: ListJobsWithExpiredLease :many
+SELECT *
+FROM jobs
+WHERE
+    state = "running"
+    AND datetime(lease_expires_at) < datetime(CAST(@now AS TEXT));
+
 -- name: GetNextWakeUpTime :one
 SELECT scheduled_for
+FROM (
+    SELECT scheduled_for FROM jobs WHERE state = "scheduled"
+    UNION ALL
+    SELECT lease_expires_at FROM jobs
+    WHERE
+        state = "running"
+        AND lease_expires_at IS NOT NULL
+        AND datetime(lease_expires_at) >= datetime(CAST(@now AS TEXT))
+)
+ORDER BY scheduled_for ASC
+LIMIT 1;
+
+-- name: GetScheduledJobByName :one
+SELECT *
+FROM jobs
+WHERE name = ? AND state = "scheduled"
+LIMIT 1;
+
+-- name: GetRunningJobByName :one
+SELECT *
 FROM jobs
-WHERE state = "scheduled"
-ORDER BY scheduled_for DESC
+WHERE name = ? AND state = "running"
+ORDER BY id DESC
 LIMIT 1;
 
+-- name: GetJob :one
+SELECT * FROM jobs WHERE id = ?;
+
+-- name: ListJobs :many
+SELECT *
+FROM jobs
+WHERE
+    (CAST(@kind AS TEXT) = '' OR kind = @kind)
+    AND (CAST(@state AS TEXT) = '' OR state = @state)
+    AND id > @page_after
+ORDER BY id ASC
+LIMIT @page_size;
 
--- name: CreateJob :exec
+-- name: CreateJob :one
 INSERT INTO jobs(
+    name,
     kind,
     data,
+    schedule,
     scheduled_for
-) VALUES (?, ?, ?);
+) VALUES (?, ?, ?, ?, ?)
+RETURNING id;
 
--- name: UpdateJob :exec
+-- name: UpdateJob :execrows
 UPDATE jobs
 SET
     state = ?,
     result = ?,
+    attempts = ?,
+    errors = ?,
+    scheduled_for = ?,
+    finished_at = ?,
+    lease_owner = '',
+    lease_expires_at = NULL
+WHERE id = ? AND lease_owner = ?;
+
+-- name: RequeueFailedJob :execrows
+UPDATE jobs
+SET
+    state = "scheduled",
+    name = '',
+    schedule = '',
+    attempts = 0,
+    scheduled_for = ?,
+    finished_at = NULL
+WHERE id = ? AND state IN ("dead", "error");
+
+-- name: CancelScheduledJob :execrows
+UPDATE jobs
+SET
+    state = "cancelled",
     finished_at = ?
-WHERE id = ?;
+WHERE id = ? AND state = "scheduled";
+
+-- name: UpdateScheduledJob :exec
+UPDATE jobs
+SET
+    data = ?,
+    schedule = ?,
+    scheduled_for = ?
+WHERE id = ? AND state = "scheduled";
 
+-- name: DeleteScheduledJobByName :exec
+DELETE FROM jobs WHERE name = ? AND state = "scheduled";
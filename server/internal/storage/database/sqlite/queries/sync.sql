@@ -28,3 +28,54 @@ INSERT INTO full_sync_enrires (
     size_bytes,
     sha256
 ) VALUES (?, ?, ?, ?);
+
+-- name: UpdateSyncClientAck :execrows
+UPDATE sync_clients SET
+    acked_changelog_entry_id = MAX(acked_changelog_entry_id, @acked_changelog_entry_id),
+    last_seen_at = @last_seen_at,
+    updated_at = @last_seen_at
+WHERE public_id = @public_id AND account_id = @account_id;
+
+-- name: ListSyncClients :many
+SELECT
+    sync_clients.*,
+    MAX(changelog_entries.timestamp) AS last_upload_at,
+    COUNT(changelog_entries.id) AS num_changelog_entries
+FROM sync_clients
+LEFT JOIN changelog_entries
+    ON changelog_entries.account_id = sync_clients.account_id
+    AND changelog_entries.sync_client_id = sync_clients.public_id
+WHERE
+    sync_clients.account_id = @account_id
+    AND sync_clients.id > @page_after
+GROUP BY sync_clients.id
+ORDER BY sync_clients.id ASC
+LIMIT @page_size;
+
+-- name: ListFullSyncEntries :many
+SELECT * FROM full_sync_enrires
+WHERE account_id = ?
+ORDER BY timestamp DESC;
+
+-- name: DeleteFullSyncEntry :exec
+DELETE FROM full_sync_enrires WHERE id = ? AND account_id = ?;
+
+-- name: GetFullSyncEntryByTimestamp :one
+SELECT * FROM full_sync_enrires
+WHERE account_id = ? AND timestamp = ?
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListUncorruptedFullSyncEntries :many
+SELECT * FROM full_sync_enrires
+WHERE corrupted_at IS NULL
+ORDER BY id ASC;
+
+-- name: MarkFullSyncEntryCorrupted :exec
+UPDATE full_sync_enrires SET corrupted_at = ? WHERE id = ?;
+
+-- name: DeleteAccountSyncClients :exec
+DELETE FROM sync_clients WHERE account_id = ?;
+
+-- name: DeleteAccountFullSyncEntries :exec
+DELETE FROM full_sync_enrires WHERE account_id = ?;
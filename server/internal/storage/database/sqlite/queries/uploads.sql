@@ -0,0 +1,30 @@
+-- name: GetUpload :one
+SELECT * FROM uploads WHERE account_id = ? AND public_id = ?;
+
+-- name: CreateUpload :exec
+INSERT INTO uploads(
+    public_id,
+    account_id,
+    kind,
+    filepath,
+    sha256,
+    size_bytes,
+    expires_at
+) VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: UpdateUploadOffset :execrows
+UPDATE uploads SET
+    offset_bytes = @offset_bytes,
+    updated_at = @updated_at
+WHERE account_id = @account_id AND public_id = @public_id AND offset_bytes = @prev_offset_bytes;
+
+-- name: ListExpiredUploads :many
+SELECT * FROM uploads
+WHERE account_id = ? AND expires_at <= ?
+ORDER BY expires_at ASC;
+
+-- name: DeleteUpload :exec
+DELETE FROM uploads WHERE account_id = ? AND public_id = ?;
+
+-- name: DeleteAccountUploads :exec
+DELETE FROM uploads WHERE account_id = ?;
@@ -29,8 +29,9 @@ INSERT INTO accounts(
     algorithm,
     params,
     salt,
-    password
-) VALUES (?, ?, ?, ?, ?)
+    password,
+    is_admin
+) VALUES (?, ?, ?, ?, ?, ?)
 `
 
 type CreateAccountParams struct {
@@ -39,6 +40,7 @@ type CreateAccountParams struct {
 	Params    string
 	Salt      []byte
 	Password  []byte
+	IsAdmin   bool
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, db DBTX, arg CreateAccountParams) error {
@@ -48,6 +50,7 @@ func (q *Queries) CreateAccount(ctx context.Context, db DBTX, arg CreateAccountP
 		arg.Params,
 		arg.Salt,
 		arg.Password,
+		arg.IsAdmin,
 	)
 	return err
 }
@@ -79,9 +82,62 @@ func (q *Queries) CreateAccountKey(ctx context.Context, db DBTX, arg CreateAccou
 	return err
 }
 
+const deleteAccount = `-- name: DeleteAccount :exec
+DELETE FROM accounts WHERE id = ?
+`
+
+func (q *Queries) DeleteAccount(ctx context.Context, db DBTX, id domain.AccountID) error {
+	_, err := db.ExecContext(ctx, deleteAccount, id)
+	return err
+}
+
+const deleteAccountKeys = `-- name: DeleteAccountKeys :exec
+DELETE FROM account_keys WHERE account_id = ?
+`
+
+func (q *Queries) DeleteAccountKeys(ctx context.Context, db DBTX, accountID int64) error {
+	_, err := db.ExecContext(ctx, deleteAccountKeys, accountID)
+	return err
+}
+
+const disableAccount = `-- name: DisableAccount :exec
+UPDATE accounts SET
+    disabled_at = ?,
+    updated_at = ?
+WHERE id = ?
+`
+
+type DisableAccountParams struct {
+	DisabledAt types.SQLiteDatetime
+	UpdatedAt  types.SQLiteDatetime
+	ID         domain.AccountID
+}
+
+func (q *Queries) DisableAccount(ctx context.Context, db DBTX, arg DisableAccountParams) error {
+	_, err := db.ExecContext(ctx, disableAccount, arg.DisabledAt, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const enableAccount = `-- name: EnableAccount :exec
+UPDATE accounts SET
+    disabled_at = NULL,
+    updated_at = ?
+WHERE id = ?
+`
+
+type EnableAccountParams struct {
+	UpdatedAt types.SQLiteDatetime
+	ID        domain.AccountID
+}
+
+func (q *Queries) EnableAccount(ctx context.Context, db DBTX, arg EnableAccountParams) error {
+	_, err := db.ExecContext(ctx, enableAccount, arg.UpdatedAt, arg.ID)
+	return err
+}
+
 const getAccount = `-- name: GetAccount :one
 SELECT
-    accounts.id, accounts.username, accounts.algorithm, accounts.params, accounts.salt, accounts.password, accounts.requires_password_change, accounts.created_at, accounts.updated_at
+    accounts.id, accounts.username, accounts.algorithm, accounts.params, accounts.salt, accounts.password, accounts.requires_password_change, accounts.created_at, accounts.updated_at, accounts.is_admin, accounts.disabled_at
 FROM accounts
 WHERE id = ?
 LIMIT 1
@@ -100,13 +156,15 @@ func (q *Queries) GetAccount(ctx context.Context, db DBTX, id domain.AccountID)
 		&i.RequiresPasswordChange,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
 const getAccountByUsername = `-- name: GetAccountByUsername :one
 SELECT
-    accounts.id, accounts.username, accounts.algorithm, accounts.params, accounts.salt, accounts.password, accounts.requires_password_change, accounts.created_at, accounts.updated_at
+    accounts.id, accounts.username, accounts.algorithm, accounts.params, accounts.salt, accounts.password, accounts.requires_password_change, accounts.created_at, accounts.updated_at, accounts.is_admin, accounts.disabled_at
 FROM accounts
 WHERE username = ?
 LIMIT 1
@@ -125,6 +183,8 @@ func (q *Queries) GetAccountByUsername(ctx context.Context, db DBTX, username st
 		&i.RequiresPasswordChange,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
@@ -155,6 +215,55 @@ func (q *Queries) GetAccountKeyByName(ctx context.Context, db DBTX, arg GetAccou
 	return i, err
 }
 
+const listAccounts = `-- name: ListAccounts :many
+SELECT
+    accounts.id, accounts.username, accounts.algorithm, accounts.params, accounts.salt, accounts.password, accounts.requires_password_change, accounts.created_at, accounts.updated_at, accounts.is_admin, accounts.disabled_at
+FROM accounts
+WHERE id > ?1
+ORDER BY id ASC
+LIMIT ?2
+`
+
+type ListAccountsParams struct {
+	PageAfter domain.AccountID
+	PageSize  int64
+}
+
+func (q *Queries) ListAccounts(ctx context.Context, db DBTX, arg ListAccountsParams) ([]Account, error) {
+	rows, err := db.QueryContext(ctx, listAccounts, arg.PageAfter, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Algorithm,
+			&i.Params,
+			&i.Salt,
+			&i.Password,
+			&i.RequiresPasswordChange,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsAdmin,
+			&i.DisabledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateAccount = `-- name: UpdateAccount :exec
 UPDATE accounts SET
     algorithm = ?,
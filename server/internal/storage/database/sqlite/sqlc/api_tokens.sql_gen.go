@@ -17,14 +17,16 @@ INSERT INTO api_tokens(
     account_id,
     token_id,
 	name,
+    scopes,
     expires_at
-) VALUES (?, ?, ?, ?)
+) VALUES (?, ?, ?, ?, ?)
 `
 
 type CreateAPITokenParams struct {
 	AccountID domain.AccountID
 	TokenID   int64
 	Name      string
+	Scopes    string
 	ExpiresAt types.SQLiteDatetime
 }
 
@@ -33,6 +35,7 @@ func (q *Queries) CreateAPIToken(ctx context.Context, db DBTX, arg CreateAPIToke
 		arg.AccountID,
 		arg.TokenID,
 		arg.Name,
+		arg.Scopes,
 		arg.ExpiresAt,
 	)
 	return err
@@ -52,8 +55,40 @@ func (q *Queries) DeleteAPIToken(ctx context.Context, db DBTX, arg DeleteAPIToke
 	return err
 }
 
+const deleteAPITokensWithInvalidAuthTokens = `-- name: DeleteAPITokensWithInvalidAuthTokens :exec
+DELETE FROM api_tokens WHERE token_id IN (SELECT id FROM auth_tokens WHERE is_valid = FALSE)
+`
+
+func (q *Queries) DeleteAPITokensWithInvalidAuthTokens(ctx context.Context, db DBTX) error {
+	_, err := db.ExecContext(ctx, deleteAPITokensWithInvalidAuthTokens)
+	return err
+}
+
+const deleteAccountAPITokens = `-- name: DeleteAccountAPITokens :exec
+DELETE FROM api_tokens WHERE account_id = ?
+`
+
+func (q *Queries) DeleteAccountAPITokens(ctx context.Context, db DBTX, accountID domain.AccountID) error {
+	_, err := db.ExecContext(ctx, deleteAccountAPITokens, accountID)
+	return err
+}
+
+const deleteUnusedAPITokens = `-- name: DeleteUnusedAPITokens :execrows
+DELETE FROM api_tokens
+WHERE (last_used_at IS NULL AND created_at < ?1)
+OR last_used_at < ?1
+`
+
+func (q *Queries) DeleteUnusedAPITokens(ctx context.Context, db DBTX, unusedSince types.SQLiteDatetime) (int64, error) {
+	result, err := db.ExecContext(ctx, deleteUnusedAPITokens, unusedSince)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getAPIToken = `-- name: GetAPIToken :one
-SELECT id, account_id, token_id, name, created_at, expires_at FROM api_tokens WHERE name = ? AND account_id = ? LIMIT 1
+SELECT id, account_id, token_id, name, created_at, expires_at, scopes, last_used_at, last_used_ip, last_used_user_agent, request_count FROM api_tokens WHERE name = ? AND account_id = ? LIMIT 1
 `
 
 type GetAPITokenParams struct {
@@ -71,12 +106,40 @@ func (q *Queries) GetAPIToken(ctx context.Context, db DBTX, arg GetAPITokenParam
 		&i.Name,
 		&i.CreatedAt,
 		&i.ExpiresAt,
+		&i.Scopes,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.LastUsedUserAgent,
+		&i.RequestCount,
+	)
+	return i, err
+}
+
+const getAPITokenByTokenID = `-- name: GetAPITokenByTokenID :one
+SELECT id, account_id, token_id, name, created_at, expires_at, scopes, last_used_at, last_used_ip, last_used_user_agent, request_count FROM api_tokens WHERE token_id = ? LIMIT 1
+`
+
+func (q *Queries) GetAPITokenByTokenID(ctx context.Context, db DBTX, tokenID int64) (ApiToken, error) {
+	row := db.QueryRowContext(ctx, getAPITokenByTokenID, tokenID)
+	var i ApiToken
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.TokenID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.Scopes,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.LastUsedUserAgent,
+		&i.RequestCount,
 	)
 	return i, err
 }
 
 const listAPITokens = `-- name: ListAPITokens :many
-SELECT id, account_id, token_id, name, created_at, expires_at
+SELECT id, account_id, token_id, name, created_at, expires_at, scopes, last_used_at, last_used_ip, last_used_user_agent, request_count
 FROM api_tokens
 WHERE id >= ?1
 AND account_id = ?2
@@ -106,6 +169,11 @@ func (q *Queries) ListAPITokens(ctx context.Context, db DBTX, arg ListAPITokensP
 			&i.Name,
 			&i.CreatedAt,
 			&i.ExpiresAt,
+			&i.Scopes,
+			&i.LastUsedAt,
+			&i.LastUsedIp,
+			&i.LastUsedUserAgent,
+			&i.RequestCount,
 		); err != nil {
 			return nil, err
 		}
@@ -119,3 +187,31 @@ func (q *Queries) ListAPITokens(ctx context.Context, db DBTX, arg ListAPITokensP
 	}
 	return items, nil
 }
+
+const recordAPITokenUsage = `-- name: RecordAPITokenUsage :exec
+UPDATE api_tokens SET
+    last_used_at = ?1,
+    last_used_ip = ?2,
+    last_used_user_agent = ?3,
+    request_count = request_count + ?4
+WHERE id = ?5
+`
+
+type RecordAPITokenUsageParams struct {
+	LastUsedAt        types.SQLiteDatetime
+	LastUsedIp        string
+	LastUsedUserAgent string
+	RequestCount      int64
+	ID                domain.APITokenID
+}
+
+func (q *Queries) RecordAPITokenUsage(ctx context.Context, db DBTX, arg RecordAPITokenUsageParams) error {
+	_, err := db.ExecContext(ctx, recordAPITokenUsage,
+		arg.LastUsedAt,
+		arg.LastUsedIp,
+		arg.LastUsedUserAgent,
+		arg.RequestCount,
+		arg.ID,
+	)
+	return err
+}
@@ -46,6 +46,15 @@ func (q *Queries) CreateAuthToken(ctx context.Context, db DBTX, arg CreateAuthTo
 	return id, err
 }
 
+const deleteAccountAuthTokens = `-- name: DeleteAccountAuthTokens :exec
+DELETE FROM auth_tokens WHERE account_id = ?
+`
+
+func (q *Queries) DeleteAccountAuthTokens(ctx context.Context, db DBTX, accountID domain.AccountID) error {
+	_, err := db.ExecContext(ctx, deleteAccountAuthTokens, accountID)
+	return err
+}
+
 const deleteInvalidTokens = `-- name: DeleteInvalidTokens :exec
 DELETE FROM auth_tokens WHERE is_valid = FALSE
 `
@@ -120,6 +129,18 @@ func (q *Queries) GetAuthTokenByRefreshValue(ctx context.Context, db DBTX, refre
 	return i, err
 }
 
+const invalidateAccountSessionTokens = `-- name: InvalidateAccountSessionTokens :exec
+UPDATE auth_tokens
+SET is_valid = false
+WHERE account_id = ?1
+AND id NOT IN (SELECT token_id FROM api_tokens WHERE api_tokens.account_id = ?1)
+`
+
+func (q *Queries) InvalidateAccountSessionTokens(ctx context.Context, db DBTX, accountID domain.AccountID) error {
+	_, err := db.ExecContext(ctx, invalidateAccountSessionTokens, accountID)
+	return err
+}
+
 const invalidateAuthToken = `-- name: InvalidateAuthToken :exec
 UPDATE auth_tokens
 SET is_valid = false
@@ -131,10 +152,24 @@ func (q *Queries) InvalidateAuthToken(ctx context.Context, db DBTX, value []byte
 	return err
 }
 
+const invalidateUnusedAPITokenAuthTokens = `-- name: InvalidateUnusedAPITokenAuthTokens :exec
+UPDATE auth_tokens SET is_valid = false
+WHERE id IN (
+    SELECT token_id FROM api_tokens
+    WHERE (last_used_at IS NULL AND created_at < ?1)
+    OR last_used_at < ?1
+)
+`
+
+func (q *Queries) InvalidateUnusedAPITokenAuthTokens(ctx context.Context, db DBTX, unusedSince types.SQLiteDatetime) error {
+	_, err := db.ExecContext(ctx, invalidateUnusedAPITokenAuthTokens, unusedSince)
+	return err
+}
+
 const markExpiredAuthTokensAsInvalid = `-- name: MarkExpiredAuthTokensAsInvalid :exec
 UPDATE auth_tokens
 SET is_valid = false
-WHERE datetime(refresh_expires_at) > datetime("now")
+WHERE datetime(refresh_expires_at) < datetime("now")
 `
 
 func (q *Queries) MarkExpiredAuthTokensAsInvalid(ctx context.Context, db DBTX) error {
@@ -38,21 +38,89 @@ func (q *Queries) CreateChangelogEntry(ctx context.Context, db DBTX, arg CreateC
 	return err
 }
 
+const deleteAccountChangelogEntries = `-- name: DeleteAccountChangelogEntries :exec
+DELETE FROM changelog_entries WHERE account_id = ?
+`
+
+func (q *Queries) DeleteAccountChangelogEntries(ctx context.Context, db DBTX, accountID domain.AccountID) error {
+	_, err := db.ExecContext(ctx, deleteAccountChangelogEntries, accountID)
+	return err
+}
+
+const deleteChangelogEntriesBefore = `-- name: DeleteChangelogEntriesBefore :many
+DELETE FROM changelog_entries
+WHERE
+    account_id = ?1
+    AND datetime(timestamp) < datetime(?2)
+RETURNING length(data)
+`
+
+type DeleteChangelogEntriesBeforeParams struct {
+	AccountID domain.AccountID
+	Before    interface{}
+}
+
+func (q *Queries) DeleteChangelogEntriesBefore(ctx context.Context, db DBTX, arg DeleteChangelogEntriesBeforeParams) ([]int64, error) {
+	rows, err := db.QueryContext(ctx, deleteChangelogEntriesBefore, arg.AccountID, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var length int64
+		if err := rows.Scan(&length); err != nil {
+			return nil, err
+		}
+		items = append(items, length)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLatestChangelogEntryID = `-- name: GetLatestChangelogEntryID :one
+SELECT CAST(COALESCE(MAX(id), 0) AS INTEGER) AS latest_id FROM changelog_entries WHERE account_id = ?
+`
+
+func (q *Queries) GetLatestChangelogEntryID(ctx context.Context, db DBTX, accountID domain.AccountID) (int64, error) {
+	row := db.QueryRowContext(ctx, getLatestChangelogEntryID, accountID)
+	var latest_id int64
+	err := row.Scan(&latest_id)
+	return latest_id, err
+}
+
 const listChangelogEntries = `-- name: ListChangelogEntries :many
 SELECT id, account_id, sync_client_id, data, timestamp FROM changelog_entries
 WHERE
-    account_id = ?
-    AND timestamp >= datetime(?)
-ORDER BY timestamp DESC
+    account_id = ?1
+    AND timestamp >= datetime(?2)
+    AND id > ?3
+    AND sync_client_id != ?4
+ORDER BY id ASC
+LIMIT ?5
 `
 
 type ListChangelogEntriesParams struct {
-	AccountID domain.AccountID
-	Since     interface{}
+	AccountID           domain.AccountID
+	Since               interface{}
+	PageAfter           domain.ChangelogEntryID
+	ExcludeSyncClientID domain.SyncClientID
+	PageSize            int64
 }
 
 func (q *Queries) ListChangelogEntries(ctx context.Context, db DBTX, arg ListChangelogEntriesParams) ([]ChangelogEntry, error) {
-	rows, err := db.QueryContext(ctx, listChangelogEntries, arg.AccountID, arg.Since)
+	rows, err := db.QueryContext(ctx, listChangelogEntries,
+		arg.AccountID,
+		arg.Since,
+		arg.PageAfter,
+		arg.ExcludeSyncClientID,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -11,42 +11,339 @@ import (
 	"go.robinthrift.com/conveyor/internal/storage/database/sqlite/types"
 )
 
-const createJob = `-- name: CreateJob :exec
+const cancelScheduledJob = `-- name: CancelScheduledJob :execrows
+UPDATE jobs
+SET
+    state = "cancelled",
+    finished_at = ?
+WHERE id = ? AND state = "scheduled"
+`
+
+type CancelScheduledJobParams struct {
+	FinishedAt types.SQLiteDatetime
+	ID         int64
+}
+
+func (q *Queries) CancelScheduledJob(ctx context.Context, db DBTX, arg CancelScheduledJobParams) (int64, error) {
+	result, err := db.ExecContext(ctx, cancelScheduledJob, arg.FinishedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const claimJob = `-- name: ClaimJob :execrows
+UPDATE jobs
+SET
+    state = "running",
+    lease_owner = ?,
+    lease_expires_at = ?
+WHERE id = ? AND state = "scheduled"
+`
+
+type ClaimJobParams struct {
+	LeaseOwner     string
+	LeaseExpiresAt types.SQLiteDatetime
+	ID             int64
+}
+
+func (q *Queries) ClaimJob(ctx context.Context, db DBTX, arg ClaimJobParams) (int64, error) {
+	result, err := db.ExecContext(ctx, claimJob, arg.LeaseOwner, arg.LeaseExpiresAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createJob = `-- name: CreateJob :one
 INSERT INTO jobs(
+    name,
     kind,
     data,
+    schedule,
     scheduled_for
-) VALUES (?, ?, ?)
+) VALUES (?, ?, ?, ?, ?)
+RETURNING id
 `
 
 type CreateJobParams struct {
+	Name         string
 	Kind         string
 	Data         types.SQLiteJSON
+	Schedule     string
 	ScheduledFor types.SQLiteDatetime
 }
 
-func (q *Queries) CreateJob(ctx context.Context, db DBTX, arg CreateJobParams) error {
-	_, err := db.ExecContext(ctx, createJob, arg.Kind, arg.Data, arg.ScheduledFor)
+func (q *Queries) CreateJob(ctx context.Context, db DBTX, arg CreateJobParams) (int64, error) {
+	row := db.QueryRowContext(ctx, createJob,
+		arg.Name,
+		arg.Kind,
+		arg.Data,
+		arg.Schedule,
+		arg.ScheduledFor,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteScheduledJobByName = `-- name: DeleteScheduledJobByName :exec
+DELETE FROM jobs WHERE name = ? AND state = "scheduled"
+`
+
+func (q *Queries) DeleteScheduledJobByName(ctx context.Context, db DBTX, name string) error {
+	_, err := db.ExecContext(ctx, deleteScheduledJobByName, name)
 	return err
 }
 
+const getJob = `-- name: GetJob :one
+SELECT id, state, kind, data, result, scheduled_for, created_at, finished_at, name, schedule, attempts, errors, lease_expires_at, lease_owner FROM jobs WHERE id = ?
+`
+
+func (q *Queries) GetJob(ctx context.Context, db DBTX, id int64) (Job, error) {
+	row := db.QueryRowContext(ctx, getJob, id)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.State,
+		&i.Kind,
+		&i.Data,
+		&i.Result,
+		&i.ScheduledFor,
+		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.Name,
+		&i.Schedule,
+		&i.Attempts,
+		&i.Errors,
+		&i.LeaseExpiresAt,
+		&i.LeaseOwner,
+	)
+	return i, err
+}
+
+const getNextDueJob = `-- name: GetNextDueJob :one
+SELECT id, state, kind, data, result, scheduled_for, created_at, finished_at, name, schedule, attempts, errors, lease_expires_at, lease_owner
+FROM jobs
+WHERE
+    datetime(scheduled_for) <= datetime(CAST(?1 AS TEXT))
+    AND state = "scheduled"
+ORDER BY scheduled_for ASC
+LIMIT 1
+`
+
+func (q *Queries) GetNextDueJob(ctx context.Context, db DBTX, scheduledFor string) (Job, error) {
+	row := db.QueryRowContext(ctx, getNextDueJob, scheduledFor)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.State,
+		&i.Kind,
+		&i.Data,
+		&i.Result,
+		&i.ScheduledFor,
+		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.Name,
+		&i.Schedule,
+		&i.Attempts,
+		&i.Errors,
+		&i.LeaseExpiresAt,
+		&i.LeaseOwner,
+	)
+	return i, err
+}
+
 const getNextWakeUpTime = `-- name: GetNextWakeUpTime :one
 SELECT scheduled_for
-FROM jobs
-WHERE state = "scheduled"
-ORDER BY scheduled_for DESC
+FROM (
+    SELECT scheduled_for FROM jobs WHERE state = "scheduled"
+    UNION ALL
+    SELECT lease_expires_at FROM jobs
+    WHERE
+        state = "running"
+        AND lease_expires_at IS NOT NULL
+        AND datetime(lease_expires_at) >= datetime(CAST(?1 AS TEXT))
+)
+ORDER BY scheduled_for ASC
 LIMIT 1
 `
 
-func (q *Queries) GetNextWakeUpTime(ctx context.Context, db DBTX) (types.SQLiteDatetime, error) {
-	row := db.QueryRowContext(ctx, getNextWakeUpTime)
+func (q *Queries) GetNextWakeUpTime(ctx context.Context, db DBTX, now string) (types.SQLiteDatetime, error) {
+	row := db.QueryRowContext(ctx, getNextWakeUpTime, now)
 	var scheduled_for types.SQLiteDatetime
 	err := row.Scan(&scheduled_for)
 	return scheduled_for, err
 }
 
+const getRunningJobByName = `-- name: GetRunningJobByName :one
+SELECT id, state, kind, data, result, scheduled_for, created_at, finished_at, name, schedule, attempts, errors, lease_expires_at, lease_owner
+FROM jobs
+WHERE name = ? AND state = "running"
+ORDER BY id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetRunningJobByName(ctx context.Context, db DBTX, name string) (Job, error) {
+	row := db.QueryRowContext(ctx, getRunningJobByName, name)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.State,
+		&i.Kind,
+		&i.Data,
+		&i.Result,
+		&i.ScheduledFor,
+		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.Name,
+		&i.Schedule,
+		&i.Attempts,
+		&i.Errors,
+		&i.LeaseExpiresAt,
+		&i.LeaseOwner,
+	)
+	return i, err
+}
+
+const getScheduledJobByName = `-- name: GetScheduledJobByName :one
+SELECT id, state, kind, data, result, scheduled_for, created_at, finished_at, name, schedule, attempts, errors, lease_expires_at, lease_owner
+FROM jobs
+WHERE name = ? AND state = "scheduled"
+LIMIT 1
+`
+
+func (q *Queries) GetScheduledJobByName(ctx context.Context, db DBTX, name string) (Job, error) {
+	row := db.QueryRowContext(ctx, getScheduledJobByName, name)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.State,
+		&i.Kind,
+		&i.Data,
+		&i.Result,
+		&i.ScheduledFor,
+		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.Name,
+		&i.Schedule,
+		&i.Attempts,
+		&i.Errors,
+		&i.LeaseExpiresAt,
+		&i.LeaseOwner,
+	)
+	return i, err
+}
+
+const listJobs = `-- name: ListJobs :many
+SELECT id, state, kind, data, result, scheduled_for, created_at, finished_at, name, schedule, attempts, errors, lease_expires_at, lease_owner
+FROM jobs
+WHERE
+    (CAST(?1 AS TEXT) = '' OR kind = ?1)
+    AND (CAST(?2 AS TEXT) = '' OR state = ?2)
+    AND id > ?3
+ORDER BY id ASC
+LIMIT ?4
+`
+
+type ListJobsParams struct {
+	Kind      string
+	State     string
+	PageAfter int64
+	PageSize  int64
+}
+
+func (q *Queries) ListJobs(ctx context.Context, db DBTX, arg ListJobsParams) ([]Job, error) {
+	rows, err := db.QueryContext(ctx, listJobs,
+		arg.Kind,
+		arg.State,
+		arg.PageAfter,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Job
+	for rows.Next() {
+		var i Job
+		if err := rows.Scan(
+			&i.ID,
+			&i.State,
+			&i.Kind,
+			&i.Data,
+			&i.Result,
+			&i.ScheduledFor,
+			&i.CreatedAt,
+			&i.FinishedAt,
+			&i.Name,
+			&i.Schedule,
+			&i.Attempts,
+			&i.Errors,
+			&i.LeaseExpiresAt,
+			&i.LeaseOwner,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobsWithExpiredLease = `-- name: ListJobsWithExpiredLease :many
+SELECT id, state, kind, data, result, scheduled_for, created_at, finished_at, name, schedule, attempts, errors, lease_expires_at, lease_owner
+FROM jobs
+WHERE
+    state = "running"
+    AND datetime(lease_expires_at) < datetime(CAST(?1 AS TEXT))
+`
+
+func (q *Queries) ListJobsWithExpiredLease(ctx context.Context, db DBTX, now string) ([]Job, error) {
+	rows, err := db.QueryContext(ctx, listJobsWithExpiredLease, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Job
+	for rows.Next() {
+		var i Job
+		if err := rows.Scan(
+			&i.ID,
+			&i.State,
+			&i.Kind,
+			&i.Data,
+			&i.Result,
+			&i.ScheduledFor,
+			&i.CreatedAt,
+			&i.FinishedAt,
+			&i.Name,
+			&i.Schedule,
+			&i.Attempts,
+			&i.Errors,
+			&i.LeaseExpiresAt,
+			&i.LeaseOwner,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listNextJobs = `-- name: ListNextJobs :many
-SELECT id, state, kind, data, result, scheduled_for, created_at, finished_at
+SELECT id, state, kind, data, result, scheduled_for, created_at, finished_at, name, schedule, attempts, errors, lease_expires_at, lease_owner
 FROM jobs
 WHERE
     datetime(scheduled_for) <= datetime(CAST(?1 AS TEXT))
@@ -72,6 +369,12 @@ func (q *Queries) ListNextJobs(ctx context.Context, db DBTX, scheduledFor string
 			&i.ScheduledFor,
 			&i.CreatedAt,
 			&i.FinishedAt,
+			&i.Name,
+			&i.Schedule,
+			&i.Attempts,
+			&i.Errors,
+			&i.LeaseExpiresAt,
+			&i.LeaseOwner,
 		); err != nil {
 			return nil, err
 		}
@@ -86,28 +389,115 @@ func (q *Queries) ListNextJobs(ctx context.Context, db DBTX, scheduledFor string
 	return items, nil
 }
 
-const updateJob = `-- name: UpdateJob :exec
+const renewJobLease = `-- name: RenewJobLease :execrows
+UPDATE jobs
+SET lease_expires_at = ?
+WHERE id = ? AND state = "running" AND lease_owner = ?
+`
+
+type RenewJobLeaseParams struct {
+	LeaseExpiresAt types.SQLiteDatetime
+	ID             int64
+	LeaseOwner     string
+}
+
+func (q *Queries) RenewJobLease(ctx context.Context, db DBTX, arg RenewJobLeaseParams) (int64, error) {
+	result, err := db.ExecContext(ctx, renewJobLease, arg.LeaseExpiresAt, arg.ID, arg.LeaseOwner)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const requeueFailedJob = `-- name: RequeueFailedJob :execrows
+UPDATE jobs
+SET
+    state = "scheduled",
+    name = '',
+    schedule = '',
+    attempts = 0,
+    scheduled_for = ?,
+    finished_at = NULL
+WHERE id = ? AND state IN ("dead", "error")
+`
+
+type RequeueFailedJobParams struct {
+	ScheduledFor types.SQLiteDatetime
+	ID           int64
+}
+
+func (q *Queries) RequeueFailedJob(ctx context.Context, db DBTX, arg RequeueFailedJobParams) (int64, error) {
+	result, err := db.ExecContext(ctx, requeueFailedJob, arg.ScheduledFor, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateJob = `-- name: UpdateJob :execrows
 UPDATE jobs
 SET
     state = ?,
     result = ?,
-    finished_at = ?
-WHERE id = ?
+    attempts = ?,
+    errors = ?,
+    scheduled_for = ?,
+    finished_at = ?,
+    lease_owner = '',
+    lease_expires_at = NULL
+WHERE id = ? AND lease_owner = ?
 `
 
 type UpdateJobParams struct {
-	State      interface{}
-	Result     types.SQLiteJSON
-	FinishedAt types.SQLiteDatetime
-	ID         int64
+	State        interface{}
+	Result       types.SQLiteJSON
+	Attempts     int64
+	Errors       types.SQLiteJSON
+	ScheduledFor types.SQLiteDatetime
+	FinishedAt   types.SQLiteDatetime
+	ID           int64
+	LeaseOwner   string
 }
 
-func (q *Queries) UpdateJob(ctx context.Context, db DBTX, arg UpdateJobParams) error {
-	_, err := db.ExecContext(ctx, updateJob,
+func (q *Queries) UpdateJob(ctx context.Context, db DBTX, arg UpdateJobParams) (int64, error) {
+	result, err := db.ExecContext(ctx, updateJob,
 		arg.State,
 		arg.Result,
+		arg.Attempts,
+		arg.Errors,
+		arg.ScheduledFor,
 		arg.FinishedAt,
 		arg.ID,
+		arg.LeaseOwner,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateScheduledJob = `-- name: UpdateScheduledJob :exec
+UPDATE jobs
+SET
+    data = ?,
+    schedule = ?,
+    scheduled_for = ?
+WHERE id = ? AND state = "scheduled"
+`
+
+type UpdateScheduledJobParams struct {
+	Data         types.SQLiteJSON
+	Schedule     string
+	ScheduledFor types.SQLiteDatetime
+	ID           int64
+}
+
+func (q *Queries) UpdateScheduledJob(ctx context.Context, db DBTX, arg UpdateScheduledJobParams) error {
+	_, err := db.ExecContext(ctx, updateScheduledJob,
+		arg.Data,
+		arg.Schedule,
+		arg.ScheduledFor,
+		arg.ID,
 	)
 	return err
 }
@@ -5,6 +5,8 @@
 package sqlc
 
 import (
+	"database/sql"
+
 	"go.robinthrift.com/conveyor/internal/auth"
 	"go.robinthrift.com/conveyor/internal/domain"
 	"go.robinthrift.com/conveyor/internal/storage/database/sqlite/types"
@@ -20,6 +22,8 @@ type Account struct {
 	RequiresPasswordChange bool
 	CreatedAt              types.SQLiteDatetime
 	UpdatedAt              types.SQLiteDatetime
+	IsAdmin                bool
+	DisabledAt             types.SQLiteDatetime
 }
 
 type AccountKey struct {
@@ -32,13 +36,59 @@ type AccountKey struct {
 	UpdatedAt string
 }
 
+type AccountQuotaOverride struct {
+	AccountID    domain.AccountID
+	MaxSizeBytes sql.NullInt64
+	MaxEntries   sql.NullInt64
+}
+
+type AccountRecoveryCode struct {
+	ID        int64
+	AccountID domain.AccountID
+	Params    string
+	Salt      []byte
+	Code      []byte
+	UsedAt    types.SQLiteDatetime
+	CreatedAt types.SQLiteDatetime
+}
+
+type AccountTotp struct {
+	AccountID    domain.AccountID
+	Secret       []byte
+	LastUsedStep int64
+	EnabledAt    types.SQLiteDatetime
+	CreatedAt    types.SQLiteDatetime
+}
+
+type AccountUsage struct {
+	AccountID  domain.AccountID
+	SizeBytes  int64
+	NumEntries int64
+	UpdatedAt  types.SQLiteDatetime
+}
+
 type ApiToken struct {
-	ID        domain.APITokenID
+	ID                domain.APITokenID
+	AccountID         domain.AccountID
+	TokenID           int64
+	Name              string
+	CreatedAt         types.SQLiteDatetime
+	ExpiresAt         types.SQLiteDatetime
+	Scopes            string
+	LastUsedAt        types.SQLiteDatetime
+	LastUsedIp        string
+	LastUsedUserAgent string
+	RequestCount      int64
+}
+
+type Attachment struct {
+	ID        int64
+	PublicID  string
 	AccountID domain.AccountID
-	TokenID   int64
-	Name      string
+	Sha256    []byte
+	Filepath  string
+	SizeBytes int64
 	CreatedAt types.SQLiteDatetime
-	ExpiresAt types.SQLiteDatetime
 }
 
 type AuthToken struct {
@@ -61,28 +111,51 @@ type ChangelogEntry struct {
 }
 
 type FullSyncEnrire struct {
-	ID        int64
-	AccountID domain.AccountID
-	Timestamp types.SQLiteDatetime
-	SizeBytes int64
-	Sha256    []byte
+	ID          int64
+	AccountID   domain.AccountID
+	Timestamp   types.SQLiteDatetime
+	SizeBytes   int64
+	Sha256      []byte
+	CorruptedAt types.SQLiteDatetime
 }
 
 type Job struct {
-	ID           int64
-	State        interface{}
-	Kind         string
-	Data         types.SQLiteJSON
-	Result       types.SQLiteJSON
-	ScheduledFor types.SQLiteDatetime
-	CreatedAt    types.SQLiteDatetime
-	FinishedAt   types.SQLiteDatetime
+	ID             int64
+	State          interface{}
+	Kind           string
+	Data           types.SQLiteJSON
+	Result         types.SQLiteJSON
+	ScheduledFor   types.SQLiteDatetime
+	CreatedAt      types.SQLiteDatetime
+	FinishedAt     types.SQLiteDatetime
+	Name           string
+	Schedule       string
+	Attempts       int64
+	Errors         types.SQLiteJSON
+	LeaseExpiresAt types.SQLiteDatetime
+	LeaseOwner     string
 }
 
 type SyncClient struct {
-	ID        int64
-	PublicID  domain.SyncClientID
-	AccountID domain.AccountID
-	CreatedAt types.SQLiteDatetime
-	UpdatedAt types.SQLiteDatetime
+	ID                    int64
+	PublicID              domain.SyncClientID
+	AccountID             domain.AccountID
+	CreatedAt             types.SQLiteDatetime
+	UpdatedAt             types.SQLiteDatetime
+	AckedChangelogEntryID domain.ChangelogEntryID
+	LastSeenAt            types.SQLiteDatetime
+}
+
+type Upload struct {
+	ID          int64
+	PublicID    domain.UploadID
+	AccountID   domain.AccountID
+	Kind        string
+	Filepath    string
+	Sha256      []byte
+	SizeBytes   int64
+	OffsetBytes int64
+	CreatedAt   types.SQLiteDatetime
+	UpdatedAt   types.SQLiteDatetime
+	ExpiresAt   types.SQLiteDatetime
 }
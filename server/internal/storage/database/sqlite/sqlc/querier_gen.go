@@ -13,35 +13,99 @@ import (
 )
 
 type Querier interface {
+	AddAccountUsage(ctx context.Context, db DBTX, arg AddAccountUsageParams) error
+	CalculateAccountUsage(ctx context.Context, db DBTX, accountID domain.AccountID) (CalculateAccountUsageRow, error)
+	CancelScheduledJob(ctx context.Context, db DBTX, arg CancelScheduledJobParams) (int64, error)
+	ClaimJob(ctx context.Context, db DBTX, arg ClaimJobParams) (int64, error)
 	CountAccounts(ctx context.Context, db DBTX) (int64, error)
 	CreateAPIToken(ctx context.Context, db DBTX, arg CreateAPITokenParams) error
 	CreateAccount(ctx context.Context, db DBTX, arg CreateAccountParams) error
 	CreateAccountKey(ctx context.Context, db DBTX, arg CreateAccountKeyParams) error
+	CreateAccountRecoveryCode(ctx context.Context, db DBTX, arg CreateAccountRecoveryCodeParams) error
+	CreateAttachment(ctx context.Context, db DBTX, arg CreateAttachmentParams) error
 	CreateAuthToken(ctx context.Context, db DBTX, arg CreateAuthTokenParams) (auth.AuthTokenID, error)
 	CreateChangelogEntry(ctx context.Context, db DBTX, arg CreateChangelogEntryParams) error
 	CreateFullSyncEntry(ctx context.Context, db DBTX, arg CreateFullSyncEntryParams) error
-	CreateJob(ctx context.Context, db DBTX, arg CreateJobParams) error
+	CreateJob(ctx context.Context, db DBTX, arg CreateJobParams) (int64, error)
 	CreateSyncClient(ctx context.Context, db DBTX, arg CreateSyncClientParams) error
+	CreateUpload(ctx context.Context, db DBTX, arg CreateUploadParams) error
 	DeleteAPIToken(ctx context.Context, db DBTX, arg DeleteAPITokenParams) error
+	DeleteAPITokensWithInvalidAuthTokens(ctx context.Context, db DBTX) error
+	DeleteAccount(ctx context.Context, db DBTX, id domain.AccountID) error
+	DeleteAccountAPITokens(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAccountAttachments(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAccountAuthTokens(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAccountChangelogEntries(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAccountFullSyncEntries(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAccountKeys(ctx context.Context, db DBTX, accountID int64) error
+	DeleteAccountQuotaOverride(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAccountRecoveryCodes(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAccountSyncClients(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAccountTOTP(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAccountUploads(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAccountUsage(ctx context.Context, db DBTX, accountID domain.AccountID) error
+	DeleteAttachmentsByFilepath(ctx context.Context, db DBTX, arg DeleteAttachmentsByFilepathParams) error
+	DeleteChangelogEntriesBefore(ctx context.Context, db DBTX, arg DeleteChangelogEntriesBeforeParams) ([]int64, error)
+	DeleteFullSyncEntry(ctx context.Context, db DBTX, arg DeleteFullSyncEntryParams) error
 	DeleteInvalidTokens(ctx context.Context, db DBTX) error
+	DeleteScheduledJobByName(ctx context.Context, db DBTX, name string) error
 	DeleteSyncClientByPublicID(ctx context.Context, db DBTX, arg DeleteSyncClientByPublicIDParams) error
+	DeleteUnusedAPITokens(ctx context.Context, db DBTX, unusedSince types.SQLiteDatetime) (int64, error)
+	DeleteUpload(ctx context.Context, db DBTX, arg DeleteUploadParams) error
+	DisableAccount(ctx context.Context, db DBTX, arg DisableAccountParams) error
+	EnableAccount(ctx context.Context, db DBTX, arg EnableAccountParams) error
+	EnableAccountTOTP(ctx context.Context, db DBTX, arg EnableAccountTOTPParams) error
 	GetAPIToken(ctx context.Context, db DBTX, arg GetAPITokenParams) (ApiToken, error)
+	GetAPITokenByTokenID(ctx context.Context, db DBTX, tokenID int64) (ApiToken, error)
 	GetAccount(ctx context.Context, db DBTX, id domain.AccountID) (Account, error)
 	GetAccountByUsername(ctx context.Context, db DBTX, username string) (Account, error)
 	GetAccountKeyByName(ctx context.Context, db DBTX, arg GetAccountKeyByNameParams) (AccountKey, error)
+	GetAccountQuotaOverride(ctx context.Context, db DBTX, accountID domain.AccountID) (AccountQuotaOverride, error)
+	GetAccountTOTP(ctx context.Context, db DBTX, accountID domain.AccountID) (AccountTotp, error)
+	GetAccountUsage(ctx context.Context, db DBTX, accountID domain.AccountID) (AccountUsage, error)
+	GetAttachmentBySha256(ctx context.Context, db DBTX, arg GetAttachmentBySha256Params) (Attachment, error)
 	GetAuthToken(ctx context.Context, db DBTX, value []byte) (AuthToken, error)
 	GetAuthTokenByID(ctx context.Context, db DBTX, arg GetAuthTokenByIDParams) (AuthToken, error)
 	GetAuthTokenByRefreshValue(ctx context.Context, db DBTX, refreshValue []byte) (AuthToken, error)
+	GetFullSyncEntryByTimestamp(ctx context.Context, db DBTX, arg GetFullSyncEntryByTimestampParams) (FullSyncEnrire, error)
+	GetJob(ctx context.Context, db DBTX, id int64) (Job, error)
+	GetLatestChangelogEntryID(ctx context.Context, db DBTX, accountID domain.AccountID) (int64, error)
 	GetLatestFullSyncEntry(ctx context.Context, db DBTX, accountID domain.AccountID) (FullSyncEnrire, error)
-	GetNextWakeUpTime(ctx context.Context, db DBTX) (types.SQLiteDatetime, error)
+	GetNextDueJob(ctx context.Context, db DBTX, scheduledFor string) (Job, error)
+	GetNextWakeUpTime(ctx context.Context, db DBTX, now string) (types.SQLiteDatetime, error)
+	GetRunningJobByName(ctx context.Context, db DBTX, name string) (Job, error)
+	GetScheduledJobByName(ctx context.Context, db DBTX, name string) (Job, error)
 	GetSyncClient(ctx context.Context, db DBTX, arg GetSyncClientParams) (SyncClient, error)
+	GetUpload(ctx context.Context, db DBTX, arg GetUploadParams) (Upload, error)
+	InvalidateAccountSessionTokens(ctx context.Context, db DBTX, accountID domain.AccountID) error
 	InvalidateAuthToken(ctx context.Context, db DBTX, value []byte) error
+	InvalidateUnusedAPITokenAuthTokens(ctx context.Context, db DBTX, unusedSince types.SQLiteDatetime) error
 	ListAPITokens(ctx context.Context, db DBTX, arg ListAPITokensParams) ([]ApiToken, error)
+	ListAccounts(ctx context.Context, db DBTX, arg ListAccountsParams) ([]Account, error)
 	ListChangelogEntries(ctx context.Context, db DBTX, arg ListChangelogEntriesParams) ([]ChangelogEntry, error)
+	ListExpiredUploads(ctx context.Context, db DBTX, arg ListExpiredUploadsParams) ([]Upload, error)
+	ListFullSyncEntries(ctx context.Context, db DBTX, accountID domain.AccountID) ([]FullSyncEnrire, error)
+	ListJobs(ctx context.Context, db DBTX, arg ListJobsParams) ([]Job, error)
+	ListJobsWithExpiredLease(ctx context.Context, db DBTX, now string) ([]Job, error)
 	ListNextJobs(ctx context.Context, db DBTX, scheduledFor string) ([]Job, error)
+	ListSyncClients(ctx context.Context, db DBTX, arg ListSyncClientsParams) ([]ListSyncClientsRow, error)
+	ListUncorruptedFullSyncEntries(ctx context.Context, db DBTX) ([]FullSyncEnrire, error)
+	ListUnusedAccountRecoveryCodes(ctx context.Context, db DBTX, accountID domain.AccountID) ([]AccountRecoveryCode, error)
+	MarkAccountRecoveryCodeUsed(ctx context.Context, db DBTX, arg MarkAccountRecoveryCodeUsedParams) (int64, error)
 	MarkExpiredAuthTokensAsInvalid(ctx context.Context, db DBTX) error
+	MarkFullSyncEntryCorrupted(ctx context.Context, db DBTX, arg MarkFullSyncEntryCorruptedParams) error
+	RecordAPITokenUsage(ctx context.Context, db DBTX, arg RecordAPITokenUsageParams) error
+	RenewJobLease(ctx context.Context, db DBTX, arg RenewJobLeaseParams) (int64, error)
+	RequeueFailedJob(ctx context.Context, db DBTX, arg RequeueFailedJobParams) (int64, error)
+	SetAccountUsage(ctx context.Context, db DBTX, arg SetAccountUsageParams) error
 	UpdateAccount(ctx context.Context, db DBTX, arg UpdateAccountParams) error
-	UpdateJob(ctx context.Context, db DBTX, arg UpdateJobParams) error
+	UpdateAccountTOTPLastUsedStep(ctx context.Context, db DBTX, arg UpdateAccountTOTPLastUsedStepParams) (int64, error)
+	UpdateJob(ctx context.Context, db DBTX, arg UpdateJobParams) (int64, error)
+	UpdateScheduledJob(ctx context.Context, db DBTX, arg UpdateScheduledJobParams) error
+	UpdateSyncClientAck(ctx context.Context, db DBTX, arg UpdateSyncClientAckParams) (int64, error)
+	UpdateUploadOffset(ctx context.Context, db DBTX, arg UpdateUploadOffsetParams) (int64, error)
+	UpsertAccountQuotaOverride(ctx context.Context, db DBTX, arg UpsertAccountQuotaOverrideParams) error
+	UpsertAccountTOTP(ctx context.Context, db DBTX, arg UpsertAccountTOTPParams) error
 }
 
 var _ Querier = (*Queries)(nil)
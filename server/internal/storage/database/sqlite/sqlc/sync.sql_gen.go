@@ -55,6 +55,38 @@ func (q *Queries) CreateSyncClient(ctx context.Context, db DBTX, arg CreateSyncC
 	return err
 }
 
+const deleteAccountFullSyncEntries = `-- name: DeleteAccountFullSyncEntries :exec
+DELETE FROM full_sync_enrires WHERE account_id = ?
+`
+
+func (q *Queries) DeleteAccountFullSyncEntries(ctx context.Context, db DBTX, accountID domain.AccountID) error {
+	_, err := db.ExecContext(ctx, deleteAccountFullSyncEntries, accountID)
+	return err
+}
+
+const deleteAccountSyncClients = `-- name: DeleteAccountSyncClients :exec
+DELETE FROM sync_clients WHERE account_id = ?
+`
+
+func (q *Queries) DeleteAccountSyncClients(ctx context.Context, db DBTX, accountID domain.AccountID) error {
+	_, err := db.ExecContext(ctx, deleteAccountSyncClients, accountID)
+	return err
+}
+
+const deleteFullSyncEntry = `-- name: DeleteFullSyncEntry :exec
+DELETE FROM full_sync_enrires WHERE id = ? AND account_id = ?
+`
+
+type DeleteFullSyncEntryParams struct {
+	ID        int64
+	AccountID domain.AccountID
+}
+
+func (q *Queries) DeleteFullSyncEntry(ctx context.Context, db DBTX, arg DeleteFullSyncEntryParams) error {
+	_, err := db.ExecContext(ctx, deleteFullSyncEntry, arg.ID, arg.AccountID)
+	return err
+}
+
 const deleteSyncClientByPublicID = `-- name: DeleteSyncClientByPublicID :exec
 DELETE FROM sync_clients WHERE public_id = ? AND account_id = ?
 `
@@ -69,8 +101,34 @@ func (q *Queries) DeleteSyncClientByPublicID(ctx context.Context, db DBTX, arg D
 	return err
 }
 
+const getFullSyncEntryByTimestamp = `-- name: GetFullSyncEntryByTimestamp :one
+SELECT id, account_id, timestamp, size_bytes, sha256, corrupted_at FROM full_sync_enrires
+WHERE account_id = ? AND timestamp = ?
+ORDER BY id DESC
+LIMIT 1
+`
+
+type GetFullSyncEntryByTimestampParams struct {
+	AccountID domain.AccountID
+	Timestamp types.SQLiteDatetime
+}
+
+func (q *Queries) GetFullSyncEntryByTimestamp(ctx context.Context, db DBTX, arg GetFullSyncEntryByTimestampParams) (FullSyncEnrire, error) {
+	row := db.QueryRowContext(ctx, getFullSyncEntryByTimestamp, arg.AccountID, arg.Timestamp)
+	var i FullSyncEnrire
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Timestamp,
+		&i.SizeBytes,
+		&i.Sha256,
+		&i.CorruptedAt,
+	)
+	return i, err
+}
+
 const getLatestFullSyncEntry = `-- name: GetLatestFullSyncEntry :one
-SELECT id, account_id, timestamp, size_bytes, sha256 FROM full_sync_enrires
+SELECT id, account_id, timestamp, size_bytes, sha256, corrupted_at FROM full_sync_enrires
 WHERE account_id = ?
 ORDER BY timestamp DESC
 LIMIT 1
@@ -85,12 +143,13 @@ func (q *Queries) GetLatestFullSyncEntry(ctx context.Context, db DBTX, accountID
 		&i.Timestamp,
 		&i.SizeBytes,
 		&i.Sha256,
+		&i.CorruptedAt,
 	)
 	return i, err
 }
 
 const getSyncClient = `-- name: GetSyncClient :one
-SELECT id, public_id, account_id, created_at, updated_at FROM sync_clients
+SELECT id, public_id, account_id, created_at, updated_at, acked_changelog_entry_id, last_seen_at FROM sync_clients
 WHERE
     public_id = ?
     AND account_id = ?
@@ -111,6 +170,190 @@ func (q *Queries) GetSyncClient(ctx context.Context, db DBTX, arg GetSyncClientP
 		&i.AccountID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AckedChangelogEntryID,
+		&i.LastSeenAt,
 	)
 	return i, err
 }
+
+const listFullSyncEntries = `-- name: ListFullSyncEntries :many
+SELECT id, account_id, timestamp, size_bytes, sha256, corrupted_at FROM full_sync_enrires
+WHERE account_id = ?
+ORDER BY timestamp DESC
+`
+
+func (q *Queries) ListFullSyncEntries(ctx context.Context, db DBTX, accountID domain.AccountID) ([]FullSyncEnrire, error) {
+	rows, err := db.QueryContext(ctx, listFullSyncEntries, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FullSyncEnrire
+	for rows.Next() {
+		var i FullSyncEnrire
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Timestamp,
+			&i.SizeBytes,
+			&i.Sha256,
+			&i.CorruptedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSyncClients = `-- name: ListSyncClients :many
+SELECT
+    sync_clients.id, sync_clients.public_id, sync_clients.account_id, sync_clients.created_at, sync_clients.updated_at, sync_clients.acked_changelog_entry_id, sync_clients.last_seen_at,
+    MAX(changelog_entries.timestamp) AS last_upload_at,
+    COUNT(changelog_entries.id) AS num_changelog_entries
+FROM sync_clients
+LEFT JOIN changelog_entries
+    ON changelog_entries.account_id = sync_clients.account_id
+    AND changelog_entries.sync_client_id = sync_clients.public_id
+WHERE
+    sync_clients.account_id = ?1
+    AND sync_clients.id > ?2
+GROUP BY sync_clients.id
+ORDER BY sync_clients.id ASC
+LIMIT ?3
+`
+
+type ListSyncClientsParams struct {
+	AccountID domain.AccountID
+	PageAfter int64
+	PageSize  int64
+}
+
+type ListSyncClientsRow struct {
+	ID                    int64
+	PublicID              domain.SyncClientID
+	AccountID             domain.AccountID
+	CreatedAt             types.SQLiteDatetime
+	UpdatedAt             types.SQLiteDatetime
+	AckedChangelogEntryID domain.ChangelogEntryID
+	LastSeenAt            types.SQLiteDatetime
+	LastUploadAt          interface{}
+	NumChangelogEntries   int64
+}
+
+func (q *Queries) ListSyncClients(ctx context.Context, db DBTX, arg ListSyncClientsParams) ([]ListSyncClientsRow, error) {
+	rows, err := db.QueryContext(ctx, listSyncClients, arg.AccountID, arg.PageAfter, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSyncClientsRow
+	for rows.Next() {
+		var i ListSyncClientsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PublicID,
+			&i.AccountID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AckedChangelogEntryID,
+			&i.LastSeenAt,
+			&i.LastUploadAt,
+			&i.NumChangelogEntries,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUncorruptedFullSyncEntries = `-- name: ListUncorruptedFullSyncEntries :many
+SELECT id, account_id, timestamp, size_bytes, sha256, corrupted_at FROM full_sync_enrires
+WHERE corrupted_at IS NULL
+ORDER BY id ASC
+`
+
+func (q *Queries) ListUncorruptedFullSyncEntries(ctx context.Context, db DBTX) ([]FullSyncEnrire, error) {
+	rows, err := db.QueryContext(ctx, listUncorruptedFullSyncEntries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FullSyncEnrire
+	for rows.Next() {
+		var i FullSyncEnrire
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Timestamp,
+			&i.SizeBytes,
+			&i.Sha256,
+			&i.CorruptedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markFullSyncEntryCorrupted = `-- name: MarkFullSyncEntryCorrupted :exec
+UPDATE full_sync_enrires SET corrupted_at = ? WHERE id = ?
+`
+
+type MarkFullSyncEntryCorruptedParams struct {
+	CorruptedAt types.SQLiteDatetime
+	ID          int64
+}
+
+func (q *Queries) MarkFullSyncEntryCorrupted(ctx context.Context, db DBTX, arg MarkFullSyncEntryCorruptedParams) error {
+	_, err := db.ExecContext(ctx, markFullSyncEntryCorrupted, arg.CorruptedAt, arg.ID)
+	return err
+}
+
+const updateSyncClientAck = `-- name: UpdateSyncClientAck :execrows
+UPDATE sync_clients SET
+    acked_changelog_entry_id = MAX(acked_changelog_entry_id, ?1),
+    last_seen_at = ?2,
+    updated_at = ?2
+WHERE public_id = ?3 AND account_id = ?4
+`
+
+type UpdateSyncClientAckParams struct {
+	AckedChangelogEntryID interface{}
+	LastSeenAt            types.SQLiteDatetime
+	PublicID              domain.SyncClientID
+	AccountID             domain.AccountID
+}
+
+func (q *Queries) UpdateSyncClientAck(ctx context.Context, db DBTX, arg UpdateSyncClientAckParams) (int64, error) {
+	result, err := db.ExecContext(ctx, updateSyncClientAck,
+		arg.AckedChangelogEntryID,
+		arg.LastSeenAt,
+		arg.PublicID,
+		arg.AccountID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
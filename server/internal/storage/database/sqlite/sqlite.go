@@ -151,3 +151,15 @@ func (d *debugExecutor) QueryRowContext(ctx context.Context, query string, args
 
 	return d.exec.QueryRowContext(ctx, query, args...)
 }
+
+// freeBytes returns the size of the unused pages of the database, as seen by the connection or transaction.
+func freeBytes(ctx context.Context, conn database.Executor) (int64, error) {
+	var n int64
+
+	err := conn.QueryRowContext(ctx, "SELECT freelist_count * page_size FROM pragma_freelist_count(), pragma_page_size()").Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("error getting free pages: %w", err)
+	}
+
+	return n, nil
+}
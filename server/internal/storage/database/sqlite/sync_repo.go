@@ -22,6 +22,80 @@ func NewSyncRepo(db database.Database) *SyncRepo {
 	return &SyncRepo{db}
 }
 
+func (r *SyncRepo) GetSyncClient(ctx context.Context, accountID domain.AccountID, id domain.SyncClientID) (*domain.SyncClient, error) {
+	row, err := queries.GetSyncClient(ctx, r.db.Conn(ctx), sqlc.GetSyncClientParams{
+		AccountID: accountID,
+		PublicID:  id,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrSyncClientNotFound
+		}
+
+		return nil, err
+	}
+
+	return &domain.SyncClient{
+		ID:                    row.PublicID,
+		AccountID:             row.AccountID,
+		AckedChangelogEntryID: row.AckedChangelogEntryID,
+		LastSeenAt:            row.LastSeenAt.Time,
+		CreatedAt:             row.CreatedAt.Time,
+	}, nil
+}
+
+func (r *SyncRepo) ListSyncClients(ctx context.Context, accountID domain.AccountID, query domain.ListSyncClientsQuery) (*domain.SyncClientList, error) {
+	pageAfter := int64(0)
+	if query.PageAfter != nil {
+		pageAfter = *query.PageAfter
+	}
+
+	var pageSize int64
+	if query.PageSize >= maxPageSize {
+		pageSize = maxPageSize
+	} else {
+		pageSize = int64(query.PageSize)
+	}
+
+	rows, err := queries.ListSyncClients(ctx, r.db.Conn(ctx), sqlc.ListSyncClientsParams{
+		AccountID: accountID,
+		PageAfter: pageAfter,
+		PageSize:  pageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing sync clients: %w", err)
+	}
+
+	list := &domain.SyncClientList{
+		Items: make([]*domain.SyncClient, len(rows)),
+		Next:  nil,
+	}
+
+	for i, row := range rows {
+		var lastUploadAt types.SQLiteDatetime
+		if err := lastUploadAt.Scan(row.LastUploadAt); err != nil {
+			return nil, fmt.Errorf("error parsing last upload time of sync client: %w", err)
+		}
+
+		list.Items[i] = &domain.SyncClient{
+			ID:                    row.PublicID,
+			AccountID:             row.AccountID,
+			AckedChangelogEntryID: row.AckedChangelogEntryID,
+			LastSeenAt:            row.LastSeenAt.Time,
+			CreatedAt:             row.CreatedAt.Time,
+			LastUploadAt:          lastUploadAt.Time,
+			NumChangelogEntries:   row.NumChangelogEntries,
+		}
+	}
+
+	if len(rows) != 0 {
+		next := rows[len(rows)-1].ID
+		list.Next = &next
+	}
+
+	return list, nil
+}
+
 func (r *SyncRepo) CreateSyncClient(ctx context.Context, client *domain.SyncClient) error {
 	err := queries.CreateSyncClient(ctx, r.db.Conn(ctx), sqlc.CreateSyncClientParams{
 		AccountID: client.AccountID,
@@ -55,18 +129,68 @@ func (r *SyncRepo) DeleteSyncClient(ctx context.Context, client *domain.SyncClie
 	return nil
 }
 
-func (r *SyncRepo) ListChangelogEntries(ctx context.Context, query domain.ListChangelogEntriesQuery) ([]domain.ChangelogEntry, error) {
+func (r *SyncRepo) UpdateSyncClientAck(ctx context.Context, client *domain.SyncClient) error {
+	affected, err := queries.UpdateSyncClientAck(ctx, r.db.Conn(ctx), sqlc.UpdateSyncClientAckParams{
+		AckedChangelogEntryID: client.AckedChangelogEntryID,
+		LastSeenAt:            types.NewSQLiteDatetime(client.LastSeenAt),
+		PublicID:              client.ID,
+		AccountID:             client.AccountID,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating sync client acknowledgement: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.ErrSyncClientNotFound
+	}
+
+	return nil
+}
+
+const (
+	defaultChangelogEntriesPageSize = 250
+	maxChangelogEntriesPageSize     = 1000
+)
+
+// ListChangelogEntries returns the entries in the order they were created. Next is the ID of the last returned entry, or
+// the requested cursor if the page is empty.
+func (r *SyncRepo) ListChangelogEntries(ctx context.Context, query domain.ListChangelogEntriesQuery) (*domain.ChangelogEntryList, error) {
+	pageAfter := domain.ChangelogEntryID(0)
+	if query.PageAfter != nil {
+		pageAfter = *query.PageAfter
+	}
+
+	var pageSize int64
+
+	switch {
+	case query.PageSize == 0:
+		pageSize = defaultChangelogEntriesPageSize
+	case query.PageSize >= maxChangelogEntriesPageSize:
+		pageSize = maxChangelogEntriesPageSize
+	default:
+		pageSize = int64(query.PageSize)
+	}
+
 	rows, err := queries.ListChangelogEntries(ctx, r.db.Conn(ctx), sqlc.ListChangelogEntriesParams{
-		AccountID: query.AccountID,
-		Since:     types.NewSQLiteDatetime(query.Since),
+		AccountID:           query.AccountID,
+		Since:               types.NewSQLiteDatetime(query.Since),
+		PageAfter:           pageAfter,
+		ExcludeSyncClientID: query.ExcludeSyncClientID,
+		PageSize:            pageSize,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	entries := make([]domain.ChangelogEntry, 0, len(rows))
+	list := &domain.ChangelogEntryList{
+		Items:   make([]domain.ChangelogEntry, 0, len(rows)),
+		Next:    &pageAfter,
+		HasMore: int64(len(rows)) == pageSize,
+	}
+
 	for _, row := range rows {
-		entries = append(entries, domain.ChangelogEntry{
+		list.Items = append(list.Items, domain.ChangelogEntry{
+			ID:           row.ID,
 			SyncClientID: row.SyncClientID,
 			AccountID:    row.AccountID,
 			Data:         row.Data,
@@ -74,7 +198,12 @@ func (r *SyncRepo) ListChangelogEntries(ctx context.Context, query domain.ListCh
 		})
 	}
 
-	return entries, nil
+	if len(rows) != 0 {
+		next := rows[len(rows)-1].ID
+		list.Next = &next
+	}
+
+	return list, nil
 }
 
 func (r *SyncRepo) CreateChangelogEntries(ctx context.Context, entries []domain.ChangelogEntry) error {
@@ -93,6 +222,50 @@ func (r *SyncRepo) CreateChangelogEntries(ctx context.Context, entries []domain.
 	return nil
 }
 
+// GetLatestChangelogEntryID returns the ID of the account's most recent entry, or 0 if it has none.
+func (r *SyncRepo) GetLatestChangelogEntryID(ctx context.Context, accountID domain.AccountID) (domain.ChangelogEntryID, error) {
+	id, err := queries.GetLatestChangelogEntryID(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting latest changelog entry id: %w", err)
+	}
+
+	return domain.ChangelogEntryID(id), nil
+}
+
+// DeleteChangelogEntriesBefore deletes the account's entries created before the given time. The reclaimed bytes are the
+// size of the database pages freed by the deletion, which SQLite reuses for new data; the database file itself only
+// shrinks once it's vacuumed.
+func (r *SyncRepo) DeleteChangelogEntriesBefore(ctx context.Context, accountID domain.AccountID, before time.Time) (*domain.DeletedChangelogEntries, error) {
+	freeBytesBefore, err := freeBytes(ctx, r.db.Conn(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	sizes, err := queries.DeleteChangelogEntriesBefore(ctx, r.db.Conn(ctx), sqlc.DeleteChangelogEntriesBeforeParams{
+		AccountID: accountID,
+		Before:    types.NewSQLiteDatetime(before),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error deleting changelog entries: %w", err)
+	}
+
+	freeBytesAfter, err := freeBytes(ctx, r.db.Conn(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := &domain.DeletedChangelogEntries{
+		NumEntries:     int64(len(sizes)),
+		ReclaimedBytes: max(freeBytesAfter-freeBytesBefore, 0),
+	}
+
+	for _, size := range sizes {
+		deleted.SizeBytes += size
+	}
+
+	return deleted, nil
+}
+
 func (r *SyncRepo) CreateFullSyncEntry(ctx context.Context, entry *domain.FullSyncEntry) error {
 	err := queries.CreateFullSyncEntry(ctx, r.db.Conn(ctx), sqlc.CreateFullSyncEntryParams{
 		AccountID: entry.AccountID,
@@ -118,9 +291,100 @@ func (r *SyncRepo) GetLatestFullSyncEntry(ctx context.Context, accountID domain.
 	}
 
 	return &domain.FullSyncEntry{
-		AccountID:  row.AccountID,
-		Timestamp:  row.Timestamp.Time,
-		SizeBytes:  row.SizeBytes,
-		Sha256Hash: row.Sha256,
+		ID:          row.ID,
+		AccountID:   row.AccountID,
+		Timestamp:   row.Timestamp.Time,
+		SizeBytes:   row.SizeBytes,
+		Sha256Hash:  row.Sha256,
+		CorruptedAt: row.CorruptedAt.Time,
+	}, nil
+}
+
+func (r *SyncRepo) ListFullSyncEntries(ctx context.Context, accountID domain.AccountID) ([]*domain.FullSyncEntry, error) {
+	rows, err := queries.ListFullSyncEntries(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing full sync entries: %w", err)
+	}
+
+	entries := make([]*domain.FullSyncEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = &domain.FullSyncEntry{
+			ID:          row.ID,
+			AccountID:   row.AccountID,
+			Timestamp:   row.Timestamp.Time,
+			SizeBytes:   row.SizeBytes,
+			Sha256Hash:  row.Sha256,
+			CorruptedAt: row.CorruptedAt.Time,
+		}
+	}
+
+	return entries, nil
+}
+
+func (r *SyncRepo) DeleteFullSyncEntry(ctx context.Context, entry *domain.FullSyncEntry) error {
+	err := queries.DeleteFullSyncEntry(ctx, r.db.Conn(ctx), sqlc.DeleteFullSyncEntryParams{
+		ID:        entry.ID,
+		AccountID: entry.AccountID,
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting full sync entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SyncRepo) GetFullSyncEntryByTimestamp(ctx context.Context, accountID domain.AccountID, timestamp time.Time) (*domain.FullSyncEntry, error) {
+	row, err := queries.GetFullSyncEntryByTimestamp(ctx, r.db.Conn(ctx), sqlc.GetFullSyncEntryByTimestampParams{
+		AccountID: accountID,
+		Timestamp: types.NewSQLiteDatetime(timestamp),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNoFullSyncEntriesFound
+		}
+
+		return nil, err
+	}
+
+	return &domain.FullSyncEntry{
+		ID:          row.ID,
+		AccountID:   row.AccountID,
+		Timestamp:   row.Timestamp.Time,
+		SizeBytes:   row.SizeBytes,
+		Sha256Hash:  row.Sha256,
+		CorruptedAt: row.CorruptedAt.Time,
 	}, nil
 }
+
+func (r *SyncRepo) ListUncorruptedFullSyncEntries(ctx context.Context) ([]*domain.FullSyncEntry, error) {
+	rows, err := queries.ListUncorruptedFullSyncEntries(ctx, r.db.Conn(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error listing uncorrupted full sync entries: %w", err)
+	}
+
+	entries := make([]*domain.FullSyncEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = &domain.FullSyncEntry{
+			ID:          row.ID,
+			AccountID:   row.AccountID,
+			Timestamp:   row.Timestamp.Time,
+			SizeBytes:   row.SizeBytes,
+			Sha256Hash:  row.Sha256,
+			CorruptedAt: row.CorruptedAt.Time,
+		}
+	}
+
+	return entries, nil
+}
+
+func (r *SyncRepo) MarkFullSyncEntryCorrupted(ctx context.Context, entry *domain.FullSyncEntry) error {
+	err := queries.MarkFullSyncEntryCorrupted(ctx, r.db.Conn(ctx), sqlc.MarkFullSyncEntryCorruptedParams{
+		CorruptedAt: types.NewSQLiteDatetime(entry.CorruptedAt),
+		ID:          entry.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error marking full sync entry as corrupted: %w", err)
+	}
+
+	return nil
+}
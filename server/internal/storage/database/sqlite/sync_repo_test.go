@@ -0,0 +1,246 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/domain"
+)
+
+func TestSyncRepo_ListChangelogEntries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Default Page Size", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		t.Cleanup(cancel)
+
+		setup := setupSyncRepo(ctx, t)
+		setup.createChangelogEntries(ctx, defaultChangelogEntriesPageSize+10)
+
+		list, err := setup.repo.ListChangelogEntries(ctx, domain.ListChangelogEntriesQuery{AccountID: setup.accountID})
+		require.NoError(t, err)
+		assert.Len(t, list.Items, defaultChangelogEntriesPageSize)
+		assert.True(t, list.HasMore)
+		require.NotNil(t, list.Next)
+		assert.Equal(t, list.Items[defaultChangelogEntriesPageSize-1].ID, *list.Next)
+
+		list, err = setup.repo.ListChangelogEntries(ctx, domain.ListChangelogEntriesQuery{AccountID: setup.accountID, PageAfter: list.Next})
+		require.NoError(t, err)
+		assert.Len(t, list.Items, 10)
+		assert.False(t, list.HasMore)
+		require.NotNil(t, list.Next)
+		assert.Equal(t, list.Items[9].ID, *list.Next)
+	})
+
+	t.Run("Paginated", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		t.Cleanup(cancel)
+
+		numEntries := 100
+
+		setup := setupSyncRepo(ctx, t)
+		setup.createChangelogEntries(ctx, numEntries)
+
+		var pageAfter *domain.ChangelogEntryID
+
+		var lastID domain.ChangelogEntryID
+
+		total := 0
+
+		for i := 0; i < numEntries; i += 25 {
+			list, err := setup.repo.ListChangelogEntries(ctx, domain.ListChangelogEntriesQuery{
+				AccountID: setup.accountID,
+				PageSize:  25,
+				PageAfter: pageAfter,
+			})
+			require.NoError(t, err, i)
+
+			assert.Lenf(t, list.Items, 25, "i = %d", i)
+			assert.Greaterf(t, list.Items[0].ID, lastID, "i = %d", i)
+			assert.Equalf(t, fmt.Sprintf("entry_%d", i), string(list.Items[0].Data), "i = %d", i)
+
+			lastID = list.Items[len(list.Items)-1].ID
+			pageAfter = list.Next
+			total += len(list.Items)
+		}
+
+		assert.Equal(t, numEntries, total)
+
+		// the last page was full, so the page after it is requested as well, but it's empty
+		list, err := setup.repo.ListChangelogEntries(ctx, domain.ListChangelogEntriesQuery{
+			AccountID: setup.accountID,
+			PageSize:  25,
+			PageAfter: pageAfter,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, list.Items)
+		assert.False(t, list.HasMore)
+		// the cursor is kept, so polling can continue from it
+		assert.Equal(t, pageAfter, list.Next)
+	})
+
+	t.Run("Since", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		t.Cleanup(cancel)
+
+		setup := setupSyncRepo(ctx, t)
+		setup.createChangelogEntries(ctx, 10)
+
+		list, err := setup.repo.ListChangelogEntries(ctx, domain.ListChangelogEntriesQuery{
+			AccountID: setup.accountID,
+			Since:     time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, list.Items)
+		assert.False(t, list.HasMore)
+		require.NotNil(t, list.Next)
+		assert.Equal(t, domain.ChangelogEntryID(0), *list.Next)
+	})
+}
+
+func TestSyncRepo_ListChangelogEntries_ExcludeSyncClientID(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	setup := setupSyncRepo(ctx, t)
+
+	for _, clientID := range []domain.SyncClientID{"client_a", "client_b", "client_a"} {
+		err := setup.repo.CreateChangelogEntries(ctx, []domain.ChangelogEntry{{
+			AccountID:    setup.accountID,
+			SyncClientID: clientID,
+			Data:         []byte(clientID),
+		}})
+		require.NoError(t, err)
+	}
+
+	list, err := setup.repo.ListChangelogEntries(ctx, domain.ListChangelogEntriesQuery{
+		AccountID:           setup.accountID,
+		ExcludeSyncClientID: "client_a",
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, domain.SyncClientID("client_b"), list.Items[0].SyncClientID)
+}
+
+func TestSyncRepo_UpdateSyncClientAck(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	setup := setupSyncRepo(ctx, t)
+
+	err := setup.repo.CreateSyncClient(ctx, &domain.SyncClient{ID: "client_a", AccountID: setup.accountID})
+	require.NoError(t, err)
+
+	lastSeenAt := time.Now().UTC().Round(time.Second)
+
+	err = setup.repo.UpdateSyncClientAck(ctx, &domain.SyncClient{ID: "client_a", AccountID: setup.accountID, AckedChangelogEntryID: 10, LastSeenAt: lastSeenAt})
+	require.NoError(t, err)
+
+	// an older acknowledgement must not move the watermark backwards
+	err = setup.repo.UpdateSyncClientAck(ctx, &domain.SyncClient{ID: "client_a", AccountID: setup.accountID, AckedChangelogEntryID: 5, LastSeenAt: lastSeenAt.Add(time.Minute)})
+	require.NoError(t, err)
+
+	client, err := setup.repo.GetSyncClient(ctx, setup.accountID, "client_a")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ChangelogEntryID(10), client.AckedChangelogEntryID)
+	assert.Equal(t, lastSeenAt.Add(time.Minute), client.LastSeenAt)
+
+	err = setup.repo.UpdateSyncClientAck(ctx, &domain.SyncClient{ID: "unknown", AccountID: setup.accountID, AckedChangelogEntryID: 5, LastSeenAt: lastSeenAt})
+	require.ErrorIs(t, err, domain.ErrSyncClientNotFound)
+}
+
+func TestSyncRepo_ListSyncClients(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	setup := setupSyncRepo(ctx, t)
+
+	for _, id := range []domain.SyncClientID{"client_a", "client_b", "client_c"} {
+		err := setup.repo.CreateSyncClient(ctx, &domain.SyncClient{ID: id, AccountID: setup.accountID})
+		require.NoError(t, err)
+	}
+
+	err := setup.repo.CreateChangelogEntries(ctx, []domain.ChangelogEntry{
+		{AccountID: setup.accountID, SyncClientID: "client_a", Data: []byte("entry_0")},
+		{AccountID: setup.accountID, SyncClientID: "client_a", Data: []byte("entry_1")},
+		{AccountID: setup.accountID, SyncClientID: "client_c", Data: []byte("entry_2")},
+	})
+	require.NoError(t, err)
+
+	page, err := setup.repo.ListSyncClients(ctx, setup.accountID, domain.ListSyncClientsQuery{PageSize: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	require.NotNil(t, page.Next)
+
+	assert.Equal(t, domain.SyncClientID("client_a"), page.Items[0].ID)
+	assert.Equal(t, int64(2), page.Items[0].NumChangelogEntries)
+	assert.False(t, page.Items[0].CreatedAt.IsZero())
+	assert.False(t, page.Items[0].LastUploadAt.IsZero())
+
+	assert.Equal(t, domain.SyncClientID("client_b"), page.Items[1].ID)
+	assert.Equal(t, int64(0), page.Items[1].NumChangelogEntries)
+	assert.True(t, page.Items[1].LastUploadAt.IsZero())
+
+	page, err = setup.repo.ListSyncClients(ctx, setup.accountID, domain.ListSyncClientsQuery{PageSize: 2, PageAfter: page.Next})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, domain.SyncClientID("client_c"), page.Items[0].ID)
+	assert.Equal(t, int64(1), page.Items[0].NumChangelogEntries)
+}
+
+type syncRepoTestSetup struct {
+	accountID              domain.AccountID
+	repo                   *SyncRepo
+	createChangelogEntries func(ctx context.Context, numEntries int)
+}
+
+func setupSyncRepo(ctx context.Context, t *testing.T) syncRepoTestSetup {
+	t.Helper()
+	db := newTestDB(ctx, t)
+
+	accountRepo := NewAccountRepo(db)
+
+	err := accountRepo.Create(ctx, &domain.Account{Username: t.Name(), Password: domain.AccountPassword{Password: []byte("1234"), Salt: []byte("1234")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountID := domain.AccountID(1)
+
+	repo := NewSyncRepo(db)
+
+	return syncRepoTestSetup{
+		accountID: accountID,
+		repo:      repo,
+		createChangelogEntries: func(ctx context.Context, numEntries int) {
+			entries := make([]domain.ChangelogEntry, numEntries)
+			for i := range numEntries {
+				entries[i] = domain.ChangelogEntry{
+					AccountID:    accountID,
+					SyncClientID: domain.SyncClientID(t.Name()),
+					Data:         fmt.Appendf(nil, "entry_%d", i),
+					Timestamp:    time.Now(),
+				}
+			}
+
+			err := repo.CreateChangelogEntries(ctx, entries)
+			require.NoError(t, err)
+		},
+	}
+}
@@ -0,0 +1,153 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite/sqlc"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite/types"
+)
+
+type TOTPRepo struct {
+	db database.Database
+}
+
+func NewTOTPRepo(db database.Database) *TOTPRepo {
+	return &TOTPRepo{db}
+}
+
+func (r *TOTPRepo) GetAccountTOTP(ctx context.Context, accountID domain.AccountID) (*domain.AccountTOTP, error) {
+	row, err := queries.GetAccountTOTP(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: account %d", domain.ErrAccountTOTPNotFound, accountID)
+		}
+
+		return nil, fmt.Errorf("error getting totp of account %d: %w", accountID, err)
+	}
+
+	return &domain.AccountTOTP{
+		AccountID:    row.AccountID,
+		Secret:       row.Secret,
+		LastUsedStep: row.LastUsedStep,
+		EnabledAt:    row.EnabledAt.Time,
+		CreatedAt:    row.CreatedAt.Time,
+	}, nil
+}
+
+// UpsertAccountTOTP replaces any previous secret of the account, the new secret needs to be confirmed before it's
+// enabled.
+func (r *TOTPRepo) UpsertAccountTOTP(ctx context.Context, totp *domain.AccountTOTP) error {
+	err := queries.UpsertAccountTOTP(ctx, r.db.Conn(ctx), sqlc.UpsertAccountTOTPParams{
+		AccountID: totp.AccountID,
+		Secret:    totp.Secret,
+	})
+	if err != nil {
+		return fmt.Errorf("error saving totp of account %d: %w", totp.AccountID, err)
+	}
+
+	return nil
+}
+
+func (r *TOTPRepo) EnableAccountTOTP(ctx context.Context, accountID domain.AccountID, step int64, enabledAt time.Time) error {
+	err := queries.EnableAccountTOTP(ctx, r.db.Conn(ctx), sqlc.EnableAccountTOTPParams{
+		EnabledAt:    types.NewSQLiteDatetime(enabledAt.UTC()),
+		LastUsedStep: step,
+		AccountID:    accountID,
+	})
+	if err != nil {
+		return fmt.Errorf("error enabling totp of account %d: %w", accountID, err)
+	}
+
+	return nil
+}
+
+// UseAccountTOTPStep records the time step of an accepted code. It returns false if the step, or a later one, has
+// already been used.
+func (r *TOTPRepo) UseAccountTOTPStep(ctx context.Context, accountID domain.AccountID, step int64) (bool, error) {
+	updated, err := queries.UpdateAccountTOTPLastUsedStep(ctx, r.db.Conn(ctx), sqlc.UpdateAccountTOTPLastUsedStepParams{
+		Step:      step,
+		AccountID: accountID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error updating last used totp step of account %d: %w", accountID, err)
+	}
+
+	return updated == 1, nil
+}
+
+// DeleteAccountTOTP deletes the secret and the recovery codes of the account. It should be called in a transaction.
+func (r *TOTPRepo) DeleteAccountTOTP(ctx context.Context, accountID domain.AccountID) error {
+	err := queries.DeleteAccountRecoveryCodes(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		return fmt.Errorf("error deleting recovery codes of account %d: %w", accountID, err)
+	}
+
+	err = queries.DeleteAccountTOTP(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		return fmt.Errorf("error deleting totp of account %d: %w", accountID, err)
+	}
+
+	return nil
+}
+
+func (r *TOTPRepo) ListUnusedRecoveryCodes(ctx context.Context, accountID domain.AccountID) ([]*domain.AccountRecoveryCode, error) {
+	rows, err := queries.ListUnusedAccountRecoveryCodes(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing recovery codes of account %d: %w", accountID, err)
+	}
+
+	codes := make([]*domain.AccountRecoveryCode, len(rows))
+	for i, row := range rows {
+		codes[i] = &domain.AccountRecoveryCode{
+			ID:        domain.AccountRecoveryCodeID(row.ID),
+			AccountID: row.AccountID,
+			Params:    row.Params,
+			Salt:      row.Salt,
+			Code:      row.Code,
+			CreatedAt: row.CreatedAt.Time,
+		}
+	}
+
+	return codes, nil
+}
+
+// ReplaceRecoveryCodes deletes all existing recovery codes of the account. It should be called in a transaction.
+func (r *TOTPRepo) ReplaceRecoveryCodes(ctx context.Context, accountID domain.AccountID, codes []*domain.AccountRecoveryCode) error {
+	err := queries.DeleteAccountRecoveryCodes(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		return fmt.Errorf("error deleting recovery codes of account %d: %w", accountID, err)
+	}
+
+	for _, code := range codes {
+		err = queries.CreateAccountRecoveryCode(ctx, r.db.Conn(ctx), sqlc.CreateAccountRecoveryCodeParams{
+			AccountID: accountID,
+			Params:    code.Params,
+			Salt:      code.Salt,
+			Code:      code.Code,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating recovery code of account %d: %w", accountID, err)
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks the code as used. It returns false if the code has already been used.
+func (r *TOTPRepo) UseRecoveryCode(ctx context.Context, id domain.AccountRecoveryCodeID) (bool, error) {
+	updated, err := queries.MarkAccountRecoveryCodeUsed(ctx, r.db.Conn(ctx), sqlc.MarkAccountRecoveryCodeUsedParams{
+		UsedAt: types.NewSQLiteDatetime(time.Now().UTC()),
+		ID:     int64(id),
+	})
+	if err != nil {
+		return false, fmt.Errorf("error marking recovery code %d as used: %w", id, err)
+	}
+
+	return updated == 1, nil
+}
@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite/sqlc"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite/types"
+)
+
+type UploadRepo struct {
+	db database.Database
+}
+
+func NewUploadRepo(db database.Database) *UploadRepo {
+	return &UploadRepo{db}
+}
+
+func (r *UploadRepo) GetUpload(ctx context.Context, accountID domain.AccountID, id domain.UploadID) (*domain.Upload, error) {
+	row, err := queries.GetUpload(ctx, r.db.Conn(ctx), sqlc.GetUploadParams{
+		AccountID: accountID,
+		PublicID:  id,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUploadNotFound
+		}
+
+		return nil, err
+	}
+
+	return mapUploadToDomain(row), nil
+}
+
+func (r *UploadRepo) CreateUpload(ctx context.Context, upload *domain.Upload) error {
+	err := queries.CreateUpload(ctx, r.db.Conn(ctx), sqlc.CreateUploadParams{
+		PublicID:  upload.ID,
+		AccountID: upload.AccountID,
+		Kind:      string(upload.Kind),
+		Filepath:  upload.Filepath,
+		Sha256:    upload.Sha256,
+		SizeBytes: upload.SizeBytes,
+		ExpiresAt: types.NewSQLiteDatetime(upload.ExpiresAt),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating upload: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateUploadOffset sets the upload's offset to upload.OffsetBytes, but only if the stored offset still equals prevOffsetBytes.
+// Returns [domain.ErrUploadOffsetMismatch] otherwise, e.g. when another request appended a chunk concurrently.
+func (r *UploadRepo) UpdateUploadOffset(ctx context.Context, upload *domain.Upload, prevOffsetBytes int64) error {
+	numRows, err := queries.UpdateUploadOffset(ctx, r.db.Conn(ctx), sqlc.UpdateUploadOffsetParams{
+		OffsetBytes:     upload.OffsetBytes,
+		UpdatedAt:       types.NewSQLiteDatetime(time.Now()),
+		AccountID:       upload.AccountID,
+		PublicID:        upload.ID,
+		PrevOffsetBytes: prevOffsetBytes,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating upload offset: %w", err)
+	}
+
+	if numRows == 0 {
+		return domain.ErrUploadOffsetMismatch
+	}
+
+	return nil
+}
+
+func (r *UploadRepo) ListExpiredUploads(ctx context.Context, accountID domain.AccountID, now time.Time) ([]*domain.Upload, error) {
+	rows, err := queries.ListExpiredUploads(ctx, r.db.Conn(ctx), sqlc.ListExpiredUploadsParams{
+		AccountID: accountID,
+		ExpiresAt: types.NewSQLiteDatetime(now),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing expired uploads: %w", err)
+	}
+
+	uploads := make([]*domain.Upload, 0, len(rows))
+	for _, row := range rows {
+		uploads = append(uploads, mapUploadToDomain(row))
+	}
+
+	return uploads, nil
+}
+
+func (r *UploadRepo) DeleteUpload(ctx context.Context, upload *domain.Upload) error {
+	err := queries.DeleteUpload(ctx, r.db.Conn(ctx), sqlc.DeleteUploadParams{
+		AccountID: upload.AccountID,
+		PublicID:  upload.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting upload: %w", err)
+	}
+
+	return nil
+}
+
+func mapUploadToDomain(row sqlc.Upload) *domain.Upload {
+	return &domain.Upload{
+		ID:          row.PublicID,
+		AccountID:   row.AccountID,
+		Kind:        domain.UploadKind(row.Kind),
+		Filepath:    row.Filepath,
+		Sha256:      row.Sha256,
+		SizeBytes:   row.SizeBytes,
+		OffsetBytes: row.OffsetBytes,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+		ExpiresAt:   row.ExpiresAt.Time,
+	}
+}
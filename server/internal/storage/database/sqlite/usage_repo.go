@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage/database"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite/sqlc"
+	"go.robinthrift.com/conveyor/internal/storage/database/sqlite/types"
+)
+
+type UsageRepo struct {
+	db database.Database
+}
+
+func NewUsageRepo(db database.Database) *UsageRepo {
+	return &UsageRepo{db}
+}
+
+// GetAccountUsage returns the current usage of the account. Accounts that haven't stored anything yet have a usage of zero.
+func (r *UsageRepo) GetAccountUsage(ctx context.Context, accountID domain.AccountID) (*domain.AccountUsage, error) {
+	row, err := queries.GetAccountUsage(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &domain.AccountUsage{AccountID: accountID}, nil
+		}
+
+		return nil, err
+	}
+
+	return &domain.AccountUsage{
+		AccountID:  row.AccountID,
+		SizeBytes:  row.SizeBytes,
+		NumEntries: row.NumEntries,
+		UpdatedAt:  row.UpdatedAt.Time,
+	}, nil
+}
+
+// AddAccountUsage adds the (possibly negative) values to the account's current usage, which never drops below zero.
+func (r *UsageRepo) AddAccountUsage(ctx context.Context, usage *domain.AccountUsage) error {
+	updatedAt := usage.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	err := queries.AddAccountUsage(ctx, r.db.Conn(ctx), sqlc.AddAccountUsageParams{
+		AccountID:  usage.AccountID,
+		SizeBytes:  usage.SizeBytes,
+		NumEntries: usage.NumEntries,
+		UpdatedAt:  types.NewSQLiteDatetime(updatedAt),
+	})
+	if err != nil {
+		return fmt.Errorf("error updating account usage: %w", err)
+	}
+
+	return nil
+}
+
+// CalculateAccountUsage sums up the usage recorded in the database, i.e. the changelog entries and the sizes reserved for
+// pending uploads. Blobs aren't included, as they are only known to the blob storage.
+func (r *UsageRepo) CalculateAccountUsage(ctx context.Context, accountID domain.AccountID) (*domain.AccountUsage, error) {
+	row, err := queries.CalculateAccountUsage(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error calculating account usage: %w", err)
+	}
+
+	return &domain.AccountUsage{
+		AccountID:  accountID,
+		SizeBytes:  row.SizeBytes,
+		NumEntries: row.NumEntries,
+	}, nil
+}
+
+// SetAccountUsage replaces the account's current usage.
+func (r *UsageRepo) SetAccountUsage(ctx context.Context, usage *domain.AccountUsage) error {
+	updatedAt := usage.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	err := queries.SetAccountUsage(ctx, r.db.Conn(ctx), sqlc.SetAccountUsageParams{
+		AccountID:  usage.AccountID,
+		SizeBytes:  usage.SizeBytes,
+		NumEntries: usage.NumEntries,
+		UpdatedAt:  types.NewSQLiteDatetime(updatedAt),
+	})
+	if err != nil {
+		return fmt.Errorf("error setting account usage: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UsageRepo) GetAccountQuotaOverride(ctx context.Context, accountID domain.AccountID) (*domain.AccountQuotaOverride, error) {
+	row, err := queries.GetAccountQuotaOverride(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrAccountQuotaOverrideNotFound
+		}
+
+		return nil, err
+	}
+
+	override := &domain.AccountQuotaOverride{AccountID: row.AccountID}
+
+	if row.MaxSizeBytes.Valid {
+		override.MaxSizeBytes = &row.MaxSizeBytes.Int64
+	}
+
+	if row.MaxEntries.Valid {
+		override.MaxEntries = &row.MaxEntries.Int64
+	}
+
+	return override, nil
+}
+
+func (r *UsageRepo) UpsertAccountQuotaOverride(ctx context.Context, override *domain.AccountQuotaOverride) error {
+	params := sqlc.UpsertAccountQuotaOverrideParams{AccountID: override.AccountID}
+
+	if override.MaxSizeBytes != nil {
+		params.MaxSizeBytes = sql.NullInt64{Int64: *override.MaxSizeBytes, Valid: true}
+	}
+
+	if override.MaxEntries != nil {
+		params.MaxEntries = sql.NullInt64{Int64: *override.MaxEntries, Valid: true}
+	}
+
+	err := queries.UpsertAccountQuotaOverride(ctx, r.db.Conn(ctx), params)
+	if err != nil {
+		return fmt.Errorf("error saving account quota override: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UsageRepo) DeleteAccountQuotaOverride(ctx context.Context, accountID domain.AccountID) error {
+	err := queries.DeleteAccountQuotaOverride(ctx, r.db.Conn(ctx), accountID)
+	if err != nil {
+		return fmt.Errorf("error deleting account quota override: %w", err)
+	}
+
+	return nil
+}
@@ -1,11 +1,14 @@
 package filesystem
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path"
+	"strings"
 
 	"go.robinthrift.com/conveyor/internal/domain"
 	"go.robinthrift.com/conveyor/internal/storage"
@@ -18,8 +21,8 @@ type LocalFSBlobStorage struct {
 	TmpDir  string
 }
 
-func (lfs *LocalFSBlobStorage) WriteBlob(accountID domain.AccountID, filepath string, data io.Reader) (n int64, err error) {
-	target, err := lfs.OpenBlobTarget(accountID, filepath)
+func (lfs *LocalFSBlobStorage) WriteBlob(ctx context.Context, accountID domain.AccountID, filepath string, data io.Reader) (n int64, err error) {
+	target, err := lfs.OpenBlobTarget(ctx, accountID, filepath)
 	if err != nil {
 		return 0, err
 	}
@@ -33,7 +36,7 @@ func (lfs *LocalFSBlobStorage) WriteBlob(accountID domain.AccountID, filepath st
 		return 0, err
 	}
 
-	err = target.Finalize(filepath)
+	err = target.Finalize(ctx, filepath)
 	if err != nil {
 		return 0, err
 	}
@@ -41,7 +44,7 @@ func (lfs *LocalFSBlobStorage) WriteBlob(accountID domain.AccountID, filepath st
 	return n, nil
 }
 
-func (lfs *LocalFSBlobStorage) OpenBlobTarget(accountID domain.AccountID, originalFilename string) (storage.BlobTarget, error) {
+func (lfs *LocalFSBlobStorage) OpenBlobTarget(_ context.Context, accountID domain.AccountID, originalFilename string) (storage.BlobTarget, error) {
 	fhandle, err := os.CreateTemp(lfs.TmpDir, fmt.Sprintf("%d_%s-*", accountID, path.Base(originalFilename)))
 	if err != nil {
 		return nil, fmt.Errorf("error creating temporary file: %w", err)
@@ -53,7 +56,47 @@ func (lfs *LocalFSBlobStorage) OpenBlobTarget(accountID domain.AccountID, origin
 	}, nil
 }
 
-func (lfs *LocalFSBlobStorage) RemoveBlob(accountID domain.AccountID, filepath string) error {
+func (lfs *LocalFSBlobStorage) OpenBlob(_ context.Context, accountID domain.AccountID, filepath string) (io.ReadCloser, error) {
+	return os.Open(path.Join(lfs.BaseDir, fmt.Sprint(accountID), filepath))
+}
+
+func (lfs *LocalFSBlobStorage) StatBlob(_ context.Context, accountID domain.AccountID, filepath string) (*storage.BlobInfo, error) {
+	stat, err := os.Stat(path.Join(lfs.BaseDir, fmt.Sprint(accountID), filepath))
+	if err != nil {
+		return nil, err
+	}
+
+	if stat.IsDir() {
+		return nil, &fs.PathError{Op: "stat", Path: filepath, Err: fs.ErrNotExist}
+	}
+
+	return &storage.BlobInfo{
+		SizeBytes: stat.Size(),
+		ModTime:   stat.ModTime(),
+		ETag:      fmt.Sprintf(`"%x-%x"`, stat.ModTime().UnixNano(), stat.Size()),
+	}, nil
+}
+
+func (lfs *LocalFSBlobStorage) OpenBlobRange(_ context.Context, accountID domain.AccountID, filepath string, offset int64, length int64) (io.ReadCloser, error) {
+	f, err := os.Open(path.Join(lfs.BaseDir, fmt.Sprint(accountID), filepath))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = f.Seek(offset, io.SeekStart)
+	if err != nil {
+		return nil, errors.Join(err, f.Close())
+	}
+
+	return &limitedReadCloser{Reader: io.LimitReader(f, length), Closer: f}, nil
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (lfs *LocalFSBlobStorage) RemoveBlob(_ context.Context, accountID domain.AccountID, filepath string) error {
 	fullFilepath := path.Join(lfs.BaseDir, fmt.Sprint(accountID), filepath)
 
 	err := os.Remove(fullFilepath)
@@ -61,8 +104,11 @@ func (lfs *LocalFSBlobStorage) RemoveBlob(accountID domain.AccountID, filepath s
 		return err
 	}
 
+	// prune the directories left empty, but never the account's base directory itself
+	accountDir := path.Join(lfs.BaseDir, fmt.Sprint(accountID))
+
 	dir := path.Dir(fullFilepath)
-	for dir == "." || dir == "" || dir == "/" {
+	for strings.HasPrefix(dir, accountDir+"/") {
 		isEmpty, err := isEmptyDir(dir)
 		if err != nil {
 			return err
@@ -72,7 +118,7 @@ func (lfs *LocalFSBlobStorage) RemoveBlob(accountID domain.AccountID, filepath s
 			return nil
 		}
 
-		err = os.RemoveAll(dir)
+		err = os.Remove(dir)
 		if err != nil {
 			return err
 		}
@@ -83,6 +129,46 @@ func (lfs *LocalFSBlobStorage) RemoveBlob(accountID domain.AccountID, filepath s
 	return nil
 }
 
+// WalkAccountBlobs calls fn for each blob stored for the account, in lexical order of the filepaths.
+func (lfs *LocalFSBlobStorage) WalkAccountBlobs(ctx context.Context, accountID domain.AccountID, fn func(filepath string, info *storage.BlobInfo) error) error {
+	accountDir := path.Join(lfs.BaseDir, fmt.Sprint(accountID))
+
+	_, err := os.Stat(accountDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	return fs.WalkDir(os.DirFS(accountDir), ".", func(filepath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		stat, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		return fn(filepath, &storage.BlobInfo{SizeBytes: stat.Size(), ModTime: stat.ModTime()})
+	})
+}
+
+// RemoveAccountBlobs removes the account's base directory including all blobs stored for the account.
+func (lfs *LocalFSBlobStorage) RemoveAccountBlobs(_ context.Context, accountID domain.AccountID) error {
+	return os.RemoveAll(path.Join(lfs.BaseDir, fmt.Sprint(accountID)))
+}
+
 type BlobTarget struct {
 	f       *os.File
 	baseDir string
@@ -100,7 +186,7 @@ func (bt *BlobTarget) Close() error {
 	return nil
 }
 
-func (bt *BlobTarget) Finalize(filepath string) (err error) {
+func (bt *BlobTarget) Finalize(_ context.Context, filepath string) (err error) {
 	finalFilePath := path.Join(bt.baseDir, filepath)
 
 	err = ensureDirExists(path.Dir(finalFilePath))
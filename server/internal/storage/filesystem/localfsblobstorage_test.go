@@ -2,6 +2,9 @@ package filesystem
 
 import (
 	"fmt"
+	"io"
+	iofs "io/fs"
+	"os"
 	"path"
 	"strings"
 	"testing"
@@ -9,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage"
 )
 
 func TestLocalFSBlobStorage(t *testing.T) {
@@ -37,9 +41,114 @@ func TestLocalFSBlobStorage(t *testing.T) {
 	}
 
 	for _, tt := range tt {
-		sizeBytes, err := fs.WriteBlob(tt.accountID, tt.filename, strings.NewReader(tt.data))
+		sizeBytes, err := fs.WriteBlob(t.Context(), tt.accountID, tt.filename, strings.NewReader(tt.data))
 		require.NoError(t, err)
 		assert.Equal(t, tt.size, sizeBytes)
 		assert.FileExists(t, path.Join(fs.BaseDir, fmt.Sprint(tt.accountID), tt.filename))
 	}
 }
+
+func TestLocalFSBlobStorage_RemoveBlob(t *testing.T) {
+	t.Parallel()
+
+	fs := LocalFSBlobStorage{BaseDir: t.TempDir(), TmpDir: t.TempDir()}
+	accountID := domain.AccountID(1000)
+	accountDir := path.Join(fs.BaseDir, fmt.Sprint(accountID))
+
+	_, err := fs.WriteBlob(t.Context(), accountID, "a/b/c/d/test-0.txt", strings.NewReader("content for test-0.txt"))
+	require.NoError(t, err)
+
+	_, err = fs.WriteBlob(t.Context(), accountID, "a/b/e/f/test-1.txt", strings.NewReader("content for test-1.txt"))
+	require.NoError(t, err)
+
+	err = fs.RemoveBlob(t.Context(), accountID, "a/b/c/d/test-0.txt")
+	require.NoError(t, err)
+	assert.NoDirExists(t, path.Join(accountDir, "a/b/c"))
+	assert.DirExists(t, path.Join(accountDir, "a/b"))
+
+	err = fs.RemoveBlob(t.Context(), accountID, "a/b/e/f/test-1.txt")
+	require.NoError(t, err)
+	assert.NoDirExists(t, path.Join(accountDir, "a"))
+	assert.DirExists(t, accountDir)
+
+	err = fs.RemoveBlob(t.Context(), accountID, "a/b/e/f/test-1.txt")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLocalFSBlobStorage_RemoveAccountBlobs(t *testing.T) {
+	t.Parallel()
+
+	fs := LocalFSBlobStorage{BaseDir: t.TempDir(), TmpDir: t.TempDir()}
+
+	_, err := fs.WriteBlob(t.Context(), domain.AccountID(1000), "a/b/test-0.txt", strings.NewReader("content for test-0.txt"))
+	require.NoError(t, err)
+
+	_, err = fs.WriteBlob(t.Context(), domain.AccountID(1001), "test-1.txt", strings.NewReader("content for test-1.txt"))
+	require.NoError(t, err)
+
+	err = fs.RemoveAccountBlobs(t.Context(), domain.AccountID(1000))
+	require.NoError(t, err)
+	assert.NoDirExists(t, path.Join(fs.BaseDir, "1000"))
+	assert.FileExists(t, path.Join(fs.BaseDir, "1001", "test-1.txt"))
+
+	err = fs.RemoveAccountBlobs(t.Context(), domain.AccountID(1000))
+	require.NoError(t, err)
+}
+
+func TestLocalFSBlobStorage_WalkAccountBlobs(t *testing.T) {
+	t.Parallel()
+
+	fs := LocalFSBlobStorage{BaseDir: t.TempDir(), TmpDir: t.TempDir()}
+
+	for _, filepath := range []string{"a/test-0.txt", "a/b/test-1.txt", "test-2.txt"} {
+		_, err := fs.WriteBlob(t.Context(), domain.AccountID(1000), filepath, strings.NewReader("content for "+filepath))
+		require.NoError(t, err)
+	}
+
+	_, err := fs.WriteBlob(t.Context(), domain.AccountID(1001), "test-3.txt", strings.NewReader("content for test-3.txt"))
+	require.NoError(t, err)
+
+	walked := map[string]int64{}
+	err = fs.WalkAccountBlobs(t.Context(), domain.AccountID(1000), func(filepath string, info *storage.BlobInfo) error {
+		walked[filepath] = info.SizeBytes
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a/test-0.txt": 24, "a/b/test-1.txt": 26, "test-2.txt": 22}, walked)
+
+	err = fs.WalkAccountBlobs(t.Context(), domain.AccountID(1002), func(string, *storage.BlobInfo) error {
+		t.Error("unexpected blob")
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestLocalFSBlobStorage_BlobReader(t *testing.T) {
+	t.Parallel()
+
+	fs := LocalFSBlobStorage{BaseDir: t.TempDir(), TmpDir: t.TempDir()}
+	accountID := domain.AccountID(1000)
+
+	_, err := fs.WriteBlob(t.Context(), accountID, "a/b/test-0.txt", strings.NewReader("content for test-0.txt"))
+	require.NoError(t, err)
+
+	info, err := fs.StatBlob(t.Context(), accountID, "a/b/test-0.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(22), info.SizeBytes)
+	assert.NotEmpty(t, info.ETag)
+
+	blob, err := fs.OpenBlobRange(t.Context(), accountID, "a/b/test-0.txt", 12, 6)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { blob.Close() })
+
+	content, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	assert.Equal(t, "test-0", string(content))
+
+	_, err = fs.StatBlob(t.Context(), accountID, "a/b")
+	require.ErrorIs(t, err, iofs.ErrNotExist)
+
+	_, err = fs.StatBlob(t.Context(), accountID, "a/b/unknown.txt")
+	require.ErrorIs(t, err, iofs.ErrNotExist)
+}
@@ -0,0 +1,563 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.robinthrift.com/conveyor/internal/domain"
+	"go.robinthrift.com/conveyor/internal/storage"
+)
+
+var ErrUnexpectedResponse = errors.New("unexpected S3 response")
+
+const (
+	defaultPartSize      = 16 << 20
+	defaultPresignExpiry = 15 * time.Minute
+)
+
+// S3BlobStorage stores blobs in an S3-compatible object storage bucket, using the same
+// `<account id>/<filepath>` layout as [filesystem.LocalFSBlobStorage] below the optional Prefix.
+type S3BlobStorage struct {
+	// Endpoint is the base URL of the S3 API, e.g. https://s3.eu-central-1.amazonaws.com.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// UsePathStyle addresses the bucket as part of the path instead of as a subdomain of the endpoint, which is required by most self-hosted S3 implementations.
+	UsePathStyle bool
+	// PartSize is the size of each part of a multipart upload, S3 requires at least 5MiB. Blobs smaller than that are uploaded with a single request.
+	PartSize      int64
+	PresignExpiry time.Duration
+	TmpDir        string
+	HTTPClient    *http.Client
+
+	now func() time.Time
+}
+
+func (s3 *S3BlobStorage) WriteBlob(ctx context.Context, accountID domain.AccountID, filepath string, data io.Reader) (n int64, err error) {
+	target, err := s3.OpenBlobTarget(ctx, accountID, filepath)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		err = errors.Join(err, target.Close())
+	}()
+
+	n, err = io.Copy(target, data)
+	if err != nil {
+		return 0, err
+	}
+
+	err = target.Finalize(ctx, filepath)
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// OpenBlobTarget spools the written data to a temporary file, as the final filepath is only known once all data has been written.
+// The data is uploaded when the target is finalized.
+func (s3 *S3BlobStorage) OpenBlobTarget(_ context.Context, accountID domain.AccountID, originalFilename string) (storage.BlobTarget, error) {
+	fhandle, err := os.CreateTemp(s3.TmpDir, fmt.Sprintf("%d_%s-*", accountID, path.Base(originalFilename)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary file: %w", err)
+	}
+
+	return &BlobTarget{
+		f:         fhandle,
+		s3:        s3,
+		accountID: accountID,
+	}, nil
+}
+
+func (s3 *S3BlobStorage) OpenBlob(ctx context.Context, accountID domain.AccountID, filepath string) (io.ReadCloser, error) {
+	res, err := s3.do(ctx, http.MethodGet, s3.objectKey(accountID, filepath), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, s3.responseError(res, filepath)
+	}
+
+	return res.Body, nil
+}
+
+func (s3 *S3BlobStorage) StatBlob(ctx context.Context, accountID domain.AccountID, filepath string) (*storage.BlobInfo, error) {
+	res, err := s3.do(ctx, http.MethodHead, s3.objectKey(accountID, filepath), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, s3.responseError(res, filepath)
+	}
+
+	modTime, _ := http.ParseTime(res.Header.Get("Last-Modified")) //nolint:errcheck // modification time is optional
+
+	return &storage.BlobInfo{
+		SizeBytes: res.ContentLength,
+		ModTime:   modTime,
+		ETag:      res.Header.Get("ETag"),
+	}, nil
+}
+
+func (s3 *S3BlobStorage) OpenBlobRange(ctx context.Context, accountID domain.AccountID, filepath string, offset int64, length int64) (io.ReadCloser, error) {
+	if length <= 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	header := http.Header{"Range": {fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)}}
+
+	res, err := s3.do(ctx, http.MethodGet, s3.objectKey(accountID, filepath), nil, header, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusPartialContent {
+		defer res.Body.Close()
+		return nil, s3.responseError(res, filepath)
+	}
+
+	return res.Body, nil
+}
+
+// RemoveBlob deletes the object. As S3 doesn't report whether the deleted object existed, its existence is checked beforehand
+// so a missing blob results in an [fs.ErrNotExist] error, like it does for [filesystem.LocalFSBlobStorage].
+func (s3 *S3BlobStorage) RemoveBlob(ctx context.Context, accountID domain.AccountID, filepath string) error {
+	key := s3.objectKey(accountID, filepath)
+
+	res, err := s3.do(ctx, http.MethodHead, key, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return s3.responseError(res, filepath)
+	}
+
+	res, err = s3.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK {
+		return s3.responseError(res, filepath)
+	}
+
+	return nil
+}
+
+// RemoveAccountBlobs deletes all objects stored for the account, listing them page by page.
+func (s3 *S3BlobStorage) RemoveAccountBlobs(ctx context.Context, accountID domain.AccountID) error {
+	prefix := s3.objectKey(accountID, "") + "/"
+
+	var continuationToken string
+
+	for {
+		objects, next, err := s3.listObjects(ctx, prefix, continuationToken)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range objects {
+			res, err := s3.do(ctx, http.MethodDelete, obj.Key, nil, nil, nil)
+			if err != nil {
+				return err
+			}
+
+			res.Body.Close()
+
+			if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK {
+				return s3.responseError(res, obj.Key)
+			}
+		}
+
+		if next == "" {
+			return nil
+		}
+
+		continuationToken = next
+	}
+}
+
+// WalkAccountBlobs calls fn for each object stored for the account, in lexical order of the keys, listing them page by page.
+func (s3 *S3BlobStorage) WalkAccountBlobs(ctx context.Context, accountID domain.AccountID, fn func(filepath string, info *storage.BlobInfo) error) error {
+	prefix := s3.objectKey(accountID, "") + "/"
+
+	var continuationToken string
+
+	for {
+		objects, next, err := s3.listObjects(ctx, prefix, continuationToken)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range objects {
+			err = fn(strings.TrimPrefix(obj.Key, prefix), &storage.BlobInfo{SizeBytes: obj.Size, ETag: obj.ETag})
+			if err != nil {
+				return err
+			}
+		}
+
+		if next == "" {
+			return nil
+		}
+
+		continuationToken = next
+	}
+}
+
+type listedObject struct {
+	Key  string `xml:"Key"`
+	Size int64  `xml:"Size"`
+	ETag string `xml:"ETag"`
+}
+
+func (s3 *S3BlobStorage) listObjects(ctx context.Context, prefix string, continuationToken string) ([]listedObject, string, error) {
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	if continuationToken != "" {
+		query.Set("continuation-token", continuationToken)
+	}
+
+	res, err := s3.do(ctx, http.MethodGet, "", query, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", s3.responseError(res, prefix)
+	}
+
+	var result struct {
+		Contents              []listedObject `xml:"Contents"`
+		IsTruncated           bool           `xml:"IsTruncated"`
+		NextContinuationToken string         `xml:"NextContinuationToken"`
+	}
+
+	err = xml.NewDecoder(res.Body).Decode(&result)
+	if err != nil {
+		return nil, "", fmt.Errorf("error decoding list objects response: %w", err)
+	}
+
+	if !result.IsTruncated {
+		return result.Contents, "", nil
+	}
+
+	return result.Contents, result.NextContinuationToken, nil
+}
+
+// PresignGetURL returns a URL that can be used to download the blob without further authentication, until PresignExpiry has elapsed.
+func (s3 *S3BlobStorage) PresignGetURL(accountID domain.AccountID, filepath string) string {
+	return s3.presignGetURL(s3.objectKey(accountID, filepath))
+}
+
+func (s3 *S3BlobStorage) presignGetURL(key string) string {
+	expiry := s3.PresignExpiry
+	if expiry == 0 {
+		expiry = defaultPresignExpiry
+	}
+
+	return s3.signer().presignURL(http.MethodGet, s3.objectURL(key, nil), expiry, s3.currentTime()).String()
+}
+
+func (s3 *S3BlobStorage) objectKey(accountID domain.AccountID, filepath string) string {
+	return strings.TrimPrefix(path.Join(s3.Prefix, fmt.Sprint(accountID), filepath), "/")
+}
+
+func (s3 *S3BlobStorage) objectURL(key string, query url.Values) *url.URL {
+	u, _ := url.Parse(strings.TrimSuffix(s3.Endpoint, "/")) //nolint:errcheck // validated when configured
+
+	if s3.UsePathStyle {
+		u.Path += "/" + s3.Bucket + "/" + key
+	} else {
+		u.Host = s3.Bucket + "." + u.Host
+		u.Path += "/" + key
+	}
+
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = canonicalQueryString(query)
+
+	return u
+}
+
+func (s3 *S3BlobStorage) do(ctx context.Context, method string, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s3.objectURL(key, query).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	req.ContentLength = int64(len(body))
+
+	s3.signer().signRequest(req, hashHex(body), s3.currentTime())
+
+	client := s3.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending %s request to S3: %w", method, err)
+	}
+
+	return res, nil
+}
+
+func (s3 *S3BlobStorage) signer() *signer {
+	return &signer{accessKeyID: s3.AccessKeyID, secretAccessKey: s3.SecretAccessKey, region: s3.Region}
+}
+
+func (s3 *S3BlobStorage) currentTime() time.Time {
+	if s3.now != nil {
+		return s3.now()
+	}
+
+	return time.Now()
+}
+
+func (s3 *S3BlobStorage) partSize() int64 {
+	if s3.PartSize == 0 {
+		return defaultPartSize
+	}
+
+	return s3.PartSize
+}
+
+func (s3 *S3BlobStorage) putObject(ctx context.Context, key string, data []byte) error {
+	res, err := s3.do(ctx, http.MethodPut, key, nil, nil, data)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return s3.responseError(res, key)
+	}
+
+	return nil
+}
+
+type multipartUpload struct {
+	s3       *S3BlobStorage
+	key      string
+	uploadID string
+	parts    []completedPart
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+func (s3 *S3BlobStorage) createMultipartUpload(ctx context.Context, key string) (*multipartUpload, error) {
+	res, err := s3.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, s3.responseError(res, key)
+	}
+
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+
+	err = xml.NewDecoder(res.Body).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding multipart upload: %w", err)
+	}
+
+	return &multipartUpload{s3: s3, key: key, uploadID: result.UploadID}, nil
+}
+
+func (mu *multipartUpload) uploadPart(ctx context.Context, data []byte) error {
+	partNumber := len(mu.parts) + 1
+
+	res, err := mu.s3.do(ctx, http.MethodPut, mu.key, url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {mu.uploadID}}, nil, data)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return mu.s3.responseError(res, mu.key)
+	}
+
+	mu.parts = append(mu.parts, completedPart{PartNumber: partNumber, ETag: res.Header.Get("ETag")})
+
+	return nil
+}
+
+func (mu *multipartUpload) complete(ctx context.Context) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: mu.parts})
+	if err != nil {
+		return err
+	}
+
+	res, err := mu.s3.do(ctx, http.MethodPost, mu.key, url.Values{"uploadId": {mu.uploadID}}, nil, body)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	respBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	// S3 may report errors while completing the upload with a 200 status code
+	if res.StatusCode != http.StatusOK || bytes.Contains(respBody, []byte("<Error>")) {
+		return mu.s3.responseError(&http.Response{StatusCode: res.StatusCode, Body: io.NopCloser(bytes.NewReader(respBody))}, mu.key)
+	}
+
+	return nil
+}
+
+func (mu *multipartUpload) abort(ctx context.Context) error {
+	res, err := mu.s3.do(ctx, http.MethodDelete, mu.key, url.Values{"uploadId": {mu.uploadID}}, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK {
+		return mu.s3.responseError(res, mu.key)
+	}
+
+	return nil
+}
+
+func (s3 *S3BlobStorage) responseError(res *http.Response, filepath string) error {
+	var s3Err struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+
+	_ = xml.NewDecoder(res.Body).Decode(&s3Err)
+
+	if res.StatusCode == http.StatusNotFound || s3Err.Code == "NoSuchKey" {
+		return &fs.PathError{Op: "open", Path: filepath, Err: fs.ErrNotExist}
+	}
+
+	return fmt.Errorf("%w: status %d: %s %s", ErrUnexpectedResponse, res.StatusCode, s3Err.Code, s3Err.Message)
+}
+
+type BlobTarget struct {
+	f         *os.File
+	s3        *S3BlobStorage
+	accountID domain.AccountID
+}
+
+func (bt *BlobTarget) Write(b []byte) (int, error) {
+	return bt.f.Write(b)
+}
+
+func (bt *BlobTarget) Close() error {
+	if bt.f != nil {
+		return errors.Join(bt.f.Close(), os.Remove(bt.f.Name()))
+	}
+
+	return nil
+}
+
+func (bt *BlobTarget) Finalize(ctx context.Context, filepath string) (err error) {
+	_, err = bt.f.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("error seeking temporary file: %w", err)
+	}
+
+	key := bt.s3.objectKey(bt.accountID, filepath)
+	partSize := bt.s3.partSize()
+	buf := make([]byte, partSize)
+
+	n, err := io.ReadFull(bt.f, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("error reading temporary file: %w", err)
+	}
+
+	if int64(n) < partSize {
+		err = bt.s3.putObject(ctx, key, buf[:n])
+	} else {
+		err = bt.uploadMultipart(ctx, key, buf)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error uploading data to final location: %w", err)
+	}
+
+	err = bt.Close()
+	bt.f = nil
+
+	return err
+}
+
+func (bt *BlobTarget) uploadMultipart(ctx context.Context, key string, buf []byte) (err error) {
+	upload, err := bt.s3.createMultipartUpload(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			// abort even if ctx has been cancelled, so the uploaded parts aren't left behind in the bucket
+			err = errors.Join(err, upload.abort(context.WithoutCancel(ctx)))
+		}
+	}()
+
+	// the first part has already been read into buf
+	n := len(buf)
+
+	for n > 0 {
+		err = upload.uploadPart(ctx, buf[:n])
+		if err != nil {
+			return err
+		}
+
+		n, err = io.ReadFull(bt.f, buf)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("error reading temporary file: %w", err)
+		}
+	}
+
+	return upload.complete(ctx)
+}
@@ -0,0 +1,164 @@
+package objectstorage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AWS Signature Version 4, see https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
+
+const (
+	sigV4Algorithm      = "AWS4-HMAC-SHA256"
+	sigV4DateFormat     = "20060102"
+	sigV4DatetimeFormat = "20060102T150405Z"
+	unsignedPayload     = "UNSIGNED-PAYLOAD"
+)
+
+type signer struct {
+	accessKeyID     string
+	secretAccessKey string
+	region          string
+}
+
+func (s *signer) signRequest(req *http.Request, payloadHash string, now time.Time) {
+	datetime := now.UTC().Format(sigV4DatetimeFormat)
+
+	req.Header.Set("X-Amz-Date", datetime)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + datetime + "\n"
+
+	signature := s.signature(now, req.Method, req.URL, req.URL.Query(), canonicalHeaders, signedHeaders, payloadHash)
+
+	req.Header.Set("Authorization", sigV4Algorithm+
+		" Credential="+s.accessKeyID+"/"+s.scope(now)+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+signature)
+}
+
+func (s *signer) presignURL(method string, u *url.URL, expires time.Duration, now time.Time) *url.URL {
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.accessKeyID+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.UTC().Format(sigV4DatetimeFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	signature := s.signature(now, method, u, query, "host:"+u.Host+"\n", "host", unsignedPayload)
+
+	signed := *u
+	signed.RawQuery = canonicalQueryString(query) + "&X-Amz-Signature=" + signature
+
+	return &signed
+}
+
+func (s *signer) signature(now time.Time, method string, u *url.URL, query url.Values, canonicalHeaders string, signedHeaders string, payloadHash string) string {
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI(u),
+		canonicalQueryString(query),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		now.UTC().Format(sigV4DatetimeFormat),
+		s.scope(now),
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), now.UTC().Format(sigV4DateFormat))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func (s *signer) scope(now time.Time) string {
+	return now.UTC().Format(sigV4DateFormat) + "/" + s.region + "/s3/aws4_request"
+}
+
+func canonicalURI(u *url.URL) string {
+	if u.Path == "" {
+		return "/"
+	}
+
+	return uriEncode(u.Path, false)
+}
+
+func canonicalQueryString(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var b strings.Builder
+
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+
+		for _, v := range values {
+			if b.Len() != 0 {
+				b.WriteByte('&')
+			}
+
+			b.WriteString(uriEncode(k, true))
+			b.WriteByte('=')
+			b.WriteString(uriEncode(v, true))
+		}
+	}
+
+	return b.String()
+}
+
+// uriEncode encodes every byte except the unreserved characters, as required by the canonical request.
+func uriEncode(s string, encodeSlash bool) string {
+	const hexChars = "0123456789ABCDEF"
+
+	var b strings.Builder
+
+	for i := range len(s) {
+		c := s[i]
+
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexChars[c>>4])
+			b.WriteByte(hexChars[c&0x0f])
+		}
+	}
+
+	return b.String()
+}
+
+func hashHex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
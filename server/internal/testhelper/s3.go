@@ -0,0 +1,224 @@
+package testhelper
+
+import (
+	"crypto/md5"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// S3TestServer is a minimal in-process stand-in for an S3-compatible object storage, supporting path-style
+// object uploads (including multipart uploads), downloads (including single ranges), listings and deletions for a single bucket.
+// It only checks that requests are signed, not that the signatures are valid.
+type S3TestServer struct {
+	URL    string
+	Bucket string
+	// ListPageSize is the maximum number of keys returned per listing, to test paginated listings.
+	ListPageSize int
+
+	mu       sync.Mutex
+	objects  map[string][]byte
+	uploads  map[string]map[int][]byte
+	nextID   int
+	requests []string
+}
+
+func NewS3TestServer(t *testing.T) *S3TestServer {
+	t.Helper()
+
+	s := &S3TestServer{
+		Bucket:       "conveyor-test",
+		ListPageSize: 1000,
+		objects:      map[string][]byte{},
+		uploads:      map[string]map[int][]byte{},
+	}
+
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	s.URL = srv.URL
+
+	return s
+}
+
+// Object returns the content of the object with the given key and whether it exists.
+func (s *S3TestServer) Object(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[key]
+
+	return obj, ok
+}
+
+// Requests returns the method and query of all requests received so far, e.g. "PUT partNumber=1&uploadId=1".
+func (s *S3TestServer) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func (s *S3TestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, strings.TrimSpace(r.Method+" "+r.URL.RawQuery))
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") && r.URL.Query().Get("X-Amz-Signature") == "" {
+		writeS3Error(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+s.Bucket+"/")
+	if !ok {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		s.nextID++
+		uploadID := strconv.Itoa(s.nextID)
+		s.uploads[uploadID] = map[int][]byte{}
+
+		writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Key      string   `xml:"Key"`
+			UploadID string   `xml:"UploadId"`
+		}{Key: key, UploadID: uploadID})
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		parts, ok := s.uploads[query.Get("uploadId")]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		parts[partNumber], _ = io.ReadAll(r.Body)
+
+		w.Header().Set("ETag", fmt.Sprintf("\"part-%d\"", partNumber))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		parts, ok := s.uploads[query.Get("uploadId")]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+
+		partNumbers := make([]int, 0, len(parts))
+		for n := range parts {
+			partNumbers = append(partNumbers, n)
+		}
+
+		sort.Ints(partNumbers)
+
+		var obj []byte
+		for _, n := range partNumbers {
+			obj = append(obj, parts[n]...)
+		}
+
+		s.objects[key] = obj
+		delete(s.uploads, query.Get("uploadId"))
+
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Key     string   `xml:"Key"`
+		}{Key: key})
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(s.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && key == "" && query.Get("list-type") == "2":
+		s.listObjects(w, query.Get("prefix"), query.Get("continuation-token"))
+	case r.Method == http.MethodPut:
+		s.objects[key], _ = io.ReadAll(r.Body)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		obj, ok := s.objects[key]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+
+		w.Header().Set("ETag", fmt.Sprintf("\"%x\"", md5.Sum(obj))) //nolint:gosec // S3 uses MD5 for ETags
+
+		var start, end int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err == nil && r.Method == http.MethodGet {
+			end = min(end, len(obj)-1)
+			w.Header().Set("Content-Length", strconv.Itoa(end-start+1))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(obj[start : end+1])
+
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj)))
+
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(obj)
+		}
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// listObjects uses the last returned key as the continuation token.
+func (s *S3TestServer) listObjects(w http.ResponseWriter, prefix string, continuationToken string) {
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) && key > continuationToken {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	type content struct {
+		Key  string `xml:"Key"`
+		Size int    `xml:"Size"`
+		ETag string `xml:"ETag"`
+	}
+
+	result := struct {
+		XMLName               xml.Name  `xml:"ListBucketResult"`
+		Contents              []content `xml:"Contents"`
+		IsTruncated           bool      `xml:"IsTruncated"`
+		NextContinuationToken string    `xml:"NextContinuationToken,omitempty"`
+	}{}
+
+	if len(keys) > s.ListPageSize {
+		keys = keys[:s.ListPageSize]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[len(keys)-1]
+	}
+
+	for _, key := range keys {
+		result.Contents = append(result.Contents, content{Key: key, Size: len(s.objects[key]), ETag: fmt.Sprintf("\"%x\"", md5.Sum(s.objects[key]))}) //nolint:gosec // S3 uses MD5 for ETags
+	}
+
+	writeXML(w, result)
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+	}{Code: code})
+}
+
+func writeXML(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(v)
+}
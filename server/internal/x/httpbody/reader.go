@@ -0,0 +1,152 @@
+package httpbody
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"go.robinthrift.com/conveyor/internal/x/httperrors"
+)
+
+// Limits restricts the size of request bodies, 0 means unlimited.
+type Limits struct {
+	// MaxSizeBytes is the maximum size of the body after decoding the Content-Encoding.
+	MaxSizeBytes int64
+	// MaxDecompressionRatio is the maximum ratio between the decoded and the encoded size of a compressed body.
+	MaxDecompressionRatio int64
+}
+
+// small bodies can compress extremely well without being malicious, so the ratio is only checked once this many bytes were decoded
+const minRatioCheckSizeBytes = 1 << 20
+
+// NewReader decodes the body according to the Content-Encoding and enforces the limits while the body is read,
+// so oversized uploads and decompression bombs are aborted without being buffered or stored first.
+// Violations are reported as [httperrors.ErrPayloadTooLarge], unknown encodings as [httperrors.ErrUnsupportedMediaType].
+func NewReader(body io.Reader, contentEncoding *string, limits Limits) (io.ReadCloser, error) {
+	encoding := ""
+	if contentEncoding != nil {
+		encoding = strings.ToLower(strings.TrimSpace(*contentEncoding))
+	}
+
+	switch encoding {
+	case "", "identity":
+		return io.NopCloser(LimitReader(body, limits.MaxSizeBytes)), nil
+	case "gzip":
+		compressed := &countingReader{r: body}
+
+		gr, err := gzip.NewReader(compressed)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid gzip data: %w", httperrors.ErrBadRequest, err)
+		}
+
+		return &gzipReader{
+			gr:         gr,
+			decoded:    LimitReader(gr, limits.MaxSizeBytes),
+			compressed: compressed,
+			maxRatio:   limits.MaxDecompressionRatio,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported Content-Encoding %s", httperrors.ErrUnsupportedMediaType, encoding)
+	}
+}
+
+// LimitReader returns a reader that fails with [httperrors.ErrPayloadTooLarge] once more than maxSizeBytes are read.
+func LimitReader(r io.Reader, maxSizeBytes int64) io.Reader {
+	if maxSizeBytes <= 0 {
+		return r
+	}
+
+	return LimitReaderWithError(r, maxSizeBytes, fmt.Errorf("%w: body exceeds the limit of %d bytes", httperrors.ErrPayloadTooLarge, maxSizeBytes))
+}
+
+// LimitReaderWithError returns a reader that fails with err once more than maxSizeBytes are read.
+// Unlike [LimitReader], a limit of 0 doesn't allow any bytes.
+func LimitReaderWithError(r io.Reader, maxSizeBytes int64, err error) io.Reader {
+	return &limitedReader{r: r, remaining: maxSizeBytes, err: err}
+}
+
+// CheckContentType returns [httperrors.ErrUnsupportedMediaType] unless the media type matches one of the allowed types.
+// Allowed types may use a wildcard subtype like `image/*`. An empty allow-list allows all types.
+func CheckContentType(contentType string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: invalid Content-Type %q", httperrors.ErrUnsupportedMediaType, contentType)
+	}
+
+	for _, a := range allowed {
+		a = strings.ToLower(strings.TrimSpace(a))
+
+		if a == mediaType || a == "*/*" {
+			return nil
+		}
+
+		if prefix, ok := strings.CutSuffix(a, "*"); ok && strings.HasSuffix(prefix, "/") && strings.HasPrefix(mediaType, prefix) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: Content-Type %s is not allowed", httperrors.ErrUnsupportedMediaType, mediaType)
+}
+
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	err       error
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	// read one byte more than allowed to detect bodies exceeding the limit exactly at EOF
+	if int64(len(p)) > lr.remaining+1 {
+		p = p[:lr.remaining+1]
+	}
+
+	n, err := lr.r.Read(p)
+	lr.remaining -= int64(n)
+
+	if lr.remaining < 0 {
+		return n, lr.err
+	}
+
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+
+	return n, err
+}
+
+type gzipReader struct {
+	gr           *gzip.Reader
+	decoded      io.Reader
+	compressed   *countingReader
+	decodedBytes int64
+	maxRatio     int64
+}
+
+func (r *gzipReader) Read(p []byte) (int, error) {
+	n, err := r.decoded.Read(p)
+	r.decodedBytes += int64(n)
+
+	if r.maxRatio > 0 && r.decodedBytes > minRatioCheckSizeBytes && r.decodedBytes > r.maxRatio*r.compressed.n {
+		return n, fmt.Errorf("%w: decompression ratio exceeds the limit of %d", httperrors.ErrPayloadTooLarge, r.maxRatio)
+	}
+
+	return n, err
+}
+
+func (r *gzipReader) Close() error {
+	return r.gr.Close()
+}
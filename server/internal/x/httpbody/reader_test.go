@@ -0,0 +1,216 @@
+package httpbody_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.robinthrift.com/conveyor/internal/x/httpbody"
+	"go.robinthrift.com/conveyor/internal/x/httperrors"
+)
+
+func TestNewReader_MaxSizeBytes(t *testing.T) {
+	t.Parallel()
+
+	gzipEncoding := "gzip"
+
+	tt := []struct {
+		name     string
+		size     int
+		encoding *string
+		err      error
+	}{
+		{name: "Below Limit", size: 1023},
+		{name: "At Limit", size: 1024},
+		{name: "Above Limit", size: 1025, err: httperrors.ErrPayloadTooLarge},
+		{name: "Gzip Below Limit", size: 1023, encoding: &gzipEncoding},
+		{name: "Gzip At Limit", size: 1024, encoding: &gzipEncoding},
+		{name: "Gzip Above Limit", size: 1025, encoding: &gzipEncoding, err: httperrors.ErrPayloadTooLarge},
+	}
+
+	for _, tt := range tt {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body := bytes.Repeat([]byte("a"), tt.size)
+			if tt.encoding != nil {
+				body = gzipBytes(t, body)
+			}
+
+			r, err := httpbody.NewReader(bytes.NewReader(body), tt.encoding, httpbody.Limits{MaxSizeBytes: 1024})
+			require.NoError(t, err)
+			t.Cleanup(func() { r.Close() })
+
+			read, err := io.ReadAll(r)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, read, tt.size)
+		})
+	}
+}
+
+func TestNewReader_MaxDecompressionRatio(t *testing.T) {
+	t.Parallel()
+
+	gzipEncoding := "gzip"
+
+	// zeros compress well enough for the whole compressed body to be consumed by the first read
+	bomb := make([]byte, 2<<20)
+	compressed := gzipBytes(t, bomb)
+	ratio := int64(len(bomb)) / int64(len(compressed))
+
+	tt := []struct {
+		name     string
+		body     []byte
+		maxRatio int64
+		err      error
+	}{
+		{name: "Below Ratio", body: compressed, maxRatio: ratio + 1},
+		{name: "Above Ratio", body: compressed, maxRatio: ratio - 1, err: httperrors.ErrPayloadTooLarge},
+		{name: "At Check Threshold", body: gzipBytes(t, make([]byte, 1<<20)), maxRatio: 1},
+		{name: "Above Check Threshold", body: gzipBytes(t, make([]byte, 1<<20+1)), maxRatio: 1, err: httperrors.ErrPayloadTooLarge},
+	}
+
+	for _, tt := range tt {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := httpbody.NewReader(bytes.NewReader(tt.body), &gzipEncoding, httpbody.Limits{MaxDecompressionRatio: tt.maxRatio})
+			require.NoError(t, err)
+			t.Cleanup(func() { r.Close() })
+
+			_, err = io.Copy(io.Discard, r)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewReader_ContentEncoding(t *testing.T) {
+	t.Parallel()
+
+	for _, encoding := range []string{"", "identity", " Identity "} {
+		r, err := httpbody.NewReader(bytes.NewReader([]byte("data")), &encoding, httpbody.Limits{})
+		require.NoError(t, err, encoding)
+
+		read, err := io.ReadAll(r)
+		require.NoError(t, err, encoding)
+		assert.Equal(t, "data", string(read), encoding)
+	}
+
+	encoding := "GZIP"
+
+	r, err := httpbody.NewReader(bytes.NewReader(gzipBytes(t, []byte("data"))), &encoding, httpbody.Limits{})
+	require.NoError(t, err)
+
+	read, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(read))
+
+	encoding = "br"
+
+	_, err = httpbody.NewReader(bytes.NewReader([]byte("data")), &encoding, httpbody.Limits{})
+	require.ErrorIs(t, err, httperrors.ErrUnsupportedMediaType)
+
+	encoding = "gzip"
+
+	_, err = httpbody.NewReader(bytes.NewReader([]byte("not gzip")), &encoding, httpbody.Limits{})
+	require.ErrorIs(t, err, httperrors.ErrBadRequest)
+}
+
+func TestLimitReaderWithError(t *testing.T) {
+	t.Parallel()
+
+	errTooLarge := errors.New("too large")
+
+	tt := []struct {
+		name         string
+		size         int
+		maxSizeBytes int64
+		err          error
+	}{
+		{name: "Below Limit", size: 9, maxSizeBytes: 10},
+		{name: "At Limit", size: 10, maxSizeBytes: 10},
+		{name: "Above Limit", size: 11, maxSizeBytes: 10, err: errTooLarge},
+		{name: "Empty At Zero Limit", size: 0, maxSizeBytes: 0},
+		{name: "Above Zero Limit", size: 1, maxSizeBytes: 0, err: errTooLarge},
+	}
+
+	for _, tt := range tt {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			read, err := io.ReadAll(httpbody.LimitReaderWithError(bytes.NewReader(make([]byte, tt.size)), tt.maxSizeBytes, errTooLarge))
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, read, tt.size)
+		})
+	}
+}
+
+func TestCheckContentType(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		contentType string
+		allowed     []string
+		ok          bool
+	}{
+		{contentType: "image/png", allowed: nil, ok: true},
+		{contentType: "image/png", allowed: []string{"image/png"}, ok: true},
+		{contentType: "IMAGE/PNG", allowed: []string{"image/png"}, ok: true},
+		{contentType: "image/png", allowed: []string{" Image/PNG "}, ok: true},
+		{contentType: "text/plain; charset=utf-8", allowed: []string{"text/plain"}, ok: true},
+		{contentType: "Text/Plain; Charset=UTF-8", allowed: []string{"text/plain"}, ok: true},
+		{contentType: "image/jpeg", allowed: []string{"image/*"}, ok: true},
+		{contentType: "application/pdf", allowed: []string{"*/*"}, ok: true},
+		{contentType: "application/pdf", allowed: []string{"image/*", "text/plain"}, ok: false},
+		{contentType: "imagex/png", allowed: []string{"image/*"}, ok: false},
+		{contentType: "text/plain", allowed: []string{"text/plain+x"}, ok: false},
+		{contentType: "", allowed: []string{"text/plain"}, ok: false},
+		{contentType: "text/plain; charset", allowed: []string{"text/plain"}, ok: false},
+	}
+
+	for _, tt := range tt {
+		t.Run(tt.contentType, func(t *testing.T) {
+			t.Parallel()
+
+			err := httpbody.CheckContentType(tt.contentType, tt.allowed)
+			if tt.ok {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, httperrors.ErrUnsupportedMediaType)
+			}
+		})
+	}
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
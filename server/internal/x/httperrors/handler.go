@@ -10,10 +10,13 @@ import (
 	"runtime/debug"
 
 	"go.robinthrift.com/conveyor/internal/auth"
+	"go.robinthrift.com/conveyor/internal/domain"
 )
 
 var ErrBadRequest = errors.New("invalid request")
 var ErrNotFound = errors.New("not found")
+var ErrPayloadTooLarge = errors.New("payload too large")
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
 
 type ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
 
@@ -36,6 +39,20 @@ func ErrorHandler(prefix string) ErrorHandlerFunc {
 				Detail: err.Error(),
 				Type:   prefix + "/Unauthorized",
 			}
+		case errors.Is(err, auth.ErrMissingScope):
+			apiErr = Error{
+				Code:   http.StatusForbidden,
+				Title:  http.StatusText(http.StatusForbidden),
+				Detail: err.Error(),
+				Type:   prefix + "/MissingScope",
+			}
+		case errors.Is(err, auth.ErrForbidden):
+			apiErr = Error{
+				Code:   http.StatusForbidden,
+				Title:  http.StatusText(http.StatusForbidden),
+				Detail: err.Error(),
+				Type:   prefix + "/Forbidden",
+			}
 		case errors.Is(err, ErrNotFound):
 			apiErr = Error{
 				Code:   http.StatusNotFound,
@@ -43,6 +60,27 @@ func ErrorHandler(prefix string) ErrorHandlerFunc {
 				Detail: err.Error(),
 				Type:   prefix + "/NotFound",
 			}
+		case errors.Is(err, domain.ErrQuotaExceeded):
+			apiErr = Error{
+				Code:   http.StatusInsufficientStorage,
+				Title:  http.StatusText(http.StatusInsufficientStorage),
+				Detail: err.Error(),
+				Type:   prefix + "/QuotaExceeded",
+			}
+		case errors.Is(err, ErrPayloadTooLarge):
+			apiErr = Error{
+				Code:   http.StatusRequestEntityTooLarge,
+				Title:  http.StatusText(http.StatusRequestEntityTooLarge),
+				Detail: err.Error(),
+				Type:   prefix + "/PayloadTooLarge",
+			}
+		case errors.Is(err, ErrUnsupportedMediaType):
+			apiErr = Error{
+				Code:   http.StatusUnsupportedMediaType,
+				Title:  http.StatusText(http.StatusUnsupportedMediaType),
+				Detail: err.Error(),
+				Type:   prefix + "/UnsupportedMediaType",
+			}
 		case errors.Is(err, ErrBadRequest):
 			apiErr = Error{
 				Code:   http.StatusBadRequest,
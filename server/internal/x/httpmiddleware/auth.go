@@ -3,6 +3,8 @@ package httpmiddleware
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"slices"
 	"strings"
@@ -12,11 +14,16 @@ import (
 	"go.robinthrift.com/conveyor/internal/x/httperrors"
 )
 
+// NewAuthMiddleware authenticates the request, enforces the scopes of API tokens and records their usage. The required
+// scope of a route is looked up by its [http.Request.Pattern], requests using API tokens on routes without a required
+// scope are rejected.
 func NewAuthMiddleware(accountFetcher interface {
-	GetAccountForAuthToken(ctx context.Context, value auth.PlaintextAuthTokenValue) (*domain.Account, error)
+	GetAccountAndAPITokenForAuthToken(ctx context.Context, value auth.PlaintextAuthTokenValue) (*domain.Account, *domain.APIToken, error)
+	RecordAPITokenUsage(apiToken *domain.APIToken, ip string, userAgent string)
 },
 	errorHandler httperrors.ErrorHandlerFunc,
 	ignoreRoutes []string,
+	requiredScopes map[string]domain.APITokenScope,
 ) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -33,7 +40,7 @@ func NewAuthMiddleware(accountFetcher interface {
 				return
 			}
 
-			account, err := accountFetcher.GetAccountForAuthToken(r.Context(), *token)
+			account, apiToken, err := accountFetcher.GetAccountAndAPITokenForAuthToken(r.Context(), *token)
 			if account == nil || errors.Is(err, auth.ErrUnauthorized) {
 				errorHandler(w, r, auth.ErrUnauthorized)
 
@@ -51,11 +58,49 @@ func NewAuthMiddleware(accountFetcher interface {
 			}
 
 			ctx := auth.CtxWithAccount(r.Context(), account)
+
+			if apiToken != nil {
+				err = CheckScope(apiToken.Scopes, requiredScopes[r.Pattern])
+				if err != nil {
+					errorHandler(w, r, err)
+
+					return
+				}
+
+				accountFetcher.RecordAPITokenUsage(apiToken, RemoteIP(r), r.UserAgent())
+
+				ctx = auth.CtxWithAPITokenScopes(ctx, apiToken.Scopes)
+			}
+
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// CheckScope returns [auth.ErrMissingScope] if the required scope isn't part of the token's scopes. An empty required
+// scope is never satisfied.
+func CheckScope(scopes domain.APITokenScopes, required domain.APITokenScope) error {
+	if required == "" {
+		return fmt.Errorf("%w: route is not accessible using api tokens", auth.ErrMissingScope)
+	}
+
+	if !scopes.Has(required) {
+		return fmt.Errorf("%w: %s", auth.ErrMissingScope, required)
+	}
+
+	return nil
+}
+
+// RemoteIP returns the IP of the client without the port.
+func RemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
 const authHeader = "Authorization"
 
 func authTokenFromHeader(header http.Header) (*auth.PlaintextAuthTokenValue, bool) {